@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/gocomply/metaschema/metaschema"
+	"github.com/gocomply/metaschema/metaschema/parser"
+	"github.com/gocomply/metaschema/metaschema/validator"
 	"github.com/urfave/cli"
+	"io"
 	"os"
 )
 
@@ -11,13 +16,41 @@ func Execute() error {
 	app := cli.NewApp()
 	app.Name = "NIST Metaschema"
 	app.Usage = "This project extends metaschema beyond xml/json/yaml. This project allows users to generate golang code for processing those xml/json/yaml files out of NIST's metaschema."
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "diagnostics",
+			Value: "text",
+			Usage: "Format of metaschema errors: text (file:line:column: message) or json",
+		},
+	}
 	app.Commands = []cli.Command{
 		generate,
+		validate,
+		convert,
+		jsonSchema,
+		xmlSchema,
+		docs,
+		proto,
 	}
 
 	return app.Run(os.Args)
 }
 
+// exitError reports the error in the format chosen by --diagnostics flag and
+// exits with the code. JSON diagnostics are written to the standard output.
+func exitError(c *cli.Context, err error, code int) error {
+	if c.GlobalString("diagnostics") != "json" {
+		return cli.NewExitError(err, code)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if jsonErr := enc.Encode(parser.Diagnostics(err)); jsonErr != nil {
+		return cli.NewExitError(err, code)
+	}
+	return cli.NewExitError("", code)
+}
+
 var generate = cli.Command{
 	Name:      "generate",
 	Usage:     "Generate golang code to parse json/yaml/xml files generated by given metaschema",
@@ -32,7 +65,198 @@ var generate = cli.Command{
 		metaschemaDir, goModule, outputDir := c.Args()[0], c.Args()[1], c.Args()[2]
 		err := metaschema.Generate(metaschemaDir, goModule, outputDir)
 		if err != nil {
-			return cli.NewExitError(err, 1)
+			return exitError(c, err, 1)
+		}
+		return nil
+	},
+}
+
+var validate = cli.Command{
+	Name:  "validate",
+	Usage: "Validate xml/json/yaml documents against given metaschema",
+	Description: "Checks structure, cardinality, data types and constraints of the documents. " +
+		"Exits with status 1 when any finding is at least as severe as --fail-on level, " +
+		"and with status 2 when the metaschema or the documents cannot be loaded.",
+	ArgsUsage: "METASCHEMA-DIR DOCUMENT...",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output, o",
+			Value: "text",
+			Usage: "Output format: text, json or sarif",
+		},
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Format of the documents: xml, json or yaml. Detected from the file by default",
+		},
+		cli.StringFlag{
+			Name:  "fail-on",
+			Value: "ERROR",
+			Usage: "Lowest level of findings that fails the validation: CRITICAL, ERROR, WARNING, INFORMATIONAL or DEBUG",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		if c.NArg() < 2 {
+			return cli.NewExitError("At least 2 arguments are required", 2)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) error {
+		threshold, err := validator.ParseLevel(c.String("fail-on"))
+		if err != nil {
+			return cli.NewExitError(err, 2)
+		}
+		var write func(w io.Writer, reports []validator.Report) error
+		switch c.String("output") {
+		case "text":
+			write = validator.WriteText
+		case "json":
+			write = validator.WriteJSON
+		case "sarif":
+			write = validator.WriteSARIF
+		default:
+			return cli.NewExitError(fmt.Sprintf("Unknown output format '%s'", c.String("output")), 2)
+		}
+
+		reports, err := metaschema.Validate(c.Args()[0], c.Args()[1:], c.String("format"))
+		if err != nil {
+			return exitError(c, err, 2)
+		}
+		if err := write(os.Stdout, reports); err != nil {
+			return cli.NewExitError(err, 2)
+		}
+		for i := range reports {
+			if reports[i].Fails(threshold) {
+				return cli.NewExitError("", 1)
+			}
+		}
+		return nil
+	},
+}
+
+var convert = cli.Command{
+	Name:  "convert",
+	Usage: "Convert xml/json/yaml document defined by given metaschema to another format",
+	Description: "Reads the INPUT document and writes it in the --to format to OUTPUT, or to the standard output. " +
+		"Markup is converted between XHTML and Markdown.",
+	ArgsUsage: "METASCHEMA-DIR INPUT [OUTPUT]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "from, f",
+			Usage: "Format of the input: xml, json or yaml. Detected from the file by default",
+		},
+		cli.StringFlag{
+			Name:  "to, t",
+			Usage: "Format of the output: xml, json or yaml",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		if c.NArg() < 2 || c.NArg() > 3 {
+			return cli.NewExitError("2 or 3 arguments are required", 1)
+		}
+		if c.String("to") == "" {
+			return cli.NewExitError("Output format is required, use --to", 1)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) (err error) {
+		w := io.Writer(os.Stdout)
+		if c.NArg() == 3 {
+			f, err := os.Create(c.Args()[2])
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			defer func() {
+				if closeErr := f.Close(); err == nil && closeErr != nil {
+					err = cli.NewExitError(closeErr, 1)
+				}
+			}()
+			w = f
+		}
+		if err := metaschema.Convert(c.Args()[0], c.Args()[1], c.String("from"), c.String("to"), w); err != nil {
+			return exitError(c, err, 1)
+		}
+		return nil
+	},
+}
+
+var jsonSchema = cli.Command{
+	Name:      "jsonschema",
+	Usage:     "Generate JSON Schema of json documents defined by given metaschema",
+	ArgsUsage: "METASCHEMA-DIR OUTPUT-DIR",
+	Before: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return cli.NewExitError("Exactly 2 arguments are required", 1)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) error {
+		if err := metaschema.GenerateJSONSchema(c.Args()[0], c.Args()[1]); err != nil {
+			return exitError(c, err, 1)
+		}
+		return nil
+	},
+}
+
+var xmlSchema = cli.Command{
+	Name:      "xsd",
+	Usage:     "Generate XML Schema of xml documents defined by given metaschema",
+	ArgsUsage: "METASCHEMA-DIR OUTPUT-DIR",
+	Before: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return cli.NewExitError("Exactly 2 arguments are required", 1)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) error {
+		if err := metaschema.GenerateXSD(c.Args()[0], c.Args()[1]); err != nil {
+			return exitError(c, err, 1)
+		}
+		return nil
+	},
+}
+
+var docs = cli.Command{
+	Name:      "docs",
+	Usage:     "Generate reference documentation of the definitions of given metaschema",
+	ArgsUsage: "METASCHEMA-DIR OUTPUT-DIR",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "markdown",
+			Usage: "Output format: markdown or html",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return cli.NewExitError("Exactly 2 arguments are required", 1)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) error {
+		if err := metaschema.GenerateDocs(c.Args()[0], c.Args()[1], c.String("format")); err != nil {
+			return exitError(c, err, 1)
+		}
+		return nil
+	},
+}
+
+var proto = cli.Command{
+	Name:  "proto",
+	Usage: "Generate protocol buffers schema and golang conversions of the code generated by given metaschema",
+	Description: "Writes <package>.proto next to the code written by generate, field numbers are kept " +
+		"in <package>.proto.lock between runs. Conversions are written into <package>/<package>pbconv and " +
+		"expect protoc-gen-go output in <package>/<package>pb. Run generate first, then proto, then protoc.",
+	ArgsUsage: "METASCHEMA-DIR GO-MODULE-IMPORT OUTPUT-DIR",
+	Before: func(c *cli.Context) error {
+		if c.NArg() != 3 {
+			return cli.NewExitError("Exactly 3 arguments are required", 1)
+		}
+		return nil
+	},
+	Action: func(c *cli.Context) error {
+		metaschemaDir, goModule, outputDir := c.Args()[0], c.Args()[1], c.Args()[2]
+		if err := metaschema.GenerateProto(metaschemaDir, goModule, outputDir); err != nil {
+			return exitError(c, err, 1)
 		}
 		return nil
 	},
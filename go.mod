@@ -6,6 +6,7 @@ require (
 	github.com/iancoleman/strcase v0.3.0
 	github.com/markbates/pkger v0.17.1
 	github.com/urfave/cli v1.22.17
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,38 @@
+package metaschema
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gocomply/metaschema/metaschema/document"
+)
+
+// Convert reads the document and writes it to w in another format. Format of
+// the input is detected unless given explicitly. Content not declared by the
+// metaschemas found in the directory is dropped.
+func Convert(metaschemaDir, input, from, to string, w io.Writer) error {
+	target, err := document.ParseFormat(to)
+	if err != nil {
+		return err
+	}
+	schema, err := LoadSchema(metaschemaDir)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(input) // #nosec G304
+	if err != nil {
+		return err
+	}
+	source := document.DetectFormat(input, data)
+	if from != "" {
+		if source, err = document.ParseFormat(from); err != nil {
+			return err
+		}
+	}
+	doc, err := schema.Decode(data, source)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	return document.Encode(w, doc, target)
+}
@@ -0,0 +1,276 @@
+// Package datatype implements lexical rules of the metaschema data types as
+// used by as-type attributes of flags and fields and by datatype attribute of
+// the matches constraint.
+package datatype
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Checker tests that the value is a valid lexical representation of a type
+type Checker func(value string) error
+
+var (
+	ncNameRe   = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_\-.\x{B7}\x{300}-\x{36F}\x{203F}-\x{2040}]*$`)
+	nmTokenRe  = regexp.MustCompile(`^[\p{L}\p{N}_\-.:\x{B7}\x{300}-\x{36F}\x{203F}-\x{2040}]+$`)
+	tokenRe    = regexp.MustCompile(`^[^\s](?:[^\s]| [^\s])*$`)
+	uuidRe     = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[45][0-9A-Fa-f]{3}-[89ABab][0-9A-Fa-f]{3}-[0-9A-Fa-f]{12}$`)
+	emailRe    = regexp.MustCompile(`^[^\s@]+@[^\s@]+$`)
+	hostnameRe = regexp.MustCompile(`^[\p{L}\p{N}](?:[\p{L}\p{N}\-]*[\p{L}\p{N}])?(?:\.[\p{L}\p{N}](?:[\p{L}\p{N}\-]*[\p{L}\p{N}])?)*\.?$`)
+	integerRe  = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	decimalRe  = regexp.MustCompile(`^[+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)$`)
+	dateRe     = regexp.MustCompile(`^(-?[0-9]{4,})-([0-9]{2})-([0-9]{2})(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+	dateTimeRe = regexp.MustCompile(`^(-?[0-9]{4,})-([0-9]{2})-([0-9]{2})T([0-9]{2}):([0-9]{2}):([0-9]{2})(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})?$`)
+	dayTimeRe  = regexp.MustCompile(`^-?P(?:[0-9]+D)?(?:T(?:[0-9]+H)?(?:[0-9]+M)?(?:[0-9]+(?:\.[0-9]+)?S)?)?$`)
+	yearMonRe  = regexp.MustCompile(`^-?P(?:[0-9]+Y)?(?:[0-9]+M)?$`)
+	uriRe      = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+\-.]*:`)
+)
+
+var checkers = map[string]Checker{
+	"string":                  checkString,
+	"token":                   matching(tokenRe, "token"),
+	"NCName":                  matching(ncNameRe, "NCName"),
+	"ID":                      matching(ncNameRe, "ID"),
+	"IDREF":                   matching(ncNameRe, "IDREF"),
+	"NMTOKEN":                 matching(nmTokenRe, "NMTOKEN"),
+	"uuid":                    matching(uuidRe, "uuid"),
+	"email":                   matching(emailRe, "email address"),
+	"email-address":           matching(emailRe, "email address"),
+	"hostname":                matching(hostnameRe, "hostname"),
+	"boolean":                 checkBoolean,
+	"integer":                 checkInteger(nil),
+	"nonNegativeInteger":      checkInteger(func(n int64) bool { return n >= 0 }),
+	"non-negative-integer":    checkInteger(func(n int64) bool { return n >= 0 }),
+	"positiveInteger":         checkInteger(func(n int64) bool { return n > 0 }),
+	"positive-integer":        checkInteger(func(n int64) bool { return n > 0 }),
+	"decimal":                 matching(decimalRe, "decimal"),
+	"date":                    checkDate(false),
+	"date-with-timezone":      checkDate(true),
+	"dateTime":                checkDateTime(false),
+	"date-time":               checkDateTime(false),
+	"dateTime-with-timezone":  checkDateTime(true),
+	"date-time-with-timezone": checkDateTime(true),
+	"day-time-duration":       checkDuration(dayTimeRe, "day-time-duration"),
+	"year-month-duration":     checkDuration(yearMonRe, "year-month-duration"),
+	"base64Binary":            checkBase64,
+	"base64":                  checkBase64,
+	"uri":                     checkURI,
+	"anyURI":                  checkURIReference,
+	"uri-reference":           checkURIReference,
+	"ip-v4-address":           checkIP(false),
+	"ip-v6-address":           checkIP(true),
+	"empty":                   checkEmpty,
+	"markup-line":             acceptAll,
+	"markup-multiline":        acceptAll,
+	"mixed":                   acceptAll,
+}
+
+// Known returns true for the data types recognized by this package
+func Known(name string) bool {
+	_, ok := checkers[name]
+	return ok
+}
+
+// Check tests the value against lexical rules of the named data type. Values
+// of unknown data types are accepted.
+func Check(name, value string) error {
+	if name == "" {
+		return nil
+	}
+	check, ok := checkers[name]
+	if !ok {
+		return nil
+	}
+	return check(value)
+}
+
+func matching(re *regexp.Regexp, name string) Checker {
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return invalid(value, name)
+		}
+		return nil
+	}
+}
+
+func invalid(value, name string) error {
+	return fmt.Errorf("'%s' is not a valid %s", value, name)
+}
+
+func checkString(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("value must not be empty")
+	}
+	return nil
+}
+
+func acceptAll(value string) error {
+	return nil
+}
+
+func checkEmpty(value string) error {
+	if strings.TrimSpace(value) != "" {
+		return fmt.Errorf("value must be empty")
+	}
+	return nil
+}
+
+func checkBoolean(value string) error {
+	switch value {
+	case "true", "false", "1", "0":
+		return nil
+	}
+	return invalid(value, "boolean")
+}
+
+func checkInteger(cond func(int64) bool) Checker {
+	return func(value string) error {
+		if !integerRe.MatchString(value) {
+			return invalid(value, "integer")
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return invalid(value, "integer")
+		}
+		if cond != nil && !cond(n) {
+			return fmt.Errorf("%d is out of range", n)
+		}
+		return nil
+	}
+}
+
+func checkDate(requireTZ bool) Checker {
+	return func(value string) error {
+		m := dateRe.FindStringSubmatch(value)
+		if m == nil {
+			return invalid(value, "date")
+		}
+		if requireTZ && m[4] == "" {
+			return fmt.Errorf("date '%s' is missing time zone", value)
+		}
+		return checkCalendar(value, m[1], m[2], m[3])
+	}
+}
+
+func checkDateTime(requireTZ bool) Checker {
+	return func(value string) error {
+		m := dateTimeRe.FindStringSubmatch(value)
+		if m == nil {
+			return invalid(value, "date and time")
+		}
+		if requireTZ && m[8] == "" {
+			return fmt.Errorf("date and time '%s' is missing time zone", value)
+		}
+		if err := checkCalendar(value, m[1], m[2], m[3]); err != nil {
+			return err
+		}
+		hour, _ := strconv.Atoi(m[4])
+		minute, _ := strconv.Atoi(m[5])
+		second, _ := strconv.Atoi(m[6])
+		if hour > 24 || minute > 59 || second > 60 || (hour == 24 && (minute != 0 || second != 0)) {
+			return invalid(value, "date and time")
+		}
+		return nil
+	}
+}
+
+func checkCalendar(value, y, m, d string) error {
+	year, _ := strconv.Atoi(y)
+	month, _ := strconv.Atoi(m)
+	day, _ := strconv.Atoi(d)
+	if month < 1 || month > 12 || day < 1 {
+		return invalid(value, "date")
+	}
+	// time.Date normalizes overflowing days into the next month
+	if time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Day() != day {
+		return invalid(value, "date")
+	}
+	return nil
+}
+
+func checkDuration(re *regexp.Regexp, name string) Checker {
+	return func(value string) error {
+		if !re.MatchString(value) || strings.HasSuffix(value, "P") || strings.HasSuffix(value, "T") {
+			return invalid(value, name)
+		}
+		return nil
+	}
+}
+
+func checkBase64(value string) error {
+	stripped := strings.Join(strings.Fields(value), "")
+	if _, err := base64.StdEncoding.DecodeString(stripped); err != nil {
+		return invalid(value, "base64 encoded content")
+	}
+	return nil
+}
+
+func checkURI(value string) error {
+	if !uriRe.MatchString(value) {
+		return fmt.Errorf("'%s' is not an absolute uri", value)
+	}
+	return checkURIReference(value)
+}
+
+func checkURIReference(value string) error {
+	if strings.ContainsAny(value, " \t\n\r") {
+		return invalid(value, "uri reference")
+	}
+	if _, err := url.Parse(value); err != nil {
+		return invalid(value, "uri reference")
+	}
+	return nil
+}
+
+func checkIP(v6 bool) Checker {
+	return func(value string) error {
+		ip := net.ParseIP(value)
+		if v6 {
+			if ip == nil || !strings.Contains(value, ":") {
+				return invalid(value, "IPv6 address")
+			}
+			return nil
+		}
+		if ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return invalid(value, "IPv4 address")
+		}
+		return nil
+	}
+}
+
+// Parse checks the value and converts it to go value of the type: bool for
+// boolean, int64 for integers, float64 for decimal, time.Time for dates,
+// []byte for base64 and string for all the other types
+func Parse(name, value string) (interface{}, error) {
+	if err := Check(name, value); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "boolean":
+		return value == "true" || value == "1", nil
+	case "integer", "nonNegativeInteger", "non-negative-integer", "positiveInteger", "positive-integer":
+		return strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64)
+	case "decimal":
+		return strconv.ParseFloat(value, 64)
+	case "date", "date-with-timezone":
+		if len(value) > len("2006-01-02") {
+			return time.Parse("2006-01-02Z07:00", value)
+		}
+		return time.Parse("2006-01-02", value)
+	case "dateTime", "date-time", "dateTime-with-timezone", "date-time-with-timezone":
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02T15:04:05.999999999", value)
+	case "base64", "base64Binary":
+		return base64.StdEncoding.DecodeString(value)
+	}
+	return value, nil
+}
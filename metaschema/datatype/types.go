@@ -0,0 +1,381 @@
+package datatype
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Boolean is a value of boolean data type
+type Boolean bool
+
+// String returns the lexical representation of the value
+func (b Boolean) String() string {
+	text, _ := b.MarshalText()
+	return string(text)
+}
+
+func (b Boolean) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatBool(bool(b))), nil
+}
+
+func (b *Boolean) UnmarshalText(text []byte) error {
+	v, err := Parse("boolean", string(text))
+	if err != nil {
+		return err
+	}
+	*b = Boolean(v.(bool))
+	return nil
+}
+
+func (b Boolean) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bool(b))
+}
+
+func (b *Boolean) UnmarshalJSON(data []byte) error {
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("'%s' is not a valid boolean", data)
+	}
+	*b = Boolean(v)
+	return nil
+}
+
+// MarshalYAML writes YAML boolean, values are read by UnmarshalText
+func (b Boolean) MarshalYAML() (interface{}, error) {
+	return bool(b), nil
+}
+
+// Integer is a value of integer data type
+type Integer int64
+
+// String returns the lexical representation of the value
+func (i Integer) String() string {
+	text, _ := i.MarshalText()
+	return string(text)
+}
+
+func (i Integer) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(i), 10)), nil
+}
+
+func (i *Integer) UnmarshalText(text []byte) error {
+	v, err := Parse("integer", string(text))
+	if err != nil {
+		return err
+	}
+	*i = Integer(v.(int64))
+	return nil
+}
+
+func (i Integer) MarshalJSON() ([]byte, error) {
+	return i.MarshalText()
+}
+
+// UnmarshalJSON accepts JSON numbers, quoted strings do not pass the lexical
+// check of the type
+func (i *Integer) UnmarshalJSON(data []byte) error {
+	return i.UnmarshalText(data)
+}
+
+// MarshalYAML writes YAML integer, values are read by UnmarshalText
+func (i Integer) MarshalYAML() (interface{}, error) {
+	return int64(i), nil
+}
+
+// NonNegativeInteger is a value of nonNegativeInteger data type
+type NonNegativeInteger uint64
+
+// String returns the lexical representation of the value
+func (i NonNegativeInteger) String() string {
+	text, _ := i.MarshalText()
+	return string(text)
+}
+
+func (i NonNegativeInteger) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(i), 10)), nil
+}
+
+func (i *NonNegativeInteger) UnmarshalText(text []byte) error {
+	v, err := parseUint("nonNegativeInteger", text)
+	*i = NonNegativeInteger(v)
+	return err
+}
+
+func (i NonNegativeInteger) MarshalJSON() ([]byte, error) {
+	return i.MarshalText()
+}
+
+func (i *NonNegativeInteger) UnmarshalJSON(data []byte) error {
+	return i.UnmarshalText(data)
+}
+
+func (i NonNegativeInteger) MarshalYAML() (interface{}, error) {
+	return uint64(i), nil
+}
+
+// PositiveInteger is a value of positiveInteger data type
+type PositiveInteger uint64
+
+// String returns the lexical representation of the value
+func (i PositiveInteger) String() string {
+	text, _ := i.MarshalText()
+	return string(text)
+}
+
+func (i PositiveInteger) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(i), 10)), nil
+}
+
+func (i *PositiveInteger) UnmarshalText(text []byte) error {
+	v, err := parseUint("positiveInteger", text)
+	*i = PositiveInteger(v)
+	return err
+}
+
+func (i PositiveInteger) MarshalJSON() ([]byte, error) {
+	return i.MarshalText()
+}
+
+func (i *PositiveInteger) UnmarshalJSON(data []byte) error {
+	return i.UnmarshalText(data)
+}
+
+func (i PositiveInteger) MarshalYAML() (interface{}, error) {
+	return uint64(i), nil
+}
+
+func parseUint(name string, text []byte) (uint64, error) {
+	if err := Check(name, string(text)); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimPrefix(string(text), "+"), 10, 64)
+}
+
+// Decimal is a value of decimal data type. The lexical value is kept, so
+// that no precision is lost.
+type Decimal string
+
+// Float64 returns the value as floating point number
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(string(d), 64)
+	return f
+}
+
+// String returns the lexical representation of the value
+func (d Decimal) String() string {
+	text, _ := d.MarshalText()
+	return string(text)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if err := Check("decimal", string(text)); err != nil {
+		return err
+	}
+	*d = Decimal(text)
+	return nil
+}
+
+// MarshalJSON writes the value as JSON number, in canonical form as not all
+// the lexical forms of decimal (such as +1, .5 or 1.) are valid JSON numbers
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.canonical()), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	return d.UnmarshalText(data)
+}
+
+// MarshalYAML writes the canonical value as plain YAML scalar, so that it is
+// read back as number
+func (d Decimal) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: d.canonical()}, nil
+}
+
+// canonical returns the value without plus sign and leading zeros, with
+// zero on both sides of the decimal point when it is present. Empty value is
+// zero.
+func (d Decimal) canonical() string {
+	s := string(d)
+	sign := ""
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		sign, s = "-", s[1:]
+	}
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !hasPoint {
+		return sign + intPart
+	}
+	if fracPart == "" {
+		fracPart = "0"
+	}
+	return sign + intPart + "." + fracPart
+}
+
+// Date is a value of date data type
+type Date struct {
+	time.Time
+	// HasTimezone tells whether the timezone is part of the value
+	HasTimezone bool
+}
+
+// String returns the lexical representation of the value
+func (d Date) String() string {
+	text, _ := d.MarshalText()
+	return string(text)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	if d.HasTimezone {
+		return []byte(d.Format("2006-01-02Z07:00")), nil
+	}
+	return []byte(d.Format("2006-01-02")), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	v, err := Parse("date", string(text))
+	if err != nil {
+		return err
+	}
+	*d = Date{Time: v.(time.Time), HasTimezone: len(text) > len("2006-01-02")}
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return marshalJSONText(d)
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d.UnmarshalText)
+}
+
+// DateTime is a value of dateTime data type
+type DateTime struct {
+	time.Time
+	// HasTimezone tells whether the timezone is part of the value
+	HasTimezone bool
+}
+
+// String returns the lexical representation of the value
+func (d DateTime) String() string {
+	text, _ := d.MarshalText()
+	return string(text)
+}
+
+func (d DateTime) MarshalText() ([]byte, error) {
+	if d.HasTimezone {
+		return []byte(d.Format(time.RFC3339Nano)), nil
+	}
+	return []byte(d.Format("2006-01-02T15:04:05.999999999")), nil
+}
+
+func (d *DateTime) UnmarshalText(text []byte) error {
+	v, err := Parse("dateTime", string(text))
+	if err != nil {
+		return err
+	}
+	*d = DateTime{Time: v.(time.Time), HasTimezone: dateTimeRe.FindStringSubmatch(string(text))[8] != ""}
+	return nil
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return marshalJSONText(d)
+}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d.UnmarshalText)
+}
+
+// DateWithTimezone is a value of date-with-timezone data type, values
+// without timezone are rejected
+type DateWithTimezone struct {
+	Date
+}
+
+func (d *DateWithTimezone) UnmarshalText(text []byte) error {
+	if err := Check("date-with-timezone", string(text)); err != nil {
+		return err
+	}
+	return d.Date.UnmarshalText(text)
+}
+
+func (d *DateWithTimezone) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d.UnmarshalText)
+}
+
+// DateTimeWithTimezone is a value of dateTime-with-timezone data type, values
+// without timezone are rejected
+type DateTimeWithTimezone struct {
+	DateTime
+}
+
+func (d *DateTimeWithTimezone) UnmarshalText(text []byte) error {
+	if err := Check("dateTime-with-timezone", string(text)); err != nil {
+		return err
+	}
+	return d.DateTime.UnmarshalText(text)
+}
+
+func (d *DateTimeWithTimezone) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d.UnmarshalText)
+}
+
+// Base64 is a value of base64Binary data type
+type Base64 []byte
+
+// String returns the lexical representation of the value
+func (b Base64) String() string {
+	text, _ := b.MarshalText()
+	return string(text)
+}
+
+func (b Base64) MarshalText() ([]byte, error) {
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+func (b *Base64) UnmarshalText(text []byte) error {
+	v, err := Parse("base64Binary", string(text))
+	if err != nil {
+		return err
+	}
+	*b = v.([]byte)
+	return nil
+}
+
+type textMarshaler interface {
+	MarshalText() ([]byte, error)
+}
+
+// marshalJSONText writes the text form as JSON string. Date and DateTime
+// implement JSON explicitly, otherwise the JSON methods of the embedded Time
+// would be promoted.
+func marshalJSONText(v textMarshaler) ([]byte, error) {
+	text, err := v.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func unmarshalJSONText(data []byte, unmarshal func([]byte) error) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("'%s' is not a string", data)
+	}
+	return unmarshal([]byte(s))
+}
@@ -0,0 +1,76 @@
+package datatype
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalMarshalJSON(t *testing.T) {
+	tests := []struct {
+		lexical string
+		want    string
+	}{
+		{"", "0"},
+		{"1.50", "1.50"},
+		{"+1.50", "1.50"},
+		{".5", "0.5"},
+		{"-.5", "-0.5"},
+		{"1.", "1.0"},
+		{"+1", "1"},
+		{"007", "7"},
+		{"-00.25", "-0.25"},
+		{"000", "0"},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		if tt.lexical != "" {
+			if err := d.UnmarshalText([]byte(tt.lexical)); err != nil {
+				t.Fatalf("%q: %v", tt.lexical, err)
+			}
+		}
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.lexical, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.lexical, b, tt.want)
+		}
+		var back Decimal
+		if err := json.Unmarshal(b, &back); err != nil {
+			t.Errorf("%q: written JSON does not read back: %v", tt.lexical, err)
+		}
+	}
+}
+
+func TestTimezoneRequired(t *testing.T) {
+	tests := []struct {
+		value string
+		v     interface{ UnmarshalText([]byte) error }
+		valid bool
+	}{
+		{"2024-01-02Z", &DateWithTimezone{}, true},
+		{"2024-01-02+02:00", &DateWithTimezone{}, true},
+		{"2024-01-02", &DateWithTimezone{}, false},
+		{"2024-01-02", &Date{}, true},
+		{"2024-01-02T03:04:05Z", &DateTimeWithTimezone{}, true},
+		{"2024-01-02T03:04:05.5-05:00", &DateTimeWithTimezone{}, true},
+		{"2024-01-02T03:04:05", &DateTimeWithTimezone{}, false},
+		{"2024-01-02T03:04:05", &DateTime{}, true},
+	}
+	for _, tt := range tests {
+		err := tt.v.UnmarshalText([]byte(tt.value))
+		if (err == nil) != tt.valid {
+			t.Errorf("%T %q: got error %v, want valid=%v", tt.v, tt.value, err, tt.valid)
+		}
+	}
+	var d DateTimeWithTimezone
+	if err := json.Unmarshal([]byte(`"2024-01-02T03:04:05"`), &d); err == nil {
+		t.Errorf("JSON value without timezone accepted")
+	}
+	if err := json.Unmarshal([]byte(`"2024-01-02T03:04:05Z"`), &d); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := json.Marshal(d); string(b) != `"2024-01-02T03:04:05Z"` {
+		t.Errorf("got %s", b)
+	}
+}
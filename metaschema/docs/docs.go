@@ -0,0 +1,581 @@
+// Package docs builds a reference documentation of the definitions of
+// compiled metaschemas. The reference lists for each assembly, field and flag
+// its XML and JSON paths, flags and model with cardinality and data types,
+// allowed values and examples. References to definitions of imported
+// metaschemas are links to the reference of the imported metaschema.
+//
+// The reference is rendered to Markdown or HTML by templates.GenerateDocs.
+package docs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/markup"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Format of the rendered reference
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+)
+
+// ParseFormat parses name of the format
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case Markdown, HTML:
+		return f, nil
+	case "md":
+		return Markdown, nil
+	}
+	return "", fmt.Errorf("Unknown documentation format '%s', expected markdown or html", name)
+}
+
+// Extension returns file name extension of the format
+func (f Format) Extension() string {
+	if f == HTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// maxPaths limits number of paths listed for a single definition, recursive
+// models would list too many of them
+const maxPaths = 20
+
+// Reference documents definitions of a single metaschema
+type Reference struct {
+	Name        string
+	Package     string
+	Namespace   string
+	JsonBaseURI string
+	// Remarks is XHTML markup
+	Remarks    string
+	Assemblies []*Definition
+	Fields     []*Definition
+	Flags      []*Definition
+
+	format Format
+}
+
+// FileName returns name of the file of the reference
+func (r *Reference) FileName() string {
+	return r.Package + r.format.Extension()
+}
+
+// Definition documents an assembly, field or flag definition
+type Definition struct {
+	Kind       string
+	Name       string
+	FormalName string
+	// Anchor identifies the definition within the reference
+	Anchor      string
+	Description string
+	// Remarks is XHTML markup
+	Remarks string
+	// RootName is the name of the root element of documents, if the
+	// assembly may be the root
+	RootName     string
+	DataType     string
+	JsonKey      string
+	JsonValueKey string
+	// Inline is true for definitions local to their parent
+	Inline        bool
+	XmlPaths      []string
+	JsonPaths     []string
+	MorePaths     int
+	Flags         []*Property
+	Model         []*Property
+	AllowedValues []*AllowedValues
+	Examples      []*Example
+}
+
+// Property is a flag of a definition or an item of assembly model
+type Property struct {
+	XmlName  string
+	JsonName string
+	// Link refers to the documentation of the definition of the property,
+	// it is empty for flags declared inline
+	Link        string
+	Kind        string
+	DataType    string
+	Cardinality string
+	// Grouping describes group-as of model items
+	Grouping string
+	// Choice lists other members of the choice the item belongs to
+	Choice        string
+	Description   string
+	AllowedValues []*AllowedValues
+}
+
+// AllowedValues documents an allowed-values constraint
+type AllowedValues struct {
+	Closed bool
+	Values []*Value
+}
+
+// Value is an enumerated value
+type Value struct {
+	Value       string
+	Description string
+	Deprecated  string
+}
+
+// Example is an example of the definition
+type Example struct {
+	Description string
+	// Remarks is XHTML markup
+	Remarks string
+	// XML is the example content
+	XML string
+}
+
+// Build creates references of the metaschemas. Paths of the definitions are
+// found from root assemblies of all the metaschemas, so that definitions of
+// imported metaschemas are located within documents of the importing ones.
+func Build(metaschemas []*parser.Metaschema, format Format) []*Reference {
+	b := builder{definitions: map[string]*Definition{}, format: format}
+	result := make([]*Reference, 0, len(metaschemas))
+	for _, m := range metaschemas {
+		result = append(result, b.reference(m))
+	}
+	for _, m := range metaschemas {
+		for _, root := range m.RootAssemblies() {
+			d := b.definitions[key(root.Metaschema, anchor("assembly", "", root.Name))]
+			b.walkAssembly(d, root, "/"+root.RootXmlName(), root.RootXmlName(), nil)
+		}
+	}
+	return result
+}
+
+type builder struct {
+	// definitions are indexed by anchor within package
+	definitions map[string]*Definition
+	format      Format
+}
+
+func key(m *parser.Metaschema, anchor string) string {
+	if m == nil {
+		return anchor
+	}
+	return m.GoPackageName() + "#" + anchor
+}
+
+func (b *builder) reference(m *parser.Metaschema) *Reference {
+	r := &Reference{
+		Name:        name(m),
+		Package:     m.GoPackageName(),
+		Namespace:   m.XmlNamespace(),
+		JsonBaseURI: m.JsonBaseURI(),
+		Remarks:     remarks(m.Remarks),
+		format:      b.format,
+	}
+	for i := range m.DefineAssembly {
+		b.assembly(r, m, &m.DefineAssembly[i], "")
+	}
+	for i := range m.DefineField {
+		b.field(r, m, &m.DefineField[i], "")
+	}
+	for i := range m.DefineFlag {
+		df := &m.DefineFlag[i]
+		d := &Definition{
+			Kind:        "flag",
+			Name:        df.Name,
+			FormalName:  df.FormalName,
+			Anchor:      "flag-" + df.Name,
+			Description: text(df.Description),
+			Remarks:     remarks(df.Remarks),
+			DataType:    dataType(df.AsType),
+			Examples:    examples(df.Examples),
+		}
+		if df.Constraint != nil {
+			d.AllowedValues = allowedValues(df.Constraint.Rules())
+		}
+		b.definitions[key(m, d.Anchor)] = d
+		r.Flags = append(r.Flags, d)
+	}
+	sortDefinitions(r.Assemblies)
+	sortDefinitions(r.Fields)
+	sortDefinitions(r.Flags)
+	return r
+}
+
+func sortDefinitions(list []*Definition) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Anchor < list[j].Anchor
+	})
+}
+
+// assembly adds the assembly definition and its local definitions to the
+// reference, parent is the anchor of the enclosing definition of local ones
+func (b *builder) assembly(r *Reference, m *parser.Metaschema, da *parser.DefineAssembly, parent string) {
+	d := &Definition{
+		Kind:        "assembly",
+		Name:        da.Name,
+		FormalName:  da.FormalName,
+		Anchor:      anchor("assembly", parent, da.Name),
+		Description: text(da.Description),
+		Remarks:     remarks(da.Remarks),
+		Inline:      parent != "",
+		Flags:       b.flags(m, da.Flags),
+		Examples:    examples(da.Examples),
+	}
+	if da.RepresentsRootElement() {
+		d.RootName = da.RootXmlName()
+	}
+	if da.JsonKey != nil {
+		d.JsonKey = da.JsonKey.FlagName
+	}
+	b.definitions[key(m, d.Anchor)] = d
+	r.Assemblies = append(r.Assemblies, d)
+	if da.Model == nil {
+		return
+	}
+	for _, item := range da.Model.GoStructItems() {
+		p := b.property(m, item, d.Anchor)
+		if choice := choiceOf(da.Model, item); choice != nil {
+			p.Choice = choice.JsonNames()
+		}
+		d.Model = append(d.Model, p)
+		switch v := item.(type) {
+		case *parser.Assembly:
+			if v.IsInline() {
+				b.assembly(r, m, v.Def, d.Anchor)
+			}
+		case *parser.Field:
+			if v.IsInline() {
+				b.field(r, m, v.Def, d.Anchor)
+			}
+		}
+	}
+}
+
+func (b *builder) field(r *Reference, m *parser.Metaschema, df *parser.DefineField, parent string) {
+	d := &Definition{
+		Kind:          "field",
+		Name:          df.Name,
+		FormalName:    df.FormalName,
+		Anchor:        anchor("field", parent, df.Name),
+		Description:   text(df.Description),
+		Remarks:       remarks(df.Remarks),
+		DataType:      dataType(df.AsType),
+		Inline:        parent != "",
+		Flags:         b.flags(m, df.Flags),
+		AllowedValues: allowedValues(df.Constraint.Rules()),
+		Examples:      examples(df.Examples),
+	}
+	if df.JsonKey != nil {
+		d.JsonKey = df.JsonKey.FlagName
+	}
+	if len(df.Flags) > 0 && !df.Empty() {
+		d.JsonValueKey = df.JsonName()
+	}
+	b.definitions[key(m, d.Anchor)] = d
+	r.Fields = append(r.Fields, d)
+}
+
+// anchor identifies the definition, local definitions are prefixed by the
+// anchor of their parent
+func anchor(kind, parent, name string) string {
+	if parent == "" {
+		return kind + "-" + name
+	}
+	return parent + "-" + kind + "-" + name
+}
+
+func (b *builder) flags(m *parser.Metaschema, flags []parser.Flag) []*Property {
+	result := make([]*Property, 0, len(flags))
+	for i := range flags {
+		f := &flags[i]
+		p := &Property{
+			XmlName:       "@" + f.XmlName(),
+			JsonName:      f.JsonName(),
+			Kind:          "flag",
+			Cardinality:   "0..1",
+			Description:   text(f.Description),
+			AllowedValues: allowedValues(openAndClosed(f)),
+		}
+		asType := f.AsType
+		if f.Def != nil {
+			p.Link = b.link(m, f.Def.Metaschema, "flag-"+f.Def.Name)
+			if asType == "" {
+				asType = f.Def.AsType
+			}
+			if p.Description == "" {
+				p.Description = text(f.Def.Description)
+			}
+		}
+		p.DataType = dataType(asType)
+		if f.IsRequired() {
+			p.Cardinality = "1"
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// openAndClosed returns all the allowed-values rules applicable to the flag
+func openAndClosed(f *parser.Flag) []parser.Rule {
+	var result []parser.Rule
+	if f.Constraint != nil {
+		result = append(result, f.Constraint.Rules()...)
+	}
+	if f.Def != nil && f.Def.Constraint != nil {
+		result = append(result, f.Def.Constraint.Rules()...)
+	}
+	return result
+}
+
+func (b *builder) property(m *parser.Metaschema, item parser.GoStructItem, parent string) *Property {
+	p := &Property{
+		XmlName:     item.XmlName(),
+		JsonName:    item.JsonName(),
+		Cardinality: cardinality(item.Min(), item.Max()),
+		Description: text(item.GoComment()),
+	}
+	var groupAs *parser.GroupAs
+	switch v := item.(type) {
+	case *parser.Assembly:
+		p.Kind, groupAs = "assembly", v.GroupAs
+		if v.IsInline() {
+			p.Link = "#" + anchor("assembly", parent, v.Def.Name)
+		} else {
+			p.Link = b.link(m, v.Def.Metaschema, "assembly-"+v.Def.Name)
+		}
+	case *parser.Field:
+		p.Kind, groupAs = "field", v.GroupAs
+		p.DataType = dataType(v.Def.AsType)
+		if v.IsInline() {
+			p.Link = "#" + anchor("field", parent, v.Def.Name)
+		} else {
+			p.Link = b.link(m, v.Def.Metaschema, "field-"+v.Def.Name)
+		}
+		if v.InXml == "UNWRAPPED" {
+			p.Grouping = "unwrapped in XML"
+		}
+	}
+	if groupAs != nil {
+		var grouping []string
+		if groupAs.InXml == "GROUPED" {
+			grouping = append(grouping, "wrapped by "+groupAs.Name+" in XML")
+		}
+		switch {
+		case groupAs.ByKey():
+			grouping = append(grouping, "object by key in JSON")
+		case groupAs.SingletonOrArray():
+			grouping = append(grouping, "single item or array in JSON")
+		default:
+			grouping = append(grouping, "array in JSON")
+		}
+		p.Grouping = strings.Join(grouping, ", ")
+	}
+	return p
+}
+
+// link returns link to the definition, that is an anchor within the
+// reference or a link to the reference of the imported metaschema
+func (b *builder) link(from, to *parser.Metaschema, anchor string) string {
+	if to == nil || to.GoPackageName() == from.GoPackageName() {
+		return "#" + anchor
+	}
+	return to.GoPackageName() + b.format.Extension() + "#" + anchor
+}
+
+func cardinality(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("%d..∞", min)
+	case min == max:
+		return fmt.Sprint(min)
+	}
+	return fmt.Sprintf("%d..%d", min, max)
+}
+
+func choiceOf(model *parser.Model, item parser.GoStructItem) *parser.Choice {
+	for i := range model.Choice {
+		for _, member := range model.Choice[i].GoStructItems() {
+			if member == item {
+				return &model.Choice[i]
+			}
+		}
+	}
+	return nil
+}
+
+// walkAssembly records paths of the assembly documented by d and of its
+// descendants, stack holds the definitions on the path to stop at recursion
+func (b *builder) walkAssembly(d *Definition, da *parser.DefineAssembly, xmlPath, jsonPath string, stack []*Definition) {
+	if d == nil || !d.addPath(xmlPath, jsonPath) || contains(stack, d) {
+		return
+	}
+	stack = append(stack, d)
+	b.walkFlags(da.Flags, xmlPath, jsonPath)
+	if da.Model == nil {
+		return
+	}
+	for _, item := range da.Model.GoStructItems() {
+		childXml, childJson := xmlPath, jsonPath+"."+item.JsonName()
+		var groupAs *parser.GroupAs
+		switch v := item.(type) {
+		case *parser.Assembly:
+			groupAs = v.GroupAs
+		case *parser.Field:
+			groupAs = v.GroupAs
+		}
+		if groupAs != nil {
+			if groupAs.InXml == "GROUPED" {
+				childXml += "/" + groupAs.Name
+			}
+			if groupAs.ByKey() {
+				childJson += ".*"
+			} else {
+				childJson += "[]"
+			}
+		}
+		childXml += "/" + item.XmlName()
+		switch v := item.(type) {
+		case *parser.Assembly:
+			b.walkAssembly(b.child(d, v.Def.Metaschema, "assembly", v.Def.Name, v.IsInline()), v.Def, childXml, childJson, stack)
+		case *parser.Field:
+			if fd := b.child(d, v.Def.Metaschema, "field", v.Def.Name, v.IsInline()); fd != nil && fd.addPath(childXml, childJson) {
+				b.walkFlags(v.Def.Flags, childXml, childJson)
+			}
+		}
+	}
+}
+
+func (b *builder) walkFlags(flags []parser.Flag, xmlPath, jsonPath string) {
+	for i := range flags {
+		f := &flags[i]
+		if f.Def == nil {
+			continue
+		}
+		if d := b.definitions[key(f.Def.Metaschema, "flag-"+f.Def.Name)]; d != nil {
+			d.addPath(xmlPath+"/@"+f.XmlName(), jsonPath+"."+f.JsonName())
+		}
+	}
+}
+
+// child returns documentation of the definition of a model item of parent
+func (b *builder) child(parent *Definition, m *parser.Metaschema, kind, name string, inline bool) *Definition {
+	if inline {
+		return b.definitions[key(m, anchor(kind, parent.Anchor, name))]
+	}
+	return b.definitions[key(m, anchor(kind, "", name))]
+}
+
+// addPath records the path, false is returned when the definition has
+// already been reached through too many paths
+func (d *Definition) addPath(xmlPath, jsonPath string) bool {
+	if len(d.XmlPaths) >= maxPaths {
+		d.MorePaths++
+		return false
+	}
+	d.XmlPaths = append(d.XmlPaths, xmlPath)
+	d.JsonPaths = append(d.JsonPaths, jsonPath)
+	return true
+}
+
+func contains(stack []*Definition, d *Definition) bool {
+	for _, s := range stack {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedValues(rules []parser.Rule) []*AllowedValues {
+	var result []*AllowedValues
+	for _, r := range rules {
+		av, ok := r.(*parser.AllowedValues)
+		if !ok || len(av.Enum) == 0 {
+			continue
+		}
+		values := &AllowedValues{Closed: !av.AllowsOther()}
+		for _, e := range av.Enum {
+			values.Values = append(values.Values, &Value{
+				Value:       e.Value,
+				Description: text(markup.Text(e.InnerXML)),
+				Deprecated:  e.Deprecated,
+			})
+		}
+		result = append(result, values)
+	}
+	return result
+}
+
+var (
+	exampleMetaRe = regexp.MustCompile(`(?s)<(description|remarks)\b[^>]*>.*?</(description|remarks)>`)
+	xmlnsRe       = regexp.MustCompile(`\s+xmlns(:\w+)?="[^"]*"`)
+)
+
+func examples(list []parser.Example) []*Example {
+	result := make([]*Example, 0, len(list))
+	for i := range list {
+		e := &list[i]
+		content := exampleMetaRe.ReplaceAllString(e.InnerXML, "")
+		content = xmlnsRe.ReplaceAllString(content, "")
+		result = append(result, &Example{
+			Description: text(e.Description),
+			Remarks:     remarks(e.Remarks),
+			XML:         dedent(content),
+		})
+	}
+	return result
+}
+
+// dedent removes blank lines around the content and the indentation common
+// to all its lines
+func dedent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func remarks(r *parser.Remarks) string {
+	if r == nil {
+		return ""
+	}
+	return strings.TrimSpace(xmlnsRe.ReplaceAllString(r.InnerXML, ""))
+}
+
+func name(m *parser.Metaschema) string {
+	if m.SchemaName != nil {
+		if n := text(markup.Text(m.SchemaName.InnerXML)); n != "" {
+			return n
+		}
+	}
+	return m.GoPackageName()
+}
+
+func dataType(asType parser.AsType) string {
+	if asType == "" {
+		return string(parser.AsTypeString)
+	}
+	return string(asType)
+}
+
+// text collapses white space of plain text
+func text(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,173 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const docMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>doc</short-name>
+  <namespace>http://example.com/ns/doc</namespace>
+  <define-assembly name="doc">
+    <description>A doc</description>
+    <root-name>doc</root-name>
+    <flag name="version" as-type="integer"><description>v</description></flag>
+    <flag name="draft" as-type="boolean"><description>d</description></flag>
+    <model>
+      <field ref="title" required="yes"/>
+      <field ref="prop" max-occurs="unbounded"><group-as name="props" in-json="BY_KEY"/></field>
+      <field ref="note" max-occurs="unbounded"><group-as name="notes" in-json="SINGLETON_OR_ARRAY"/></field>
+      <assembly ref="part" max-occurs="unbounded"><group-as name="parts"/></assembly>
+    </model>
+  </define-assembly>
+  <define-assembly name="part">
+    <description>A part</description>
+    <flag name="name" as-type="NCName" required="yes"><description>n</description></flag>
+    <model>
+      <field ref="title"/>
+      <field ref="prose" in-xml="UNWRAPPED"/>
+      <field ref="count"/>
+    </model>
+  </define-assembly>
+  <define-field name="title" as-type="markup-line"><description>t</description></define-field>
+  <define-field name="prop">
+    <description>p</description>
+    <json-key flag-name="name"/>
+    <flag name="name" as-type="NCName" required="yes"><description>n</description></flag>
+    <flag name="class" as-type="NCName"><description>c</description></flag>
+  </define-field>
+  <define-field name="note"><description>n</description></define-field>
+  <define-field name="count" as-type="nonNegativeInteger">
+    <description>c</description>
+    <flag name="unit" as-type="token"><description>u</description></flag>
+  </define-field>
+  <define-field name="prose" as-type="markup-multiline"><description>p</description></define-field>
+</METASCHEMA>`
+
+func loadSchema(t *testing.T) *Schema {
+	t.Helper()
+	meta := &parser.Metaschema{URI: "file:///doc.xml"}
+	if err := xml.Unmarshal([]byte(docMetaschema), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := NewSchema(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func encode(t *testing.T, doc *Node, format Format) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Encode(&buf, doc, format); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// TestRoundTrip converts each document from XML to JSON, to YAML, back to XML
+// and to JSON again, which has to give the JSON of the original document.
+// White space between XHTML blocks is not kept, so XML is not compared.
+func TestRoundTrip(t *testing.T) {
+	schema := loadSchema(t)
+	tests := []struct {
+		name string
+		xml  string
+		// json and yaml are excerpts of the intermediate documents
+		json []string
+		yaml []string
+	}{
+		{
+			"markup",
+			`<doc xmlns="http://example.com/ns/doc" version="3" draft="true">
+  <title>The <em>big</em> &amp; <code>small</code> doc</title>
+  <part name="intro">
+    <title>Intro</title>
+    <p>First paragraph with <a href="#x">link</a> and <insert type="param" id-ref="p1"/>.</p>
+    <ul>
+      <li>one</li>
+      <li>two with <strong>bold</strong></li>
+    </ul>
+    <h2>Heading</h2>
+    <pre>code
+  block</pre>
+  </part>
+</doc>`,
+			[]string{
+				`"title": "The *big* & ` + "`small`" + ` doc"`,
+				`"version": 3`,
+				`"draft": true`,
+				`First paragraph with [link](#x) and {{ insert: param, p1 }}.`,
+				`- two with **bold**`,
+				`## Heading`,
+			},
+			[]string{
+				"title: The *big* & `small` doc",
+				"version: 3",
+			},
+		},
+		{
+			"groups",
+			`<doc xmlns="http://example.com/ns/doc">
+  <title>Groups</title>
+  <prop name="status" class="x">draft</prop>
+  <prop name="owner">me</prop>
+  <note>only one</note>
+  <part name="a"><count unit="pages">12</count></part>
+  <part name="b"/>
+</doc>`,
+			[]string{
+				`"props": {`,
+				`"status": {`,
+				`"class": "x"`,
+				`"notes": "only one"`,
+				`"count": {`,
+				`"unit": "pages"`,
+			},
+			[]string{
+				"props:\n    status:",
+				"notes: only one",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := schema.Decode([]byte(tt.xml), XML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			js := encode(t, doc, JSON)
+			for _, excerpt := range tt.json {
+				if !strings.Contains(js, excerpt) {
+					t.Errorf("JSON is missing %s:\n%s", excerpt, js)
+				}
+			}
+			if doc, err = schema.Decode([]byte(js), JSON); err != nil {
+				t.Fatal(err)
+			}
+			yml := encode(t, doc, YAML)
+			for _, excerpt := range tt.yaml {
+				if !strings.Contains(yml, excerpt) {
+					t.Errorf("YAML is missing %s:\n%s", excerpt, yml)
+				}
+			}
+			if doc, err = schema.Decode([]byte(yml), YAML); err != nil {
+				t.Fatal(err)
+			}
+			if doc, err = schema.Decode([]byte(encode(t, doc, XML)), XML); err != nil {
+				t.Fatal(err)
+			}
+			if got := encode(t, doc, JSON); got != js {
+				t.Errorf("JSON differs after round trip, got\n%s\nwant\n%s", got, js)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package document
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/datatype"
+	"github.com/gocomply/metaschema/metaschema/markup"
+	"github.com/gocomply/metaschema/metaschema/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Encode writes the document in the given format. Nodes not declared by the
+// metaschema are left out.
+func Encode(w io.Writer, doc *Node, format Format) error {
+	switch format {
+	case XML:
+		return EncodeXML(w, doc)
+	case JSON:
+		return EncodeJSON(w, doc)
+	case YAML:
+		return EncodeYAML(w, doc)
+	}
+	return fmt.Errorf("unsupported format %s", format)
+}
+
+// EncodeXML writes the document as XML
+func EncodeXML(w io.Writer, doc *Node) error {
+	root := doc.Root()
+	if root == nil || root.assembly == nil {
+		return fmt.Errorf("document has no root assembly")
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	writeXMLElement(bw, root, "", 0)
+	return bw.Flush()
+}
+
+func namespace(n *Node) string {
+	var m *parser.Metaschema
+	switch {
+	case n.assembly != nil:
+		m = n.assembly.Metaschema
+	case n.field != nil:
+		m = n.field.Metaschema
+	}
+	if m == nil {
+		return ""
+	}
+	return m.XmlNamespace()
+}
+
+func writeStartTag(w *bufio.Writer, name, ns, parentNS string, depth int) {
+	w.WriteString(strings.Repeat("  ", depth) + "<" + name)
+	if ns != "" && ns != parentNS {
+		w.WriteString(` xmlns="` + escapeAttr(ns) + `"`)
+	}
+}
+
+func writeXMLElement(w *bufio.Writer, n *Node, parentNS string, depth int) {
+	ns := namespace(n)
+	writeStartTag(w, n.name, ns, parentNS, depth)
+	for _, f := range n.flags {
+		if f.Known() {
+			w.WriteString(" " + f.name + `="` + escapeAttr(f.value) + `"`)
+		}
+	}
+	if n.field != nil {
+		switch {
+		case n.markup != "":
+			w.WriteString(">" + n.markup + "</" + n.name + ">\n")
+		case n.value != "":
+			w.WriteString(">" + escapeText(n.value) + "</" + n.name + ">\n")
+		default:
+			w.WriteString("/>\n")
+		}
+		return
+	}
+	items := modelItems(n.assembly)
+	if len(n.children) == 0 || len(items) == 0 {
+		w.WriteString("/>\n")
+		return
+	}
+	w.WriteString(">\n")
+	for _, item := range items {
+		nodes := n.childrenOf(item)
+		if len(nodes) == 0 {
+			continue
+		}
+		switch {
+		case isUnwrapped(item):
+			// markup is written as is, re-indenting would change content
+			// of preformatted text
+			for _, c := range nodes {
+				w.WriteString(strings.Repeat("  ", depth+1) + strings.TrimSpace(c.markup) + "\n")
+			}
+		case isGrouped(item):
+			writeStartTag(w, groupAs(item).Name, ns, ns, depth+1)
+			w.WriteString(">\n")
+			for _, c := range nodes {
+				writeXMLElement(w, c, ns, depth+2)
+			}
+			w.WriteString(strings.Repeat("  ", depth+1) + "</" + groupAs(item).Name + ">\n")
+		default:
+			for _, c := range nodes {
+				writeXMLElement(w, c, ns, depth+1)
+			}
+		}
+	}
+	w.WriteString(strings.Repeat("  ", depth) + "</" + n.name + ">\n")
+}
+
+// childrenOf returns children reached through the model item
+func (n *Node) childrenOf(item parser.GoStructItem) []*Node {
+	var result []*Node
+	for _, c := range n.children {
+		if c.item == item {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\n", "&#xA;", "\t", "&#x9;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
+
+// EncodeJSON writes the document as JSON
+func EncodeJSON(w io.Writer, doc *Node) error {
+	v, err := toValue(doc)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	writeJSON(bw, v, 0)
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// EncodeYAML writes the document as YAML
+func EncodeYAML(w io.Writer, doc *Node) error {
+	v, err := toValue(doc)
+	if err != nil {
+		return err
+	}
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(toYAML(v)); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+func toValue(doc *Node) (*value, error) {
+	root := doc.Root()
+	if root == nil || root.assembly == nil {
+		return nil, fmt.Errorf("document has no root assembly")
+	}
+	v := newObject()
+	v.set(root.name, assemblyValue(root, ""))
+	return v, nil
+}
+
+func newObject() *value {
+	return &value{kind: objectValue, object: map[string]*value{}}
+}
+
+// assemblyValue converts assembly to JSON object, keyFlag is left out as it
+// is used as the key of BY_KEY group
+func assemblyValue(n *Node, keyFlag string) *value {
+	v := newObject()
+	flagValues(v, n, keyFlag)
+	for _, item := range modelItems(n.assembly) {
+		nodes := n.childrenOf(item)
+		if len(nodes) == 0 {
+			continue
+		}
+		v.set(item.JsonName(), itemsValue(item, nodes))
+	}
+	return v
+}
+
+func itemsValue(item parser.GoStructItem, nodes []*Node) *value {
+	ga := groupAs(item)
+	if ga == nil && len(nodes) == 1 {
+		return itemValue(nodes[0], "")
+	}
+	if ga != nil && ga.ByKey() {
+		if keyFlag := jsonKey(item); keyFlag != "" {
+			v := newObject()
+			for _, n := range nodes {
+				key := n.Flag(keyFlag)
+				if key == nil {
+					// cannot be keyed, fall back to an array
+					return arrayOf(nodes, "")
+				}
+				v.set(key.value, itemValue(n, keyFlag))
+			}
+			return v
+		}
+	}
+	if ga != nil && ga.SingletonOrArray() && len(nodes) == 1 {
+		return itemValue(nodes[0], "")
+	}
+	return arrayOf(nodes, "")
+}
+
+func arrayOf(nodes []*Node, keyFlag string) *value {
+	v := &value{kind: arrayValue}
+	for _, n := range nodes {
+		v.array = append(v.array, itemValue(n, keyFlag))
+	}
+	return v
+}
+
+func itemValue(n *Node, keyFlag string) *value {
+	if n.assembly != nil {
+		return assemblyValue(n, keyFlag)
+	}
+	return fieldValue(n, keyFlag)
+}
+
+func fieldValue(n *Node, keyFlag string) *value {
+	content := scalarOf(n)
+	hasFlags := false
+	for i := range n.field.Flags {
+		if n.field.Flags[i].XmlName() != keyFlag {
+			hasFlags = true
+		}
+	}
+	if !hasFlags {
+		return content
+	}
+	v := newObject()
+	flagValues(v, n, keyFlag)
+	if n.value != "" || n.markup != "" {
+		v.set(n.field.JsonName(), content)
+	}
+	return v
+}
+
+func flagValues(v *value, n *Node, keyFlag string) {
+	for _, f := range n.flags {
+		if f.Known() && f.name != keyFlag {
+			v.set(f.flag.JsonName(), scalarOf(f))
+		}
+	}
+}
+
+// scalarOf converts value of field or flag, numbers and booleans become
+// JSON literals and markup becomes Markdown
+func scalarOf(n *Node) *value {
+	v := &value{kind: scalarValue, scalar: n.value}
+	if n.field != nil && n.field.IsMarkup() {
+		toMarkdown := markup.ToMarkdown
+		if n.field.AsType == parser.AsTypeMarkupLine {
+			toMarkdown = markup.ToMarkdownLine
+		}
+		if md, err := toMarkdown(n.markup); err == nil {
+			v.scalar = md
+		}
+		return v
+	}
+	switch asType := string(n.AsType()); asType {
+	case "boolean":
+		switch n.value {
+		case "true", "1":
+			v.scalar, v.literal = "true", true
+		case "false", "0":
+			v.scalar, v.literal = "false", true
+		}
+	case "integer", "nonNegativeInteger", "non-negative-integer", "positiveInteger", "positive-integer", "decimal":
+		v.literal = datatype.Check(asType, n.value) == nil
+	}
+	return v
+}
+
+func writeJSON(w *bufio.Writer, v *value, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	switch v.kind {
+	case objectValue:
+		if len(v.keys) == 0 {
+			w.WriteString("{}")
+			return
+		}
+		w.WriteString("{\n")
+		for i, key := range v.keys {
+			w.WriteString(indent)
+			writeJSONString(w, key)
+			w.WriteString(": ")
+			writeJSON(w, v.object[key], depth+1)
+			if i < len(v.keys)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(strings.Repeat("  ", depth) + "}")
+	case arrayValue:
+		if len(v.array) == 0 {
+			w.WriteString("[]")
+			return
+		}
+		w.WriteString("[\n")
+		for i, item := range v.array {
+			w.WriteString(indent)
+			writeJSON(w, item, depth+1)
+			if i < len(v.array)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(strings.Repeat("  ", depth) + "]")
+	default:
+		if v.literal {
+			w.WriteString(v.scalar)
+			return
+		}
+		writeJSONString(w, v.scalar)
+	}
+}
+
+// writeJSONString writes quoted string, unlike json.Marshal it keeps <, >
+// and & as they are common in markup
+func writeJSONString(w *bufio.Writer, s string) {
+	var sb strings.Builder
+	e := json.NewEncoder(&sb)
+	e.SetEscapeHTML(false)
+	_ = e.Encode(s)
+	w.WriteString(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+func toYAML(v *value) *yaml.Node {
+	switch v.kind {
+	case objectValue:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range v.keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, toYAML(v.object[key]))
+		}
+		return n
+	case arrayValue:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v.array {
+			n.Content = append(n.Content, toYAML(item))
+		}
+		return n
+	}
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.scalar}
+	switch {
+	case v.literal && (v.scalar == "true" || v.scalar == "false"):
+		n.Tag = "!!bool"
+	case v.literal && strings.ContainsAny(v.scalar, ".eE"):
+		n.Tag = "!!float"
+	case v.literal:
+		n.Tag = "!!int"
+	case strings.Contains(v.scalar, "\n"):
+		n.Style = yaml.LiteralStyle
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Format is a serialization of metaschema instance documents
+type Format int
+
+const (
+	XML Format = iota
+	JSON
+	YAML
+)
+
+func (f Format) String() string {
+	switch f {
+	case XML:
+		return "xml"
+	case JSON:
+		return "json"
+	case YAML:
+		return "yaml"
+	}
+	return "unknown"
+}
+
+// ParseFormat returns the format of given name
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "xml":
+		return XML, nil
+	case "json":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	}
+	return 0, fmt.Errorf("unknown format '%s', expected one of xml, json, yaml", name)
+}
+
+// DetectFormat guesses the format of the document from the file extension,
+// or from its content when the extension is not recognized
+func DetectFormat(filename string, data []byte) Format {
+	if f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(filename), ".")); err == nil {
+		return f
+	}
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return XML
+	case bytes.HasPrefix(trimmed, []byte("{")), bytes.HasPrefix(trimmed, []byte("[")):
+		return JSON
+	}
+	return YAML
+}
+
+// Decode reads instance document of one of the root assemblies in the given
+// format
+func Decode(data []byte, format Format, roots []*parser.DefineAssembly) (*Node, error) {
+	switch format {
+	case XML:
+		return DecodeXML(data, roots)
+	case JSON:
+		return DecodeJSON(data, roots)
+	case YAML:
+		return DecodeYAML(data, roots)
+	}
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
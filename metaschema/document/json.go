@@ -0,0 +1,357 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gocomply/metaschema/metaschema/markup"
+	"github.com/gocomply/metaschema/metaschema/metapath"
+	"github.com/gocomply/metaschema/metaschema/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// value is a JSON or YAML value together with its position in the source
+type value struct {
+	kind   valueKind
+	keys   []string
+	object map[string]*value
+	array  []*value
+	scalar string
+	// literal marks scalar written as JSON number or boolean
+	literal bool
+
+	line, column int
+}
+
+type valueKind int
+
+const (
+	scalarValue valueKind = iota
+	objectValue
+	arrayValue
+)
+
+func (v *value) set(key string, val *value) {
+	if _, ok := v.object[key]; !ok {
+		v.keys = append(v.keys, key)
+	}
+	v.object[key] = val
+}
+
+// DecodeJSON reads JSON instance of one of the root assemblies. Properties
+// not declared by the metaschema are kept in the tree as unknown nodes.
+func DecodeJSON(data []byte, roots []*parser.DefineAssembly) (*Node, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	v, err := readJSON(d, newLines(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after the document")
+	}
+	return decodeValue(v, roots)
+}
+
+// DecodeYAML reads YAML instance of one of the root assemblies. The YAML
+// document has the same structure as the JSON one.
+func DecodeYAML(data []byte, roots []*parser.DefineAssembly) (*Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errors.New("document is empty")
+	}
+	v, err := readYAML(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	return decodeValue(v, roots)
+}
+
+func readJSON(d *json.Decoder, lines lines) (*value, error) {
+	offset := d.InputOffset()
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	v := &value{}
+	v.line, v.column = lines.position(offset)
+	switch t := t.(type) {
+	case json.Delim:
+		if t == '{' {
+			v.kind = objectValue
+			v.object = map[string]*value{}
+			for d.More() {
+				key, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				val, err := readJSON(d, lines)
+				if err != nil {
+					return nil, err
+				}
+				v.set(key.(string), val)
+			}
+		} else {
+			v.kind = arrayValue
+			for d.More() {
+				val, err := readJSON(d, lines)
+				if err != nil {
+					return nil, err
+				}
+				v.array = append(v.array, val)
+			}
+		}
+		// closing delimiter
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		v.scalar = t
+	case json.Number:
+		v.scalar = t.String()
+	case bool:
+		v.scalar = strconv.FormatBool(t)
+	}
+	return v, nil
+}
+
+func readYAML(n *yaml.Node) (*value, error) {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	v := &value{line: n.Line, column: n.Column}
+	switch n.Kind {
+	case yaml.MappingNode:
+		v.kind = objectValue
+		v.object = map[string]*value{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			val, err := readYAML(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			v.set(n.Content[i].Value, val)
+		}
+	case yaml.SequenceNode:
+		v.kind = arrayValue
+		for _, c := range n.Content {
+			val, err := readYAML(c)
+			if err != nil {
+				return nil, err
+			}
+			v.array = append(v.array, val)
+		}
+	case yaml.ScalarNode:
+		if n.Tag != "!!null" {
+			v.scalar = n.Value
+		}
+	default:
+		return nil, fmt.Errorf("unsupported YAML content at line %d", n.Line)
+	}
+	return v, nil
+}
+
+func decodeValue(v *value, roots []*parser.DefineAssembly) (*Node, error) {
+	if v.kind != objectValue {
+		return nil, errors.New("document must be an object")
+	}
+	doc := newDocument()
+	for _, key := range v.keys {
+		if key == "$schema" {
+			continue
+		}
+		def := rootByName(roots, key)
+		if def == nil {
+			return nil, fmt.Errorf("unknown root property '%s'", key)
+		}
+		if len(doc.children) > 0 {
+			return nil, fmt.Errorf("document has more than one root property")
+		}
+		decodeAssembly(doc.appendChild(newAssembly(def, nil, key)), v.object[key])
+	}
+	if len(doc.children) == 0 {
+		return nil, errors.New("document has no root property")
+	}
+	return doc, nil
+}
+
+func decodeAssembly(n *Node, v *value) {
+	n.line, n.column = v.line, v.column
+	if v.kind != objectValue {
+		n.problem = "expected an object"
+		return
+	}
+	used := map[string]bool{}
+	decodeFlags(n, v, n.assembly.Flags, used)
+	for _, item := range modelItems(n.assembly) {
+		key := item.JsonName()
+		if val, ok := v.object[key]; ok {
+			used[key] = true
+			decodeItems(n, item, val)
+		}
+	}
+	decodeUnknown(n, v, used)
+}
+
+func decodeField(n *Node, v *value) {
+	n.line, n.column = v.line, v.column
+	switch v.kind {
+	case scalarValue:
+		setFieldValue(n, v.scalar)
+	case objectValue:
+		used := map[string]bool{}
+		decodeFlags(n, v, n.field.Flags, used)
+		key := n.field.JsonName()
+		if val, ok := v.object[key]; ok {
+			used[key] = true
+			if val.kind == scalarValue {
+				setFieldValue(n, val.scalar)
+			} else {
+				n.problem = "expected a value of property '" + key + "'"
+			}
+		}
+		decodeUnknown(n, v, used)
+	default:
+		n.problem = "expected a value or an object"
+	}
+}
+
+func setFieldValue(n *Node, s string) {
+	n.value = s
+	switch n.field.AsType {
+	case parser.AsTypeMarkupMultiLine:
+		n.markup = markup.FromMarkdown(s)
+		n.value = markup.Text(n.markup)
+	case parser.AsTypeMarkupLine:
+		n.markup = markup.FromMarkdownLine(s)
+		n.value = markup.Text(n.markup)
+	}
+}
+
+func decodeFlags(n *Node, v *value, defs []parser.Flag, used map[string]bool) {
+	for i := range defs {
+		key := defs[i].JsonName()
+		val, ok := v.object[key]
+		if !ok {
+			continue
+		}
+		used[key] = true
+		flag := n.appendFlag(newFlag(&defs[i], val.scalar))
+		flag.line, flag.column = val.line, val.column
+		if val.kind != scalarValue {
+			flag.problem = "expected a value"
+		}
+	}
+}
+
+// decodeItems reads occurrences of the model item according to its group-as
+func decodeItems(parent *Node, item parser.GoStructItem, v *value) {
+	ga := groupAs(item)
+	switch {
+	case ga == nil:
+		decodeItem(parent.appendChild(newItem(item)), v)
+	case ga.ByKey() && v.kind == objectValue:
+		keyName := jsonKey(item)
+		for _, key := range v.keys {
+			n := parent.appendChild(newItem(item))
+			decodeItem(n, v.object[key])
+			if keyName == "" {
+				continue
+			}
+			var def *parser.Flag
+			if n.assembly != nil {
+				def = flagByName(n.assembly.Flags, keyName)
+			} else {
+				def = flagByName(n.field.Flags, keyName)
+			}
+			if def != nil && n.Flag(keyName) == nil {
+				flag := newFlag(def, key)
+				flag.parent = n
+				flag.line, flag.column = v.object[key].line, v.object[key].column
+				n.flags = append([]*Node{flag}, n.flags...)
+			}
+		}
+	case v.kind == arrayValue:
+		for _, val := range v.array {
+			decodeItem(parent.appendChild(newItem(item)), val)
+		}
+	default:
+		n := parent.appendChild(newItem(item))
+		decodeItem(n, v)
+		if !ga.SingletonOrArray() {
+			n.problem = "expected an array of '" + item.JsonName() + "'"
+		}
+	}
+}
+
+func decodeItem(n *Node, v *value) {
+	if n.assembly != nil {
+		decodeAssembly(n, v)
+	} else {
+		decodeField(n, v)
+	}
+}
+
+// decodeUnknown keeps properties not declared by the metaschema
+func decodeUnknown(n *Node, v *value, used map[string]bool) {
+	for _, key := range v.keys {
+		if !used[key] {
+			appendUnknown(n, key, v.object[key])
+		}
+	}
+}
+
+func appendUnknown(parent *Node, name string, v *value) {
+	switch v.kind {
+	case arrayValue:
+		for _, val := range v.array {
+			appendUnknown(parent, name, val)
+		}
+		return
+	case objectValue:
+		n := parent.appendChild(newUnknown(metapath.AssemblyNode, name, ""))
+		n.line, n.column = v.line, v.column
+		for _, key := range v.keys {
+			appendUnknown(n, key, v.object[key])
+		}
+		if len(n.children) == 0 {
+			n.kind = metapath.FieldNode
+		}
+	default:
+		n := parent.appendChild(newUnknown(metapath.FieldNode, name, v.scalar))
+		n.line, n.column = v.line, v.column
+	}
+}
+
+// lines converts byte offsets to line and column numbers
+type lines struct {
+	data   []byte
+	starts []int
+}
+
+func newLines(data []byte) lines {
+	starts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return lines{data: data, starts: starts}
+}
+
+// position returns line and column of the first token at or after the offset
+func (l lines) position(offset int64) (int, int) {
+	i := int(offset)
+	for i < len(l.data) && bytes.IndexByte([]byte(" \t\r\n,:"), l.data[i]) >= 0 {
+		i++
+	}
+	line := sort.Search(len(l.starts), func(n int) bool { return l.starts[n] > i })
+	return line, i - l.starts[line-1] + 1
+}
@@ -0,0 +1,108 @@
+package document
+
+import (
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// modelItems returns assembly and field references of the model in the order
+// of their declaration, including members of choices
+func modelItems(da *parser.DefineAssembly) []parser.GoStructItem {
+	if da == nil || da.Model == nil {
+		return nil
+	}
+	return da.Model.GoStructItems()
+}
+
+func groupAs(item parser.GoStructItem) *parser.GroupAs {
+	switch v := item.(type) {
+	case *parser.Assembly:
+		return v.GroupAs
+	case *parser.Field:
+		return v.GroupAs
+	}
+	return nil
+}
+
+// isGrouped returns true when occurrences of the item are wrapped in XML
+// element named by group-as
+func isGrouped(item parser.GoStructItem) bool {
+	ga := groupAs(item)
+	return ga != nil && ga.InXml == "GROUPED"
+}
+
+// isUnwrapped returns true for markup-multiline fields whose content appears
+// directly within the parent element
+func isUnwrapped(item parser.GoStructItem) bool {
+	f, ok := item.(*parser.Field)
+	return ok && f.InXml == "UNWRAPPED"
+}
+
+// jsonKey returns the name of the flag that is used as a key of BY_KEY
+// groups, or empty string when not declared
+func jsonKey(item parser.GoStructItem) string {
+	var key *parser.JsonKey
+	switch v := item.(type) {
+	case *parser.Assembly:
+		key = v.Def.JsonKey
+	case *parser.Field:
+		key = v.Def.JsonKey
+	}
+	if key == nil {
+		return ""
+	}
+	return key.FlagName
+}
+
+func itemByXmlName(da *parser.DefineAssembly, name string) parser.GoStructItem {
+	for _, item := range modelItems(da) {
+		if !isUnwrapped(item) && item.XmlName() == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func itemByGroupName(da *parser.DefineAssembly, name string) parser.GoStructItem {
+	for _, item := range modelItems(da) {
+		if isGrouped(item) && groupAs(item).Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func unwrappedItem(da *parser.DefineAssembly) parser.GoStructItem {
+	for _, item := range modelItems(da) {
+		if isUnwrapped(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+func flagByName(flags []parser.Flag, name string) *parser.Flag {
+	for i := range flags {
+		if flags[i].XmlName() == name {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// newItem creates assembly or field node reached through the model item
+func newItem(item parser.GoStructItem) *Node {
+	switch v := item.(type) {
+	case *parser.Assembly:
+		return newAssembly(v.Def, item, v.XmlName())
+	case *parser.Field:
+		return newField(v.Def, item)
+	}
+	return nil
+}
+
+// blockMarkup lists elements of markup-multiline that may appear directly
+// within an assembly as content of an unwrapped field
+var blockMarkup = map[string]bool{
+	"p": true, "ul": true, "ol": true, "pre": true, "table": true, "blockquote": true, "hr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
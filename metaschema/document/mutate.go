@@ -0,0 +1,147 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/gocomply/metaschema/metaschema/datatype"
+	"github.com/gocomply/metaschema/metaschema/markup"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// TypedValue returns the value of a field or flag converted according to its
+// data type, see datatype.Parse
+func (n *Node) TypedValue() (interface{}, error) {
+	if n.field == nil && n.flag == nil {
+		return nil, fmt.Errorf("%s has no value", n.Path())
+	}
+	if n.field != nil && n.field.IsMarkup() {
+		return n.value, nil
+	}
+	asType := string(n.AsType())
+	if asType == "" {
+		asType = "string"
+	}
+	return datatype.Parse(asType, n.value)
+}
+
+// SetValue replaces the value of a field or flag. Value of a markup field
+// is set as plain text.
+func (n *Node) SetValue(value string) error {
+	switch {
+	case n.field != nil && n.field.AsType == parser.AsTypeMarkupMultiLine:
+		n.markup = "<p>" + escapeText(value) + "</p>"
+	case n.field != nil && n.field.IsMarkup():
+		n.markup = escapeText(value)
+	case n.field == nil && n.flag == nil:
+		return fmt.Errorf("%s has no value", n.Path())
+	}
+	n.value = value
+	n.problem = ""
+	return nil
+}
+
+// SetMarkup replaces the content of a markup-line or markup-multiline field
+func (n *Node) SetMarkup(xhtml string) error {
+	if n.field == nil || !n.field.IsMarkup() {
+		return fmt.Errorf("%s is not a markup field", n.Path())
+	}
+	if err := markup.Check(xhtml); err != nil {
+		return err
+	}
+	n.markup = xhtml
+	n.value = markup.Text(xhtml)
+	n.problem = ""
+	return nil
+}
+
+func (n *Node) flagDefinitions() []parser.Flag {
+	switch {
+	case n.assembly != nil:
+		return n.assembly.Flags
+	case n.field != nil:
+		return n.field.Flags
+	}
+	return nil
+}
+
+// SetFlag sets value of the named flag, adding the flag when not present
+func (n *Node) SetFlag(name, value string) (*Node, error) {
+	defs := n.flagDefinitions()
+	def := flagByName(defs, name)
+	if def == nil {
+		return nil, fmt.Errorf("%s has no flag '%s'", n.Path(), name)
+	}
+	if f := n.Flag(name); f != nil {
+		f.value = value
+		return f, nil
+	}
+	// keep flags in the order of their definition
+	order := map[string]int{}
+	for i := range defs {
+		order[defs[i].XmlName()] = i
+	}
+	pos := 0
+	for i, f := range n.flags {
+		if o, ok := order[f.name]; ok && o < order[name] {
+			pos = i + 1
+		}
+	}
+	f := newFlag(def, value)
+	f.parent = n
+	n.flags = append(n.flags[:pos], append([]*Node{f}, n.flags[pos:]...)...)
+	return f, nil
+}
+
+// AddChild appends new assembly or field of given name to the assembly. The
+// child is placed after the existing children that precede it in the model.
+func (n *Node) AddChild(name string) (*Node, error) {
+	if n.assembly == nil {
+		return nil, fmt.Errorf("%s is not an assembly", n.Path())
+	}
+	items := modelItems(n.assembly)
+	index := -1
+	for i, item := range items {
+		if item.XmlName() == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%s has no model item '%s'", n.Path(), name)
+	}
+	order := map[parser.GoStructItem]int{}
+	for i, item := range items {
+		order[item] = i
+	}
+	pos := 0
+	for i, c := range n.children {
+		if o, ok := order[c.item]; ok && o <= index {
+			pos = i + 1
+		}
+	}
+	child := newItem(items[index])
+	child.parent = n
+	n.children = append(n.children[:pos], append([]*Node{child}, n.children[pos:]...)...)
+	return child, nil
+}
+
+// Remove detaches the node from its parent
+func (n *Node) Remove() {
+	p := n.parent
+	if p == nil {
+		return
+	}
+	n.parent = nil
+	for i, f := range p.flags {
+		if f == n {
+			p.flags = append(p.flags[:i], p.flags[i+1:]...)
+			return
+		}
+	}
+	for i, c := range p.children {
+		if c == n {
+			p.children = append(p.children[:i], p.children[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package document
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	doc, err := loadSchema(t).Decode([]byte(`{"doc": {"version": 2, "title": "A *doc*",
+  "props": {"status": {"value": "draft", "class": "x"}, "owner": {"value": "me"}},
+  "notes": ["one", "two"],
+  "parts": [{"name": "a", "count": {"value": 12, "unit": "pages"}}, {"name": "b"}]}}`), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"/doc/title", []string{"A doc"}},
+		{"/doc/@version", []string{"2"}},
+		{"prop/@name", []string{"status", "owner"}},
+		{"prop[@class='x']", []string{"draft"}},
+		{"note", []string{"one", "two"}},
+		{"part[count > 10]/@name", []string{"a"}},
+		{"//count/@unit", []string{"pages"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			nodes, err := doc.Root().Select(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, n := range nodes {
+				got = append(got, n.Value())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	title, err := doc.Root().Select("title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := title[0].Markup(); got != "A <em>doc</em>" {
+		t.Errorf("markdown of JSON decoded as %q", got)
+	}
+	count, err := doc.Root().Select("part/count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := count[0].TypedValue(); err != nil || v != int64(12) {
+		t.Errorf("typed value of count is %#v, %v", v, err)
+	}
+}
+
+// TestEdit builds a document through the API, flags and children are kept
+// in the order of their definitions
+func TestEdit(t *testing.T) {
+	schema := loadSchema(t)
+	doc, err := schema.New("doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Root()
+	steps := []struct {
+		name string
+		edit func() error
+	}{
+		{"part before title", func() error {
+			part, err := root.AddChild("part")
+			if err != nil {
+				return err
+			}
+			_, err = part.SetFlag("name", "a")
+			return err
+		}},
+		{"title", func() error {
+			title, err := root.AddChild("title")
+			if err != nil {
+				return err
+			}
+			return title.SetMarkup("Edited <em>doc</em>")
+		}},
+		{"draft before version", func() error {
+			_, err := root.SetFlag("draft", "true")
+			return err
+		}},
+		{"version", func() error {
+			_, err := root.SetFlag("version", "1")
+			return err
+		}},
+		{"removed note", func() error {
+			note, err := root.AddChild("note")
+			if err != nil {
+				return err
+			}
+			note.Remove()
+			return nil
+		}},
+	}
+	for _, s := range steps {
+		if err := s.edit(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<doc xmlns="http://example.com/ns/doc" version="1" draft="true">
+  <title>Edited <em>doc</em></title>
+  <part name="a"/>
+</doc>
+`
+	if got := encode(t, doc, XML); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	for name, err := range map[string]error{
+		"unknown flag":      func() error { _, err := root.SetFlag("bogus", "x"); return err }(),
+		"unknown child":     func() error { _, err := root.AddChild("bogus"); return err }(),
+		"value of assembly": root.SetValue("x"),
+		"markup of part":    root.ChildrenNamed("part")[0].SetMarkup("x"),
+	} {
+		if err == nil {
+			t.Errorf("%s accepted", name)
+		}
+	}
+}
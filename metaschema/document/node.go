@@ -0,0 +1,221 @@
+// Package document implements a generic document model for instances of any
+// metaschema. Documents are read from XML, JSON or YAML without generating go
+// code first, each node points back to the metaschema definition it conforms
+// to. Nodes implement metapath.Node, so that metapath expressions of the
+// metaschema constraints can be evaluated against them.
+//
+// Documents can be traversed, modified and encoded again in any of the
+// formats. Use Schema to decode documents of compiled metaschemas.
+package document
+
+import (
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/metapath"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Node is a node of a document: the document itself, an assembly, a field or
+// a flag
+type Node struct {
+	kind     metapath.NodeKind
+	name     string
+	value    string
+	markup   string
+	parent   *Node
+	flags    []*Node
+	children []*Node
+
+	assembly *parser.DefineAssembly
+	field    *parser.DefineField
+	flag     *parser.Flag
+	item     parser.GoStructItem
+
+	// problem describes content that could not be read as expected by the
+	// definition
+	problem      string
+	line, column int
+}
+
+func (n *Node) Kind() metapath.NodeKind {
+	return n.kind
+}
+
+func (n *Node) Name() string {
+	return n.name
+}
+
+func (n *Node) Parent() metapath.Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Flags() []metapath.Node {
+	result := make([]metapath.Node, 0, len(n.flags))
+	for _, f := range n.flags {
+		result = append(result, f)
+	}
+	return result
+}
+
+func (n *Node) Children() []metapath.Node {
+	result := make([]metapath.Node, 0, len(n.children))
+	for _, c := range n.children {
+		result = append(result, c)
+	}
+	return result
+}
+
+// Value returns the value of a field or flag. Value of a markup field is its
+// text content.
+func (n *Node) Value() string {
+	return n.value
+}
+
+// Markup returns the XHTML markup of a markup-line or markup-multiline field.
+// Markdown of JSON and YAML documents is converted to XHTML when decoded.
+func (n *Node) Markup() string {
+	return n.markup
+}
+
+// ParentNode returns the parent node, or nil for the document node
+func (n *Node) ParentNode() *Node {
+	return n.parent
+}
+
+// Root returns the root assembly of the document
+func (n *Node) Root() *Node {
+	for n.parent != nil {
+		n = n.parent
+	}
+	if len(n.children) == 0 {
+		return nil
+	}
+	return n.children[0]
+}
+
+// FlagNodes returns flags of an assembly or a field
+func (n *Node) FlagNodes() []*Node {
+	return n.flags
+}
+
+// ChildNodes returns model children of an assembly or of the document
+func (n *Node) ChildNodes() []*Node {
+	return n.children
+}
+
+// Flag returns the named flag, or nil when the flag is not set
+func (n *Node) Flag(name string) *Node {
+	for _, f := range n.flags {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// ChildrenNamed returns model children of given name
+func (n *Node) ChildrenNamed(name string) []*Node {
+	var result []*Node
+	for _, c := range n.children {
+		if c.name == name {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// AssemblyDefinition returns the definition of an assembly node
+func (n *Node) AssemblyDefinition() *parser.DefineAssembly {
+	return n.assembly
+}
+
+// FieldDefinition returns the definition of a field node
+func (n *Node) FieldDefinition() *parser.DefineField {
+	return n.field
+}
+
+// FlagDefinition returns the definition of a flag node
+func (n *Node) FlagDefinition() *parser.Flag {
+	return n.flag
+}
+
+// ModelItem returns the assembly or field reference within the model of the
+// parent through which the node has been reached. It is nil for the root
+// assembly.
+func (n *Node) ModelItem() parser.GoStructItem {
+	return n.item
+}
+
+// Known returns false for nodes that do not conform to any definition, such
+// as elements or properties not declared by the metaschema
+func (n *Node) Known() bool {
+	return n.kind == metapath.DocumentNode || n.assembly != nil || n.field != nil || n.flag != nil
+}
+
+// Problem describes why the content of the node could not be read as expected
+// by its definition, for example an array found where an object is expected.
+// Empty string is returned for well-formed nodes.
+func (n *Node) Problem() string {
+	return n.problem
+}
+
+// Position returns the line and column of the node in the source document.
+// Zero is returned when the position is not known.
+func (n *Node) Position() (int, int) {
+	return n.line, n.column
+}
+
+// Path returns metapath expression locating the node
+func (n *Node) Path() string {
+	return metapath.Path(n)
+}
+
+// AsType returns the data type of a field or flag value
+func (n *Node) AsType() parser.AsType {
+	switch {
+	case n.field != nil:
+		return n.field.AsType
+	case n.flag != nil:
+		if n.flag.AsType != "" || n.flag.Def == nil {
+			return n.flag.AsType
+		}
+		return n.flag.Def.AsType
+	}
+	return ""
+}
+
+func newDocument() *Node {
+	return &Node{kind: metapath.DocumentNode}
+}
+
+func (n *Node) appendChild(child *Node) *Node {
+	child.parent = n
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *Node) appendFlag(flag *Node) *Node {
+	flag.parent = n
+	n.flags = append(n.flags, flag)
+	return flag
+}
+
+func newAssembly(def *parser.DefineAssembly, item parser.GoStructItem, name string) *Node {
+	return &Node{kind: metapath.AssemblyNode, name: name, assembly: def, item: item}
+}
+
+func newField(def *parser.DefineField, item parser.GoStructItem) *Node {
+	return &Node{kind: metapath.FieldNode, name: def.Name, field: def, item: item}
+}
+
+func newFlag(def *parser.Flag, value string) *Node {
+	return &Node{kind: metapath.FlagNode, name: def.XmlName(), flag: def, value: value}
+}
+
+// newUnknown creates a node for content not declared by the metaschema
+func newUnknown(kind metapath.NodeKind, name, value string) *Node {
+	return &Node{kind: kind, name: name, value: strings.TrimSpace(value)}
+}
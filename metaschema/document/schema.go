@@ -0,0 +1,69 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Schema is a set of compiled metaschemas whose instance documents can be
+// decoded or created
+type Schema struct {
+	roots []*parser.DefineAssembly
+}
+
+// NewSchema returns schema of documents rooted in any root assembly of the
+// metaschemas
+func NewSchema(metaschemas ...*parser.Metaschema) (*Schema, error) {
+	s := &Schema{}
+	seen := map[string]bool{}
+	for _, m := range metaschemas {
+		for _, def := range m.RootAssemblies() {
+			if !seen[def.RootXmlName()] {
+				seen[def.RootXmlName()] = true
+				s.roots = append(s.roots, def)
+			}
+		}
+	}
+	if len(s.roots) == 0 {
+		return nil, errors.New("metaschemas define no root assembly")
+	}
+	return s, nil
+}
+
+// Roots returns definitions of the assemblies that may be used as document
+// root
+func (s *Schema) Roots() []*parser.DefineAssembly {
+	return s.roots
+}
+
+// Decode reads document in the given format
+func (s *Schema) Decode(data []byte, format Format) (*Node, error) {
+	return Decode(data, format, s.roots)
+}
+
+// DecodeFile reads document from the file, the format is detected from the
+// file name or its content
+func (s *Schema) DecodeFile(path string) (*Node, Format, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, 0, err
+	}
+	format := DetectFormat(path, data)
+	doc, err := s.Decode(data, format)
+	return doc, format, err
+}
+
+// New creates a document that consists of an empty root assembly of given
+// name
+func (s *Schema) New(root string) (*Node, error) {
+	def := rootByName(s.roots, root)
+	if def == nil {
+		return nil, fmt.Errorf("unknown root assembly '%s'", root)
+	}
+	doc := newDocument()
+	doc.appendChild(newAssembly(def, nil, root))
+	return doc, nil
+}
@@ -0,0 +1,68 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gocomply/metaschema/metaschema/metapath"
+)
+
+// SkipChildren is returned by WalkFunc to skip flags and children of the node
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called for each node visited by Walk. Error other than
+// SkipChildren stops the walk.
+type WalkFunc func(n *Node) error
+
+// Walk visits the node and all its descendants in document order, flags of
+// a node are visited before its children
+func (n *Node) Walk(fn WalkFunc) error {
+	err := fn(n)
+	if err == SkipChildren {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, f := range n.flags {
+		if err := f.Walk(fn); err != nil {
+			return err
+		}
+	}
+	for _, c := range n.children {
+		if err := c.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Document returns the document node the node belongs to
+func (n *Node) Document() *Node {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// Select evaluates metapath expression in the context of the node and
+// returns the selected nodes
+func (n *Node) Select(expression string) ([]*Node, error) {
+	e, err := metapath.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := e.EvaluateNodes(n)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		v, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("%s selects nodes of another document", expression)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
@@ -0,0 +1,271 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/metapath"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// DecodeXML reads XML instance of one of the root assemblies. Content not
+// declared by the metaschema is kept in the tree as unknown nodes.
+func DecodeXML(data []byte, roots []*parser.DefineAssembly) (*Node, error) {
+	x := &xmlDecoder{d: xml.NewDecoder(bytes.NewReader(data)), data: data}
+	doc := newDocument()
+	for {
+		t, err := x.token()
+		if err == io.EOF {
+			return nil, errors.New("document has no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		def := rootByName(roots, start.Name.Local)
+		if def == nil {
+			return nil, fmt.Errorf("unknown root element <%s>", start.Name.Local)
+		}
+		root := doc.appendChild(newAssembly(def, nil, start.Name.Local))
+		if err := x.assembly(root, start); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+}
+
+func rootByName(roots []*parser.DefineAssembly, name string) *parser.DefineAssembly {
+	for _, def := range roots {
+		if def.RootXmlName() == name {
+			return def
+		}
+	}
+	return nil
+}
+
+type xmlDecoder struct {
+	d    *xml.Decoder
+	data []byte
+
+	// position and offset of the start of the last token
+	line, column int
+	offset       int64
+}
+
+func (x *xmlDecoder) token() (xml.Token, error) {
+	x.line, x.column = x.d.InputPos()
+	x.offset = x.d.InputOffset()
+	return x.d.Token()
+}
+
+func (x *xmlDecoder) position(n *Node) *Node {
+	n.line, n.column = x.line, x.column
+	return n
+}
+
+func (x *xmlDecoder) flags(n *Node, attrs []xml.Attr, defs []parser.Flag) {
+	for _, attr := range attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == xsiNamespace {
+			continue
+		}
+		var flag *Node
+		if def := flagByName(defs, attr.Name.Local); def != nil {
+			flag = newFlag(def, attr.Value)
+		} else {
+			flag = newUnknown(metapath.FlagNode, attr.Name.Local, attr.Value)
+		}
+		flag.line, flag.column = n.line, n.column
+		n.appendFlag(flag)
+	}
+}
+
+func (x *xmlDecoder) assembly(n *Node, start xml.StartElement) error {
+	x.position(n)
+	x.flags(n, start.Attr, n.assembly.Flags)
+	var unwrapped *Node
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.StartElement:
+			name := v.Name.Local
+			if item := itemByXmlName(n.assembly, name); item != nil {
+				unwrapped = nil
+				if err := x.item(n.appendChild(newItem(item)), v); err != nil {
+					return err
+				}
+			} else if item := itemByGroupName(n.assembly, name); item != nil {
+				unwrapped = nil
+				if err := x.group(n, item); err != nil {
+					return err
+				}
+			} else if item := unwrappedItem(n.assembly); item != nil && blockMarkup[name] {
+				if unwrapped == nil {
+					unwrapped = x.position(n.appendChild(newItem(item)))
+				}
+				if err := x.unwrapped(unwrapped); err != nil {
+					return err
+				}
+			} else {
+				unwrapped = nil
+				if err := x.unknown(n.appendChild(newUnknown(metapath.AssemblyNode, name, "")), v); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (x *xmlDecoder) item(n *Node, start xml.StartElement) error {
+	if n.assembly != nil {
+		return x.assembly(n, start)
+	}
+	return x.field(n, start)
+}
+
+// group reads occurrences of the item wrapped in group-as element
+func (x *xmlDecoder) group(parent *Node, item parser.GoStructItem) error {
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.StartElement:
+			if v.Name.Local == item.XmlName() {
+				err = x.item(parent.appendChild(newItem(item)), v)
+			} else {
+				err = x.unknown(parent.appendChild(newUnknown(metapath.AssemblyNode, v.Name.Local, "")), v)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (x *xmlDecoder) field(n *Node, start xml.StartElement) error {
+	x.position(n)
+	x.flags(n, start.Attr, n.field.Flags)
+	if n.field.IsMarkup() {
+		return x.markup(n)
+	}
+	var text strings.Builder
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			if err := x.unknown(n.appendChild(newUnknown(metapath.AssemblyNode, v.Name.Local, "")), v); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			n.value = strings.TrimSpace(text.String())
+			return nil
+		}
+	}
+}
+
+// markup reads content of markup field, keeping its source as well as the
+// text content
+func (x *xmlDecoder) markup(n *Node) error {
+	begin := x.d.InputOffset()
+	var text strings.Builder
+	depth := 0
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				n.markup = strings.TrimSpace(string(x.data[begin:x.offset]))
+				n.value = strings.TrimSpace(text.String())
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// unwrapped appends a block element of unwrapped markup-multiline field
+func (x *xmlDecoder) unwrapped(n *Node) error {
+	begin := x.offset
+	var text strings.Builder
+	depth := 0
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				block := string(x.data[begin:x.d.InputOffset()])
+				if n.markup != "" {
+					n.markup += "\n"
+					n.value += "\n"
+				}
+				n.markup += strings.TrimSpace(block)
+				n.value += strings.TrimSpace(text.String())
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// unknown reads element not declared by the metaschema
+func (x *xmlDecoder) unknown(n *Node, start xml.StartElement) error {
+	x.position(n)
+	x.flags(n, start.Attr, nil)
+	var text strings.Builder
+	for {
+		t, err := x.token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			if err := x.unknown(n.appendChild(newUnknown(metapath.AssemblyNode, v.Name.Local, "")), v); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if len(n.children) == 0 {
+				n.kind = metapath.FieldNode
+				n.value = strings.TrimSpace(text.String())
+			}
+			return nil
+		}
+	}
+}
@@ -1,82 +1,122 @@
 package metaschema
 
 import (
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/gocomply/metaschema/metaschema/parser"
+	"github.com/gocomply/metaschema/metaschema/docs"
+	"github.com/gocomply/metaschema/metaschema/jsonschema"
 	"github.com/gocomply/metaschema/metaschema/templates"
+	"github.com/gocomply/metaschema/metaschema/xsd"
 )
 
+// Generate writes go code of each metaschema found in the directory and of
+// the modules they import. Progress is reported to the standard error, so
+// that the standard output is left to the diagnostics. Errors of all the
+// modules are returned joined, no code is written when there are any.
 func Generate(metaschemaDir, goModule, outputDir string) error {
 	files, err := os.ReadDir(metaschemaDir)
 	if err != nil {
 		return err
 	}
+	loader := NewLoader(goModule, os.Stderr)
+	var errs []error
 	for _, metaschemaPath := range files {
 		if !strings.HasSuffix(metaschemaPath.Name(), ".xml") {
 			continue
 		}
-		fmt.Println("Processing ", metaschemaPath.Name())
-		f, err := os.Open(fmt.Sprintf("%s/%s", metaschemaDir, metaschemaPath.Name()))
-		if err != nil {
-			return err
+		fmt.Fprintf(os.Stderr, "Processing %s\n", metaschemaPath.Name())
+		if _, err := loader.Load(filepath.Join(metaschemaDir, metaschemaPath.Name())); err != nil && !containsError(errs, err) {
+			// module that failed to load is reported once, even if
+			// other modules import it
+			errs = append(errs, err)
 		}
-		defer func() {
-			if err := f.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "Troubles while closing file: %v", err)
-			}
-		}()
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	// imported modules are generated as well, even when found elsewhere
+	return templates.GenerateModules(loader.Modules(), outputDir)
+}
 
-		meta, err := decode(metaschemaDir, goModule, f)
-		if err != nil {
-			return err
+func containsError(errs []error, err error) bool {
+	for _, e := range errs {
+		if e == err {
+			return true
 		}
+	}
+	return false
+}
 
-		if err := templates.GenerateAll(meta, outputDir); err != nil {
+// GenerateJSONSchema writes JSON Schema of each metaschema found in the
+// directory that defines a root assembly
+func GenerateJSONSchema(metaschemaDir, outputDir string) error {
+	metaschemas, err := Load(metaschemaDir)
+	if err != nil {
+		return err
+	}
+	for _, meta := range metaschemas {
+		if !meta.ContainsRootElement() {
+			continue
+		}
+		if err := jsonschema.GenerateAll(meta, outputDir); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func decode(metaschemaDir, goModule string, r io.Reader) (*parser.Metaschema, error) {
-	var meta parser.Metaschema
-
-	d := xml.NewDecoder(r)
-
-	if err := d.Decode(&meta); err != nil {
-		return nil, fmt.Errorf("Error decoding metaschema: %s", err)
+// GenerateXSD writes XML Schema of each metaschema found in the directory
+func GenerateXSD(metaschemaDir, outputDir string) error {
+	metaschemas, err := Load(metaschemaDir)
+	if err != nil {
+		return err
 	}
-
-	for _, imported := range meta.Import {
-		if imported.Href == nil {
-			return nil, fmt.Errorf("import element in %s is missing 'href' attribute", r)
-		}
-		imf, err := os.Open(fmt.Sprintf("%s/%s", metaschemaDir, imported.Href.URL.String()))
-		if err != nil {
-			return nil, err
+	for _, meta := range metaschemas {
+		if err := xsd.GenerateAll(meta, outputDir); err != nil {
+			return err
 		}
-		defer func() {
-			if err := imf.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "Troubles while closing file: %v", err)
-			}
-		}()
-
-		fmt.Printf("  Processing imported href: %s\n", imported.Href.URL.String())
-		importedMeta, err := decode(metaschemaDir, goModule, imf)
+	}
+	return nil
+}
 
-		if err != nil {
-			return nil, err
+// GenerateDocs writes reference documentation of each metaschema found in the
+// directory, format is either markdown or html
+func GenerateDocs(metaschemaDir, outputDir, format string) error {
+	f, err := docs.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	metaschemas, err := Load(metaschemaDir)
+	if err != nil {
+		return err
+	}
+	for _, ref := range docs.Build(metaschemas, f) {
+		if err := templates.GenerateDocs(ref, f, outputDir); err != nil {
+			return err
 		}
-
-		meta.ImportedMetaschema = append(meta.ImportedMetaschema, *importedMeta)
 	}
-	err := meta.Compile()
-	meta.GoMod = goModule
+	return nil
+}
 
-	return &meta, err
+// GenerateProto writes protocol buffers schema of each metaschema found in the
+// directory and go conversions between the messages and the models written by
+// Generate into the same outputDir. Run Generate first, then GenerateProto,
+// then protoc-gen-go on the .proto files; the models do not depend on the
+// conversions, which live in <package>/<package>pbconv.
+func GenerateProto(metaschemaDir, goModule, outputDir string) error {
+	metaschemas, err := NewLoader(goModule, io.Discard).LoadDir(metaschemaDir)
+	if err != nil {
+		return err
+	}
+	for _, meta := range metaschemas {
+		if err := templates.GenerateProto(meta, outputDir); err != nil {
+			return err
+		}
+	}
+	return nil
 }
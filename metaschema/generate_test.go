@@ -0,0 +1,110 @@
+package metaschema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// TestGenerateImports generates modules importing each other, the modules
+// are rendered concurrently, run with -race to check they share no mutable
+// state
+func TestGenerateImports(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		outputDir := t.TempDir()
+		if err := Generate(filepath.Join("testdata", "imports"), "example.com/out", outputDir); err != nil {
+			t.Fatal(err)
+		}
+		for _, pkg := range []string{"base", "common", "catalog"} {
+			if _, err := os.Stat(filepath.Join(outputDir, pkg, "generated_models.go")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		source, err := os.ReadFile(filepath.Join(outputDir, "catalog", "generated_models.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			`/common"`,
+			"type CatalogMetadata struct",
+			"type ControlStatement = markup.Multiline",
+		} {
+			if !strings.Contains(string(source), want) {
+				t.Errorf("generated catalog is missing %s", want)
+			}
+		}
+	}
+}
+
+func TestGenerateReportsAllModules(t *testing.T) {
+	outputDir := t.TempDir()
+	err := Generate(filepath.Join("testdata", "broken"), "example.com/out", outputDir)
+	if err == nil {
+		t.Fatal("broken modules generated")
+	}
+	diagnostics := parser.Diagnostics(err)
+	var files []string
+	for _, d := range diagnostics {
+		files = append(files, filepath.Base(d.File))
+	}
+	// third.xml fails by its import of first.xml, which is reported once
+	if got := strings.Join(files, ","); got != "first.xml,second.xml" {
+		t.Errorf("got diagnostics of %s, want first.xml,second.xml: %v", got, err)
+	}
+	if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+		t.Errorf("code written despite errors")
+	}
+}
+
+func TestGenerateDocs(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+		want   []string
+	}{
+		{"markdown", "catalog.md", []string{
+			"# Catalog Reference\n",
+			"- [metadata](#assembly-catalog-assembly-metadata) — Metadata\n",
+			"| [`@id`](base.md#flag-id) | `id` | 1 | `NCName` | Identifier of the item |\n",
+			"| [`prop`](common.md#field-prop) | `props` | field `string` | 0..∞ | object by key in JSON | A named value |\n",
+			"- `/catalog/metadata/version`\n",
+		}},
+		{"html", "catalog.html", []string{
+			"<title>Catalog Reference</title>",
+			`<li><a href="#assembly-catalog-assembly-metadata">metadata</a> — Metadata</li>`,
+			`<tr><td><a href="base.html#flag-id"><code>@id</code></a></td><td><code>id</code></td><td>1</td>`,
+			`<td>single item or array in JSON</td>`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := GenerateDocs(filepath.Join("testdata", "imports"), outputDir, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := os.ReadDir(outputDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 3 {
+				t.Errorf("got %d files, want reference of each of 3 modules", len(entries))
+			}
+			source, err := os.ReadFile(filepath.Join(outputDir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(source), want) {
+					t.Errorf("%s is missing %s:\n%s", tt.file, want, source)
+				}
+			}
+		})
+	}
+
+	if err := GenerateDocs(filepath.Join("testdata", "imports"), t.TempDir(), "pdf"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
@@ -0,0 +1,44 @@
+package jsonschema
+
+import "github.com/gocomply/metaschema/metaschema/parser"
+
+// datatypes maps data types to JSON Schema of their JSON representation.
+// Patterns follow the lexical rules of the datatype package written as
+// ECMA-262 regular expressions.
+var datatypes = map[parser.AsType]Schema{
+	parser.AsTypeString:             {Type: "string", Pattern: `\S`},
+	parser.AsTypeMixed:              {Type: "string"},
+	parser.AsTypeMarkupLine:         {Type: "string"},
+	parser.AsTypeMarkupMultiLine:    {Type: "string"},
+	parser.AsTypeToken:              {Type: "string", Pattern: `^[^\s](?:[^\s]| [^\s])*$`},
+	parser.AsTypeNCName:             {Type: "string", Pattern: ncNamePattern},
+	parser.AsTypeID:                 {Type: "string", Pattern: ncNamePattern},
+	parser.AsTypeIDRef:              {Type: "string", Pattern: ncNamePattern},
+	parser.AsTypeNMToken:            {Type: "string", Pattern: `^[\p{L}\p{N}_\-.:\u00B7\u0300-\u036F\u203F-\u2040]+$`},
+	parser.AsTypeUUID:               {Type: "string", Pattern: `^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[45][0-9A-Fa-f]{3}-[89ABab][0-9A-Fa-f]{3}-[0-9A-Fa-f]{12}$`},
+	parser.AsTypeEmail:              {Type: "string", Format: "email", Pattern: `^[^\s@]+@[^\s@]+$`},
+	parser.AsTypeHostname:           {Type: "string", Format: "idn-hostname"},
+	parser.AsTypeBoolean:            {Type: "boolean"},
+	parser.AsTypeInteger:            {Type: "integer"},
+	parser.AsTypeNonNegativeInteger: {Type: "integer", Minimum: intPtr(0)},
+	parser.AsTypePositiveInteger:    {Type: "integer", Minimum: intPtr(1)},
+	parser.AsTypeDecimal:            {Type: "number"},
+	parser.AsTypeDate:               {Type: "string", Pattern: `^-?[0-9]{4,}-[0-9]{2}-[0-9]{2}(Z|[+-][0-9]{2}:[0-9]{2})?$`},
+	parser.AsTypeDateTZ:             {Type: "string", Pattern: `^-?[0-9]{4,}-[0-9]{2}-[0-9]{2}(Z|[+-][0-9]{2}:[0-9]{2})$`},
+	parser.AsTypeDateTime:           {Type: "string", Pattern: `^-?[0-9]{4,}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})?$`},
+	parser.AsTypeDateTimeTZ:         {Type: "string", Format: "date-time", Pattern: `^-?[0-9]{4,}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})$`},
+	parser.AsTypeDayTimeDuration:    {Type: "string", Format: "duration", Pattern: `^-?P([0-9]+D)?(T([0-9]+H)?([0-9]+M)?([0-9]+(\.[0-9]+)?S)?)?$`},
+	parser.AsTypeYearMonthDuration:  {Type: "string", Format: "duration", Pattern: `^-?P([0-9]+Y)?([0-9]+M)?$`},
+	parser.AsTypeBase64:             {Type: "string", ContentEncoding: "base64", Pattern: `^[0-9A-Za-z+/\s]+=*\s*$`},
+	parser.AsTypeURI:                {Type: "string", Format: "uri", Pattern: `^[A-Za-z][A-Za-z0-9+\-.]*:`},
+	parser.AsTypeAnyURI:             {Type: "string", Format: "uri-reference"},
+	parser.AsTypeURIRef:             {Type: "string", Format: "uri-reference"},
+	parser.AsTypeIPv4Address:        {Type: "string", Format: "ipv4"},
+	parser.AsTypeIPv6Address:        {Type: "string", Format: "ipv6"},
+}
+
+const ncNamePattern = `^[\p{L}_][\p{L}\p{N}_\-.\u00B7\u0300-\u036F\u203F-\u2040]*$`
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,351 @@
+// Package jsonschema generates JSON Schema (draft 2020-12) of the JSON
+// representation of compiled metaschemas, that is the JSON read and written
+// by the generated go types and by the document package.
+//
+// Definitions of assemblies and fields become $defs shared by reference,
+// local definitions are inlined. Multiplexed groups follow the in-json
+// setting of group-as: BY_KEY groups are objects keyed by the json-key flag
+// and SINGLETON_OR_ARRAY groups accept a single item or an array. Values are
+// checked against the lexical patterns of their data type and against closed
+// allowed-values lists.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Draft is the URI of the JSON Schema dialect of the generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema or one of its subschemas
+type Schema struct {
+	Schema          string `json:"$schema,omitempty"`
+	ID              string `json:"$id,omitempty"`
+	Comment         string `json:"$comment,omitempty"`
+	Ref             string `json:"$ref,omitempty"`
+	Title           string `json:"title,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Format          string `json:"format,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	Pattern         string `json:"pattern,omitempty"`
+	// Enum lists allowed string values
+	Enum    []string `json:"enum,omitempty"`
+	Minimum *int     `json:"minimum,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is either false or *Schema of the values of
+	// objects keyed by json-key
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	MinProperties        int         `json:"minProperties,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems int     `json:"minItems,omitempty"`
+	MaxItems int     `json:"maxItems,omitempty"`
+
+	OneOf []*Schema          `json:"oneOf,omitempty"`
+	AllOf []*Schema          `json:"allOf,omitempty"`
+	Defs  map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// GenerateAll writes the schema of the metaschema to <package>_schema.json
+// file within baseDir
+func GenerateAll(metaschema *parser.Metaschema, baseDir string) error {
+	schema, err := Generate(metaschema)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(baseDir, os.FileMode(0755)); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(baseDir, metaschema.GoPackageName()+"_schema.json"), append(data, '\n'), os.FileMode(0644))
+}
+
+// Generate builds the schema of documents rooted in the root assemblies of
+// the metaschema
+func Generate(metaschema *parser.Metaschema) (*Schema, error) {
+	roots := metaschema.RootAssemblies()
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("Metaschema '%s' does not define any root assembly", metaschema.GoPackageName())
+	}
+	g := generator{defs: map[string]*Schema{}}
+	schema := &Schema{
+		Schema:  Draft,
+		Comment: comment(metaschema),
+		Type:    "object",
+		Properties: map[string]*Schema{
+			"$schema": {Type: "string", Format: "uri-reference"},
+		},
+		AdditionalProperties: false,
+		Defs:                 g.defs,
+	}
+	if base := metaschema.JsonBaseURI(); base != "" {
+		schema.ID = strings.TrimSuffix(base, "/") + "/" + metaschema.GoPackageName() + "_schema.json"
+	}
+	for _, root := range roots {
+		ref, err := g.assembly(root, "")
+		if err != nil {
+			return nil, err
+		}
+		schema.Properties[root.RootXmlName()] = ref
+		schema.OneOf = append(schema.OneOf, &Schema{Required: []string{root.RootXmlName()}})
+	}
+	if len(roots) == 1 {
+		schema.Required, schema.OneOf = schema.OneOf[0].Required, nil
+	}
+	return schema, nil
+}
+
+func comment(metaschema *parser.Metaschema) string {
+	if metaschema.SchemaName == nil {
+		return ""
+	}
+	return text(metaschema.SchemaName.InnerXML) + ": JSON Schema"
+}
+
+// generator collects $defs of the schema
+type generator struct {
+	defs map[string]*Schema
+}
+
+// assembly returns schema of the assembly definition. The flag named
+// keyFlag is left out, as it is the key of BY_KEY group.
+func (g *generator) assembly(da *parser.DefineAssembly, keyFlag string) (*Schema, error) {
+	if da.IsInline() {
+		return g.assemblySchema(da, keyFlag)
+	}
+	return g.define(defName(da.Metaschema, da.Name, keyFlag), func() (*Schema, error) {
+		return g.assemblySchema(da, keyFlag)
+	})
+}
+
+// field returns schema of the field definition, see assembly
+func (g *generator) field(df *parser.DefineField, keyFlag string) (*Schema, error) {
+	if df.IsInline() {
+		return g.fieldSchema(df, keyFlag)
+	}
+	return g.define(defName(df.Metaschema, df.Name, keyFlag), func() (*Schema, error) {
+		return g.fieldSchema(df, keyFlag)
+	})
+}
+
+// define adds the definition to $defs unless already present and returns
+// reference to it. The definition is registered before it is built, so that
+// recursive models refer to it.
+func (g *generator) define(name string, build func() (*Schema, error)) (*Schema, error) {
+	if _, ok := g.defs[name]; !ok {
+		def := &Schema{}
+		g.defs[name] = def
+		s, err := build()
+		if err != nil {
+			return nil, err
+		}
+		*def = *s
+	}
+	return &Schema{Ref: "#/$defs/" + name}, nil
+}
+
+func defName(metaschema *parser.Metaschema, name, keyFlag string) string {
+	prefix := ""
+	if metaschema != nil {
+		prefix = metaschema.GoPackageName() + ":"
+	}
+	if keyFlag != "" {
+		return prefix + name + ":by-" + keyFlag
+	}
+	return prefix + name
+}
+
+func (g *generator) assemblySchema(da *parser.DefineAssembly, keyFlag string) (*Schema, error) {
+	s := object(da.FormalName, da.Description)
+	if err := g.flags(s, da.Flags, keyFlag); err != nil {
+		return nil, err
+	}
+	if da.Model == nil {
+		return s, nil
+	}
+	for _, item := range da.Model.GoStructItems() {
+		items, err := g.items(item)
+		if err != nil {
+			return nil, err
+		}
+		s.Properties[item.JsonName()] = items
+		if item.Min() > 0 && !item.InChoice() {
+			s.Required = append(s.Required, item.JsonName())
+		}
+	}
+	for i := range da.Model.Choice {
+		choice := &Schema{}
+		for _, item := range da.Model.Choice[i].GoStructItems() {
+			choice.OneOf = append(choice.OneOf, &Schema{Required: []string{item.JsonName()}})
+		}
+		s.AllOf = append(s.AllOf, choice)
+	}
+	return s, nil
+}
+
+func (g *generator) fieldSchema(df *parser.DefineField, keyFlag string) (*Schema, error) {
+	var value *Schema
+	if !df.Empty() {
+		var err error
+		if value, err = g.scalar(df.AsType, df.ClosedAllowedValues()); err != nil {
+			return nil, fmt.Errorf("%w found at <%s> definition", err, df.Name)
+		}
+	}
+	hasFlags := false
+	for i := range df.Flags {
+		if df.Flags[i].XmlName() != keyFlag {
+			hasFlags = true
+		}
+	}
+	if !hasFlags && value != nil {
+		value.Title, value.Description = df.FormalName, text(df.Description)
+		return value, nil
+	}
+	s := object(df.FormalName, df.Description)
+	if err := g.flags(s, df.Flags, keyFlag); err != nil {
+		return nil, err
+	}
+	if value != nil {
+		s.Properties[df.JsonName()] = value
+	}
+	return s, nil
+}
+
+// items returns schema of all the occurrences of the model item
+func (g *generator) items(item parser.GoStructItem) (*Schema, error) {
+	var groupAs *parser.GroupAs
+	var jsonKey *parser.JsonKey
+	switch v := item.(type) {
+	case *parser.Assembly:
+		groupAs, jsonKey = v.GroupAs, v.Def.JsonKey
+	case *parser.Field:
+		groupAs, jsonKey = v.GroupAs, v.Def.JsonKey
+	}
+	if groupAs != nil && groupAs.ByKey() && jsonKey != nil {
+		keyed, err := g.item(item, jsonKey.FlagName)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: keyed, MinProperties: 1}, nil
+	}
+	single, err := g.item(item, "")
+	if err != nil || groupAs == nil {
+		return single, err
+	}
+	array := &Schema{Type: "array", Items: single, MinItems: 1}
+	if item.Max() > 1 {
+		array.MaxItems = item.Max()
+	}
+	if groupAs.SingletonOrArray() {
+		return &Schema{OneOf: []*Schema{single, array}}, nil
+	}
+	return array, nil
+}
+
+func (g *generator) item(item parser.GoStructItem, keyFlag string) (*Schema, error) {
+	switch v := item.(type) {
+	case *parser.Assembly:
+		return g.assembly(v.Def, keyFlag)
+	case *parser.Field:
+		return g.field(v.Def, keyFlag)
+	}
+	return nil, fmt.Errorf("Unknown model item <%s>", item.XmlName())
+}
+
+func (g *generator) flags(s *Schema, flags []parser.Flag, keyFlag string) error {
+	for i := range flags {
+		f := &flags[i]
+		if f.XmlName() == keyFlag {
+			continue
+		}
+		asType, description := f.AsType, f.Description
+		if f.Def != nil {
+			if asType == "" {
+				asType = f.Def.AsType
+			}
+			if description == "" {
+				description = f.Def.Description
+			}
+		}
+		value, err := g.scalar(asType, f.ClosedAllowedValues())
+		if err != nil {
+			return fmt.Errorf("%w found at <%s> flag", err, f.XmlName())
+		}
+		value.Description = text(description)
+		s.Properties[f.JsonName()] = value
+		if f.IsRequired() {
+			s.Required = append(s.Required, f.JsonName())
+		}
+	}
+	return nil
+}
+
+// scalar returns schema of a flag or field value. Data types are shared by
+// $defs, enum of the closed allowed-values is added to string types.
+func (g *generator) scalar(asType parser.AsType, closed []*parser.AllowedValues) (*Schema, error) {
+	if asType == "" {
+		asType = parser.AsTypeString
+	}
+	asType = asType.Canonical()
+	dt, ok := datatypes[asType]
+	if !ok {
+		return nil, fmt.Errorf("Unknown as-type='%s'", asType)
+	}
+	name := asType.DatatypeName()
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = &dt
+	}
+	s := &Schema{Ref: "#/$defs/" + name}
+	if dt.Type == "string" {
+		s.Enum = allowed(closed)
+	}
+	return s, nil
+}
+
+// allowed returns values permitted by all the allowed-values rules
+func allowed(rules []*parser.AllowedValues) []string {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := rules[0].Values()
+	for _, av := range rules[1:] {
+		var both []string
+		for _, v := range result {
+			for _, other := range av.Values() {
+				if v == other {
+					both = append(both, v)
+					break
+				}
+			}
+		}
+		result = both
+	}
+	return result
+}
+
+func object(title, description string) *Schema {
+	return &Schema{
+		Title:                title,
+		Description:          text(description),
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+}
+
+// text collapses white space of a description
+func text(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
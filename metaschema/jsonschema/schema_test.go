@@ -0,0 +1,154 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const catalogMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <schema-name>Catalog</schema-name>
+  <short-name>catalog</short-name>
+  <json-base-uri>http://example.com/ns/catalog</json-base-uri>
+  <define-assembly name="catalog">
+    <formal-name>Catalog</formal-name>
+    <description>A catalog</description>
+    <root-name>catalog</root-name>
+    <flag name="id" as-type="NCName" required="yes"><description>Identifier</description></flag>
+    <model>
+      <field ref="title" required="yes"/>
+      <field ref="prop" max-occurs="unbounded"><group-as name="props" in-json="BY_KEY"/></field>
+      <field ref="note" max-occurs="3"><group-as name="notes" in-json="SINGLETON_OR_ARRAY"/></field>
+      <choice>
+        <field ref="link"/>
+        <field ref="href"/>
+      </choice>
+    </model>
+  </define-assembly>
+  <define-field name="title" as-type="markup-line"><description>A title</description></define-field>
+  <define-field name="prop">
+    <description>A property</description>
+    <json-key flag-name="name"/>
+    <flag name="name" as-type="NCName" required="yes"><description>name</description></flag>
+    <flag name="class" as-type="token"><description>class</description>
+      <constraint>
+        <allowed-values allow-other="no"><enum value="a">A</enum><enum value="b">B</enum></allowed-values>
+      </constraint>
+    </flag>
+  </define-field>
+  <define-field name="note"><description>A note</description></define-field>
+  <define-field name="link" as-type="uri-reference"><description>A link</description></define-field>
+  <define-field name="href" as-type="uri"><description>An href</description></define-field>
+</METASCHEMA>`
+
+// generate returns the schema of the metaschema decoded as generic JSON
+func generate(t *testing.T, src string) map[string]interface{} {
+	t.Helper()
+	meta := &parser.Metaschema{URI: "file:///catalog.xml"}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := Generate(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// lookup returns the value at the slash separated path of object keys and
+// array indexes, nil when there is none
+func lookup(v interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, "/") {
+		switch container := v.(type) {
+		case map[string]interface{}:
+			v = container[key]
+		case []interface{}:
+			var i int
+			if err := json.Unmarshal([]byte(key), &i); err != nil || i >= len(container) {
+				return nil
+			}
+			v = container[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func TestGenerate(t *testing.T) {
+	schema := generate(t, catalogMetaschema)
+	catalog := "$defs/catalog:catalog/"
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$schema", `"https://json-schema.org/draft/2020-12/schema"`},
+		{"$id", `"http://example.com/ns/catalog/catalog_schema.json"`},
+		{"$comment", `"Catalog: JSON Schema"`},
+		{"required", `["catalog"]`},
+		{"properties/catalog", `{"$ref": "#/$defs/catalog:catalog"}`},
+		{catalog + "required", `["id", "title"]`},
+		{catalog + "additionalProperties", `false`},
+		{catalog + "properties/id", `{"$ref": "#/$defs/NCNameDatatype", "description": "Identifier"}`},
+		// BY_KEY group is object keyed by the json-key flag, which is left
+		// out of the members
+		{catalog + "properties/props", `{"type": "object", "minProperties": 1,
+			"additionalProperties": {"$ref": "#/$defs/catalog:prop:by-name"}}`},
+		{"$defs/catalog:prop:by-name/properties/name", `null`},
+		{"$defs/catalog:prop:by-name/properties/class", `{"$ref": "#/$defs/TokenDatatype", "enum": ["a", "b"], "description": "class"}`},
+		{"$defs/catalog:prop:by-name/properties/value", `{"$ref": "#/$defs/StringDatatype"}`},
+		// SINGLETON_OR_ARRAY group accepts the item or array of items
+		{catalog + "properties/notes", `{"oneOf": [
+			{"$ref": "#/$defs/catalog:note"},
+			{"type": "array", "items": {"$ref": "#/$defs/catalog:note"}, "minItems": 1, "maxItems": 3}]}`},
+		// choice requires exactly one of its items
+		{catalog + "allOf", `[{"oneOf": [{"required": ["link"]}, {"required": ["href"]}]}]`},
+		{catalog + "properties/link", `{"$ref": "#/$defs/catalog:link"}`},
+		{"$defs/catalog:link", `{"$ref": "#/$defs/UriReferenceDatatype", "description": "A link"}`},
+		{"$defs/UriReferenceDatatype", `{"type": "string", "format": "uri-reference"}`},
+		{"$defs/catalog:title/$ref", `"#/$defs/MarkupLineDatatype"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			got := lookup(schema, tt.path)
+			if !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("got %s, want %s", gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRequiresRoot(t *testing.T) {
+	meta := &parser.Metaschema{URI: "file:///rootless.xml"}
+	src := `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0"><short-name>rootless</short-name>
+  <define-field name="note"><description>A note</description></define-field></METASCHEMA>`
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(meta); err == nil {
+		t.Error("schema generated without root assembly")
+	}
+}
@@ -0,0 +1,247 @@
+package metaschema
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Loader decodes metaschema modules together with the modules they import.
+// Hrefs of imports are resolved relative to the importing module. Each
+// module is decoded and compiled once and cached by its canonical URI, so
+// that all the importers share the same *parser.Metaschema.
+type Loader struct {
+	goModule string
+	log      io.Writer
+	modules  map[string]*parser.Metaschema
+	// failed keeps the error of modules that could not be loaded, so that
+	// the error is reported once when the module is imported again
+	failed map[string]error
+	// order lists the modules in the order they were compiled, imported
+	// modules precede their importers
+	order []*parser.Metaschema
+	// loading is the chain of modules being decoded, used to detect cycles
+	loading []*url.URL
+}
+
+// NewLoader returns loader of modules whose go code is generated within
+// goModule, imports being decoded are reported to log
+func NewLoader(goModule string, log io.Writer) *Loader {
+	return &Loader{
+		goModule: goModule,
+		log:      log,
+		modules:  map[string]*parser.Metaschema{},
+		failed:   map[string]error{},
+	}
+}
+
+// Load returns the compiled module found at the file path
+func (l *Loader) Load(path string) (*parser.Metaschema, error) {
+	uri, err := fileURI(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.load(uri)
+}
+
+// Modules returns all the modules loaded so far, imported modules precede
+// their importers
+func (l *Loader) Modules() []*parser.Metaschema {
+	return l.order
+}
+
+// LoadDir returns modules of all the .xml files found in the directory
+// followed by the modules they import from elsewhere
+func (l *Loader) LoadDir(metaschemaDir string) ([]*parser.Metaschema, error) {
+	files, err := os.ReadDir(metaschemaDir)
+	if err != nil {
+		return nil, err
+	}
+	var result []*parser.Metaschema
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".xml") {
+			continue
+		}
+		meta, err := l.Load(filepath.Join(metaschemaDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, meta)
+	}
+	for _, meta := range l.order {
+		if !contains(result, meta) {
+			result = append(result, meta)
+		}
+	}
+	return result, nil
+}
+
+func contains(list []*parser.Metaschema, meta *parser.Metaschema) bool {
+	for _, m := range list {
+		if m == meta {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Loader) load(uri *url.URL) (*parser.Metaschema, error) {
+	key := uri.String()
+	if meta, ok := l.modules[key]; ok {
+		return meta, nil
+	}
+	if err, ok := l.failed[key]; ok {
+		return nil, err
+	}
+	meta, err := l.loadModule(uri)
+	if err != nil {
+		l.failed[key] = err
+		return nil, err
+	}
+	l.modules[key] = meta
+	l.order = append(l.order, meta)
+	return meta, nil
+}
+
+func (l *Loader) loadModule(uri *url.URL) (*parser.Metaschema, error) {
+	l.loading = append(l.loading, uri)
+	defer func() {
+		l.loading = l.loading[:len(l.loading)-1]
+	}()
+
+	meta, err := l.decode(uri)
+	if err != nil {
+		return nil, err
+	}
+	for _, imported := range meta.Import {
+		if imported.Href == nil {
+			return nil, parser.NewDiagnostic(meta.SourcePath(), imported.Pos, "Import element is missing 'href' attribute.")
+		}
+		importedPath, err := importPath(uri, imported.Href.URL)
+		if err != nil {
+			return nil, parser.NewDiagnostic(meta.SourcePath(), imported.Pos, "Import of %s: %v", imported.Href.URL, err)
+		}
+		if _, err := os.Stat(importedPath); err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				err = pathErr.Err
+			}
+			return nil, parser.NewDiagnostic(meta.SourcePath(), imported.Pos, "Import of %s: %v", imported.Href.URL, err)
+		}
+		importedURI, err := fileURI(importedPath)
+		if err != nil {
+			return nil, parser.NewDiagnostic(meta.SourcePath(), imported.Pos, "Import of %s: %v", imported.Href.URL, err)
+		}
+		if cycle := l.cycle(importedURI); cycle != nil {
+			return nil, parser.NewDiagnostic(meta.SourcePath(), imported.Pos, "Import cycle: %s", chain(cycle))
+		}
+		if _, ok := l.modules[importedURI.String()]; !ok {
+			fmt.Fprintf(l.log, "  Processing imported href: %s\n", imported.Href.URL.String())
+		}
+		importedMeta, err := l.load(importedURI)
+		if err != nil {
+			return nil, err
+		}
+		meta.ImportedMetaschema = append(meta.ImportedMetaschema, importedMeta)
+	}
+	err = meta.Compile()
+	meta.GoMod = l.goModule
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// cycle returns the chain of modules being loaded that ends by importing
+// the uri again, nil when the import does not close a cycle
+func (l *Loader) cycle(uri *url.URL) []*url.URL {
+	for i, loading := range l.loading {
+		if loading.String() == uri.String() {
+			cycle := append([]*url.URL{}, l.loading[i:]...)
+			return append(cycle, uri)
+		}
+	}
+	return nil
+}
+
+func (l *Loader) decode(uri *url.URL) (*parser.Metaschema, error) {
+	meta := &parser.Metaschema{URI: uri.String()}
+	f, err := os.Open(parser.FilePath(uri))
+	if err != nil {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			err = pathErr.Err
+		}
+		return nil, parser.NewDiagnostic(meta.SourcePath(), parser.Position{}, "Could not open metaschema: %v", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Troubles while closing file: %v", err)
+		}
+	}()
+
+	if err := xml.NewDecoder(f).Decode(meta); err != nil {
+		var pos parser.Position
+		var syntax *xml.SyntaxError
+		if errors.As(err, &syntax) {
+			pos.Line, err = syntax.Line, errors.New(syntax.Msg)
+		}
+		return nil, parser.NewDiagnostic(meta.SourcePath(), pos, "Error decoding metaschema: %v", err)
+	}
+	return meta, nil
+}
+
+// chain lists the modules of import cycle relative to the directory of the
+// first one
+func chain(uris []*url.URL) string {
+	names := make([]string, 0, len(uris))
+	dir := filepath.Dir(parser.FilePath(uris[0]))
+	for _, uri := range uris {
+		path := parser.FilePath(uri)
+		if rel, err := filepath.Rel(dir, path); err == nil {
+			path = rel
+		}
+		names = append(names, path)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// importPath returns path of the file imported by href from the module at
+// uri. Relative hrefs are joined with the directory of the importer as file
+// paths, so that drive letters of Windows paths are kept intact.
+func importPath(uri, href *url.URL) (string, error) {
+	if href.Scheme == "" && href.Host == "" && !strings.HasPrefix(href.Path, "/") {
+		return filepath.Join(filepath.Dir(parser.FilePath(uri)), filepath.FromSlash(href.Path)), nil
+	}
+	resolved := uri.ResolveReference(href)
+	if resolved.Scheme != "file" {
+		return "", errors.New("only local files can be imported")
+	}
+	return parser.FilePath(resolved), nil
+}
+
+// fileURI returns canonical URI of the file, that is its absolute path with
+// symbolic links evaluated. Paths starting with drive letter get leading
+// slash, as in file:///C:/dir/file.xml.
+func fileURI(path string) (*url.URL, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	slashed := filepath.ToSlash(abs)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return &url.URL{Scheme: "file", Path: slashed}, nil
+}
@@ -0,0 +1,105 @@
+package metaschema
+
+import (
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+func TestImportPath(t *testing.T) {
+	tests := []struct {
+		importer string
+		href     string
+		want     string
+	}{
+		{"file:///C:/a/b/c.xml", "d.xml", "C:/a/b/d.xml"},
+		{"file:///C:/a/b/c.xml", "../e.xml", "C:/a/e.xml"},
+		{"file:///C:/a/b/c.xml", "sub/f.xml", "C:/a/b/sub/f.xml"},
+		{"file:///C:/a/b/c.xml", "file:///D:/g.xml", "D:/g.xml"},
+		{"file:///home/a/c.xml", "d.xml", "/home/a/d.xml"},
+		{"file:///home/a/c.xml", "/opt/e.xml", "/opt/e.xml"},
+	}
+	for _, tt := range tests {
+		importer, _ := url.Parse(tt.importer)
+		href, _ := url.Parse(tt.href)
+		got, err := importPath(importer, href)
+		if err != nil {
+			t.Errorf("%s imports %s: %v", tt.importer, tt.href, err)
+			continue
+		}
+		if want := filepath.FromSlash(tt.want); got != want {
+			t.Errorf("%s imports %s: got %s, want %s", tt.importer, tt.href, got, want)
+		}
+	}
+
+	importer, _ := url.Parse("file:///C:/a/c.xml")
+	href, _ := url.Parse("https://example.com/c.xml")
+	if _, err := importPath(importer, href); err == nil {
+		t.Errorf("import of remote module accepted")
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("testdata", "imports", "catalog.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri, err := fileURI(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(uri.Path, "/") {
+		t.Errorf("path of %s is not absolute", uri)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parser.FilePath(uri); got != resolved {
+		t.Errorf("got %s, want %s", got, resolved)
+	}
+}
+
+func TestLoadImports(t *testing.T) {
+	l := NewLoader("example.com/out", io.Discard)
+	catalog, err := l.Load(filepath.Join("testdata", "imports", "catalog.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range l.Modules() {
+		names = append(names, m.GoPackageName())
+	}
+	if got := strings.Join(names, ","); got != "base,common,catalog" {
+		t.Errorf("got modules %s, want base,common,catalog", got)
+	}
+	if len(catalog.ImportedMetaschema) != 1 || catalog.ImportedMetaschema[0] != l.Modules()[1] {
+		t.Fatalf("catalog does not share the loaded common module")
+	}
+	if common := catalog.ImportedMetaschema[0]; len(common.ImportedMetaschema) != 1 || common.ImportedMetaschema[0] != l.Modules()[0] {
+		t.Errorf("common does not share the loaded base module")
+	}
+
+	again, err := l.Load(filepath.Join("testdata", "imports", "catalog.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != catalog || len(l.Modules()) != 3 {
+		t.Errorf("module loaded twice")
+	}
+}
+
+func TestLoadCycle(t *testing.T) {
+	_, err := NewLoader("example.com/out", io.Discard).Load(filepath.Join("testdata", "cycle", "a.xml"))
+	if err == nil {
+		t.Fatal("import cycle accepted")
+	}
+	want := "Import cycle: " + strings.Join([]string{"a.xml", "b.xml", "a.xml"}, " -> ")
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want error containing %q", err, want)
+	}
+}
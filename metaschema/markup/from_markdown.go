@@ -0,0 +1,400 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FromMarkdown converts Markdown of markup-multiline to XHTML markup
+func FromMarkdown(md string) string {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	return parseBlocks(strings.Split(md, "\n"))
+}
+
+// FromMarkdownLine converts Markdown of markup-line to XHTML markup
+func FromMarkdownLine(md string) string {
+	return parseInline(strings.TrimSpace(strings.ReplaceAll(md, "\r\n", "\n")))
+}
+
+var (
+	headingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	hrRe        = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	listItemRe  = regexp.MustCompile(`^(\s*)([-*+]|[0-9]+\.)\s+(.*)$`)
+	tableSepRe  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(?:\|\s*:?-{3,}:?\s*)*\|?$`)
+	linkTitleRe = regexp.MustCompile(`^(\S*)\s+"((?:[^"\\]|\\.)*)"$`)
+	insertRe    = regexp.MustCompile(`^\{\{\s*insert:\s*([^,\s}]+)\s*,\s*([^\s}]+)\s*\}\}`)
+	punctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+func parseBlocks(lines []string) string {
+	var out []string
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case strings.HasPrefix(trimmed, "```"):
+			j := i + 1
+			for j < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[j]), "```") {
+				j++
+			}
+			out = append(out, "<pre>"+escapeXML(strings.Join(lines[i+1:min(j, len(lines))], "\n"))+"</pre>")
+			i = j + 1
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			out = append(out, "<"+tag+">"+parseInline(m[2])+"</"+tag+">")
+			i++
+		case hrRe.MatchString(trimmed):
+			out = append(out, "<hr/>")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var inner []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				inner = append(inner, strings.TrimPrefix(l, " "))
+				i++
+			}
+			out = append(out, "<blockquote>"+parseBlocks(inner)+"</blockquote>")
+		case listItemRe.MatchString(lines[i]):
+			var list string
+			list, i = parseList(lines, i)
+			out = append(out, list)
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableSepRe.MatchString(strings.TrimSpace(lines[i+1])):
+			var table string
+			table, i = parseTable(lines, i)
+			out = append(out, table)
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])) {
+				para = append(para, strings.TrimLeft(lines[i], " \t"))
+				i++
+			}
+			out = append(out, "<p>"+parseInline(strings.TrimRight(strings.Join(para, "\n"), " \t"))+"</p>")
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// startsBlock returns true for lines that interrupt a paragraph
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || headingRe.MatchString(trimmed) || hrRe.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || listItemRe.MatchString(line)
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func parseList(lines []string, i int) (string, int) {
+	m := listItemRe.FindStringSubmatch(lines[i])
+	indent := len(m[1])
+	ordered := m[2][0] >= '0' && m[2][0] <= '9'
+	var sb strings.Builder
+	if ordered {
+		if start, _ := strconv.Atoi(strings.TrimSuffix(m[2], ".")); start != 1 {
+			sb.WriteString(`<ol start="` + strconv.Itoa(start) + `">`)
+		} else {
+			sb.WriteString("<ol>")
+		}
+	} else {
+		sb.WriteString("<ul>")
+	}
+	for i < len(lines) {
+		m = listItemRe.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != indent || (m[2][0] >= '0' && m[2][0] <= '9') != ordered {
+			break
+		}
+		content := []string{m[3]}
+		contentIndent := indent + len(m[2]) + 1
+		loose := false
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				// blank line belongs to the item when followed by its content
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j < len(lines) && indentation(lines[j]) > indent {
+					loose = true
+					content = append(content, "")
+					continue
+				}
+				break
+			}
+			if indentation(l) <= indent {
+				break
+			}
+			content = append(content, l[min(contentIndent, indentation(l)):])
+		}
+		sb.WriteString("<li>" + parseListItem(content, loose) + "</li>")
+	}
+	if ordered {
+		sb.WriteString("</ol>")
+	} else {
+		sb.WriteString("</ul>")
+	}
+	return sb.String(), i
+}
+
+// parseListItem converts content of list item. Text of tight items is not
+// wrapped in paragraphs.
+func parseListItem(content []string, loose bool) string {
+	if loose {
+		return parseBlocks(content)
+	}
+	n := 1
+	for n < len(content) && !startsBlock(content[n]) {
+		n++
+	}
+	result := parseInline(strings.Join(content[:n], "\n"))
+	if n < len(content) {
+		result += parseBlocks(content[n:])
+	}
+	return result
+}
+
+func parseTable(lines []string, i int) (string, int) {
+	var sb strings.Builder
+	sb.WriteString("<table>")
+	sb.WriteString("<tr>")
+	for _, cell := range splitRow(lines[i]) {
+		sb.WriteString("<th>" + parseInline(cell) + "</th>")
+	}
+	sb.WriteString("</tr>")
+	for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+		sb.WriteString("<tr>")
+		for _, cell := range splitRow(lines[i]) {
+			sb.WriteString("<td>" + parseInline(cell) + "</td>")
+		}
+		sb.WriteString("</tr>")
+	}
+	sb.WriteString("</table>")
+	return sb.String(), i
+}
+
+// splitRow splits table row to cells, escaped pipes are kept within cells
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// parseInline converts inline Markdown to XHTML
+func parseInline(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			sb.WriteString("<br/>")
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(punctuation, s[i+1]) >= 0:
+			sb.WriteString(escapeXML(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == ' ' && strings.HasPrefix(s[i:], "  \n"):
+			sb.WriteString("<br/>")
+			i += 3
+			continue
+		case c == '`':
+			if code, next, ok := codeSpanAt(s, i); ok {
+				sb.WriteString("<code>" + escapeXML(code) + "</code>")
+				i = next
+				continue
+			}
+		case c == '{' && strings.HasPrefix(s[i:], "{{"):
+			if m := insertRe.FindStringSubmatch(s[i:]); m != nil {
+				sb.WriteString(`<insert type="` + escapeAttr(m[1]) + `" id-ref="` + escapeAttr(m[2]) + `"/>`)
+				i += len(m[0])
+				continue
+			}
+		case c == '!' && strings.HasPrefix(s[i:], "!["):
+			if text, href, title, next, ok := linkAt(s, i+1); ok {
+				sb.WriteString(`<img src="` + escapeAttr(href) + `" alt="` + escapeAttr(unescapeMarkdown(text)) + `"` + titleAttr(title) + `/>`)
+				i = next
+				continue
+			}
+		case c == '[':
+			if text, href, title, next, ok := linkAt(s, i); ok {
+				sb.WriteString(`<a href="` + escapeAttr(href) + `"` + titleAttr(title) + `>` + parseInline(text) + `</a>`)
+				i = next
+				continue
+			}
+		case c == '*' || c == '_' && (i == 0 || !isWordByte(s[i-1])):
+			// underscores within words, as in snake_case, are not emphasis
+			delim, tag := s[i:i+1], "em"
+			if strings.HasPrefix(s[i:], delim+delim) {
+				delim, tag = delim+delim, "strong"
+			}
+			if end := closing(s, i+len(delim), delim); end >= 0 {
+				sb.WriteString("<" + tag + ">" + parseInline(s[i+len(delim):end]) + "</" + tag + ">")
+				i = end + len(delim)
+				continue
+			}
+		case c == '"':
+			if end := closing(s, i+1, `"`); end >= 0 {
+				sb.WriteString("<q>" + parseInline(s[i+1:end]) + "</q>")
+				i = end + 1
+				continue
+			}
+		case c == '~' || c == '^':
+			if end := closing(s, i+1, string(c)); end >= 0 && !strings.ContainsAny(s[i+1:end], " \t\n") {
+				tag := "sub"
+				if c == '^' {
+					tag = "sup"
+				}
+				sb.WriteString("<" + tag + ">" + parseInline(s[i+1:end]) + "</" + tag + ">")
+				i = end + 1
+				continue
+			}
+		}
+		sb.WriteString(escapeXML(s[i : i+1]))
+		i++
+	}
+	return sb.String()
+}
+
+// codeSpanAt reads code span starting at i
+func codeSpanAt(s string, i int) (string, int, bool) {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	fence := s[i : i+n]
+	for j := i + n; j < len(s); {
+		k := strings.Index(s[j:], fence)
+		if k < 0 {
+			return "", 0, false
+		}
+		k += j
+		end := k + n
+		if end < len(s) && s[end] == '`' {
+			// longer run of backticks does not close the span
+			for end < len(s) && s[end] == '`' {
+				end++
+			}
+			j = end
+			continue
+		}
+		code := s[i+n : k]
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+			code = code[1 : len(code)-1]
+		}
+		return code, end, true
+	}
+	return "", 0, false
+}
+
+// linkAt reads [text](href "title") starting at i, the title is optional
+func linkAt(s string, i int) (string, string, string, int, bool) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if j+1 >= len(s) || s[j+1] != '(' {
+					return "", "", "", 0, false
+				}
+				end := strings.IndexByte(s[j+2:], ')')
+				if end < 0 {
+					return "", "", "", 0, false
+				}
+				href, title := strings.TrimSpace(s[j+2:j+2+end]), ""
+				if m := linkTitleRe.FindStringSubmatch(href); m != nil {
+					href, title = m[1], unescapeMarkdown(m[2])
+				}
+				return s[i+1 : j], href, title, j + 3 + end, true
+			}
+		}
+	}
+	return "", "", "", 0, false
+}
+
+func titleAttr(title string) string {
+	if title == "" {
+		return ""
+	}
+	return ` title="` + escapeAttr(title) + `"`
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// closing finds the delimiter closing emphasis-like span opened before start.
+// The span must not begin or end with white space.
+func closing(s string, start int, delim string) int {
+	if start >= len(s) || s[start] == ' ' || s[start] == '\n' {
+		return -1
+	}
+	single, double := delim[:1], delim[:1]+delim[:1]
+	for j := start; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '`':
+			if _, next, ok := codeSpanAt(s, j); ok {
+				j = next - 1
+			}
+		case delim == double && strings.HasPrefix(s[j:], single) && !strings.HasPrefix(s[j:], double):
+			// emphasis nested within strong emphasis
+			if end := closing(s, j+1, single); end >= 0 {
+				j = end
+			}
+		case strings.HasPrefix(s[j:], delim):
+			if delim == single && strings.HasPrefix(s[j:], double) {
+				// strong emphasis nested within emphasis
+				if end := closing(s, j+2, double); end >= 0 {
+					j = end + 1
+					continue
+				}
+			}
+			after := j + len(delim)
+			if delim[0] == '_' && after < len(s) && isWordByte(s[after]) {
+				continue
+			}
+			if j > start && s[j-1] != ' ' && s[j-1] != '\n' {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+func unescapeMarkdown(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(punctuation, s[i+1]) >= 0 {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
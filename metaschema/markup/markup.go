@@ -0,0 +1,148 @@
+// Package markup converts metaschema markup between its XML representation,
+// a subset of XHTML, and its JSON/YAML representation, a subset of Markdown.
+//
+// markup-line allows only inline elements (em, strong, code, q, a, img, sub,
+// sup, br, insert), markup-multiline adds blocks: paragraphs, headings,
+// lists, preformatted text, block quotes, horizontal rules and tables.
+//
+// Elements map to Markdown as follows:
+//
+//	<em>x</em>                          *x* (or _x_)
+//	<strong>x</strong>                  **x** (or __x__)
+//	<code>x</code>                      `x`
+//	<q>x</q>                            "x"
+//	<sub>x</sub>, <sup>x</sup>          ~x~, ^x^
+//	<a href="u" title="t">x</a>         [x](u "t")
+//	<img src="u" alt="x"/>              ![x](u)
+//	<br/>                               two spaces at the end of line
+//	<insert type="param" id-ref="id"/>  {{ insert: param, id }}
+//	<h1> to <h6>                        # to ######
+//	<ul>, <ol>                          - item, 1. item
+//	<pre>                               ``` fenced block
+//	<blockquote>                        > quoted
+//	<hr/>                               ---
+//	<table>                             | pipe | table |
+//
+// Characters that would otherwise be read as Markdown syntax are escaped by
+// backslash, XML special characters of Markdown text are escaped as entities.
+package markup
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// element is a node of parsed XHTML markup, text nodes have empty name
+type element struct {
+	name     string
+	attrs    []xml.Attr
+	children []*element
+	text     string
+}
+
+func (e *element) attr(name string) string {
+	for _, a := range e.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (e *element) isText() bool {
+	return e.name == ""
+}
+
+// textContent returns concatenated text of all descendants
+func (e *element) textContent() string {
+	if e.isText() {
+		return e.text
+	}
+	var sb strings.Builder
+	for _, c := range e.children {
+		sb.WriteString(c.textContent())
+	}
+	return sb.String()
+}
+
+// parseXHTML parses markup content, that is the inner XML of markup field
+func parseXHTML(xhtml string) (*element, error) {
+	d := xml.NewDecoder(strings.NewReader("<markup>" + xhtml + "</markup>"))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	d.AutoClose = xml.HTMLAutoClose
+	root := &element{name: "markup"}
+	stack := []*element{}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch v := t.(type) {
+		case xml.StartElement:
+			e := &element{name: v.Name.Local, attrs: v.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			} else if v.Name.Local != "markup" {
+				return nil, errors.New("unexpected content of markup")
+			} else {
+				e = root
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &element{text: string(v)})
+			}
+		}
+	}
+	return root, nil
+}
+
+var blockElements = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "pre": true, "blockquote": true, "hr": true, "table": true,
+}
+
+// IsBlock returns true for elements allowed only in markup-multiline
+func IsBlock(name string) bool {
+	return blockElements[name]
+}
+
+// Check returns an error when the markup is not well-formed
+func Check(xhtml string) error {
+	_, err := parseXHTML(xhtml)
+	return err
+}
+
+// Text returns the text content of XHTML markup
+func Text(xhtml string) string {
+	root, err := parseXHTML(xhtml)
+	if err != nil {
+		return xhtml
+	}
+	return strings.TrimSpace(root.textContent())
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// escapeXML escapes character data for use within XHTML markup
+func escapeXML(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// escapeAttr escapes value of an attribute delimited by double quotes
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
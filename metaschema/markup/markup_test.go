@@ -0,0 +1,77 @@
+package markup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// conformanceCase is a pair of testdata files, NAME.xhtml holding markup in
+// its canonical XHTML form and NAME.md holding the same markup in Markdown
+type conformanceCase struct {
+	name     string
+	xhtml    string
+	markdown string
+}
+
+func readCorpus(t *testing.T, dir string) []conformanceCase {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("testdata", dir, "*.xhtml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no conformance cases found in testdata/%s", dir)
+	}
+	var cases []conformanceCase
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".xhtml")
+		cases = append(cases, conformanceCase{
+			name:     name,
+			xhtml:    readCorpusFile(t, file),
+			markdown: readCorpusFile(t, strings.TrimSuffix(file, ".xhtml")+".md"),
+		})
+	}
+	return cases
+}
+
+func readCorpusFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSuffix(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+}
+
+func testConformance(t *testing.T, dir string, toMarkdown func(string) (string, error), fromMarkdown func(string) string) {
+	for _, c := range readCorpus(t, dir) {
+		t.Run(c.name, func(t *testing.T) {
+			if err := Check(c.xhtml); err != nil {
+				t.Fatalf("invalid markup: %v", err)
+			}
+			md, err := toMarkdown(c.xhtml)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if md != c.markdown {
+				t.Errorf("XHTML to Markdown:\ngot  %q\nwant %q", md, c.markdown)
+			}
+			if xhtml := fromMarkdown(c.markdown); xhtml != c.xhtml {
+				t.Errorf("Markdown to XHTML:\ngot  %q\nwant %q", xhtml, c.xhtml)
+			}
+			if xhtml := fromMarkdown(md); xhtml != c.xhtml {
+				t.Errorf("XHTML round trip:\ngot  %q\nwant %q", xhtml, c.xhtml)
+			}
+		})
+	}
+}
+
+func TestLineConformance(t *testing.T) {
+	testConformance(t, "line", ToMarkdownLine, FromMarkdownLine)
+}
+
+func TestMultilineConformance(t *testing.T) {
+	testConformance(t, "multiline", ToMarkdown, FromMarkdown)
+}
@@ -0,0 +1,271 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToMarkdown converts XHTML markup to Markdown
+func ToMarkdown(xhtml string) (string, error) {
+	root, err := parseXHTML(xhtml)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(renderBlocks(root.children)), nil
+}
+
+// ToMarkdownLine converts XHTML markup of markup-line to Markdown. Block
+// elements are not allowed in markup-line, only their content is kept.
+func ToMarkdownLine(xhtml string) (string, error) {
+	root, err := parseXHTML(xhtml)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(renderInline(root.children)), nil
+}
+
+// renderBlocks renders sequence of block elements separated by blank lines.
+// Inline content found between blocks forms its own paragraph.
+func renderBlocks(nodes []*element) string {
+	var blocks []string
+	var inline []*element
+	flush := func() {
+		if s := strings.TrimSpace(renderInline(inline)); s != "" {
+			blocks = append(blocks, escapeLineStart(s))
+		}
+		inline = nil
+	}
+	for _, n := range nodes {
+		if n.isText() || !IsBlock(n.name) {
+			inline = append(inline, n)
+			continue
+		}
+		flush()
+		blocks = append(blocks, renderBlock(n))
+	}
+	flush()
+	return strings.Join(blocks, "\n\n")
+}
+
+func renderBlock(n *element) string {
+	switch n.name {
+	case "p":
+		return escapeLineStart(strings.TrimSpace(renderInline(n.children)))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(n.name[1:])
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(renderInline(n.children))
+	case "ul", "ol":
+		return renderList(n)
+	case "pre":
+		code := strings.Trim(n.textContent(), "\n")
+		return "```\n" + code + "\n```"
+	case "blockquote":
+		lines := strings.Split(renderBlocks(n.children), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight("> "+l, " ")
+		}
+		return strings.Join(lines, "\n")
+	case "hr":
+		return "---"
+	case "table":
+		return renderTable(n)
+	}
+	return renderInline([]*element{n})
+}
+
+func renderList(list *element) string {
+	var items []string
+	number := 1
+	if start, err := strconv.Atoi(list.attr("start")); err == nil {
+		number = start
+	}
+	for _, li := range list.children {
+		if li.isText() || li.name != "li" {
+			continue
+		}
+		marker := "- "
+		if list.name == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		content := renderListItem(li)
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderListItem renders content of list item, nested lists directly follow
+// the text of the item
+func renderListItem(li *element) string {
+	var parts []string
+	var inline []*element
+	flush := func() {
+		if s := strings.TrimSpace(renderInline(inline)); s != "" {
+			parts = append(parts, s)
+		}
+		inline = nil
+	}
+	for _, n := range li.children {
+		if n.isText() || !IsBlock(n.name) {
+			inline = append(inline, n)
+			continue
+		}
+		flush()
+		parts = append(parts, renderBlock(n))
+	}
+	flush()
+	return strings.Join(parts, "\n")
+}
+
+func renderTable(table *element) string {
+	var rows [][]string
+	var collect func(e *element)
+	collect = func(e *element) {
+		for _, c := range e.children {
+			switch c.name {
+			case "thead", "tbody", "tfoot":
+				collect(c)
+			case "tr":
+				var cells []string
+				for _, cell := range c.children {
+					if cell.name == "th" || cell.name == "td" {
+						text := strings.TrimSpace(renderInline(cell.children))
+						cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+					}
+				}
+				rows = append(rows, cells)
+			}
+		}
+	}
+	collect(table)
+	if len(rows) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for i, row := range rows {
+		sb.WriteString("| " + strings.Join(row, " | ") + " |")
+		if i == 0 {
+			sep := make([]string, len(row))
+			for j := range sep {
+				sep[j] = "---"
+			}
+			sb.WriteString("\n| " + strings.Join(sep, " | ") + " |")
+		}
+		if i < len(rows)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func renderInline(nodes []*element) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		if n.isText() {
+			sb.WriteString(escapeMarkdown(collapseSpace(n.text)))
+			continue
+		}
+		inner := func() string { return renderInline(n.children) }
+		switch n.name {
+		case "em", "i":
+			sb.WriteString("*" + inner() + "*")
+		case "strong", "b":
+			sb.WriteString("**" + inner() + "**")
+		case "code":
+			sb.WriteString(codeSpan(n.textContent()))
+		case "q":
+			sb.WriteString(`"` + inner() + `"`)
+		case "sub":
+			sb.WriteString("~" + inner() + "~")
+		case "sup":
+			sb.WriteString("^" + inner() + "^")
+		case "a":
+			sb.WriteString("[" + inner() + "](" + n.attr("href") + linkTitle(n) + ")")
+		case "img":
+			sb.WriteString("![" + escapeMarkdown(n.attr("alt")) + "](" + n.attr("src") + linkTitle(n) + ")")
+		case "br":
+			sb.WriteString("  \n")
+		case "insert":
+			sb.WriteString("{{ insert: " + n.attr("type") + ", " + n.attr("id-ref") + " }}")
+		default:
+			sb.WriteString(inner())
+		}
+	}
+	return sb.String()
+}
+
+func linkTitle(n *element) string {
+	if title := n.attr("title"); title != "" {
+		return ` "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(title) + `"`
+	}
+	return ""
+}
+
+func codeSpan(code string) string {
+	fence := "`"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return fence + " " + code + " " + fence
+	}
+	return fence + code + fence
+}
+
+func collapseSpace(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			space = true
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	if space {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// markdownSpecial lists characters that have meaning within Markdown inline
+// content and have to be escaped when used literally
+const markdownSpecial = "\\`*_[]\"~^"
+
+func escapeMarkdown(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if strings.ContainsRune(markdownSpecial, r) {
+			sb.WriteByte('\\')
+		} else if r == '{' && strings.HasPrefix(s[i:], "{{") {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+var (
+	blockStart    = regexp.MustCompile(`^(?:#|>|[-+] |---|\|)`)
+	numberedStart = regexp.MustCompile(`^([0-9]+)\. `)
+)
+
+// escapeLineStart escapes text that would be read as start of a block
+func escapeLineStart(s string) string {
+	if blockStart.MatchString(s) {
+		return "\\" + s
+	}
+	return numberedStart.ReplaceAllString(s, `$1\. `)
+}
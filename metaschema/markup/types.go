@@ -0,0 +1,250 @@
+package markup
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Line is the content of markup-line field, XHTML of inline elements. It is
+// represented by the XML content of the field and by Markdown in JSON and
+// YAML.
+type Line string
+
+// Multiline is the content of markup-multiline field, XHTML of block
+// elements. It is represented by the XML content of the field and by
+// Markdown in JSON and YAML.
+type Multiline string
+
+// Unwrapped is the content of markup-multiline field declared with
+// in-xml="UNWRAPPED". Its blocks appear directly within the XML element of
+// the parent assembly, in JSON and YAML it is Markdown.
+type Unwrapped string
+
+// innerXML captures content of an element as found in the document
+type innerXML struct {
+	Content string `xml:",innerxml"`
+}
+
+// XHTML returns the markup
+func (l Line) XHTML() string {
+	return string(l)
+}
+
+// Markdown returns the markup converted to Markdown
+func (l Line) Markdown() (string, error) {
+	return ToMarkdownLine(string(l))
+}
+
+// Text returns the text content of the markup
+func (l Line) Text() string {
+	return Text(string(l))
+}
+
+func (l *Line) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v innerXML
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*l = Line(v.Content)
+	return nil
+}
+
+func (l Line) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(innerXML{string(l)}, start)
+}
+
+func (l *Line) UnmarshalJSON(b []byte) error {
+	var md string
+	if err := json.Unmarshal(b, &md); err != nil {
+		return err
+	}
+	*l = Line(FromMarkdownLine(md))
+	return nil
+}
+
+func (l Line) MarshalJSON() ([]byte, error) {
+	md, err := ToMarkdownLine(string(l))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(md)
+}
+
+func (l *Line) UnmarshalYAML(value *yaml.Node) error {
+	var md string
+	if err := value.Decode(&md); err != nil {
+		return err
+	}
+	*l = Line(FromMarkdownLine(md))
+	return nil
+}
+
+func (l Line) MarshalYAML() (interface{}, error) {
+	return ToMarkdownLine(string(l))
+}
+
+// XHTML returns the markup
+func (m Multiline) XHTML() string {
+	return string(m)
+}
+
+// Markdown returns the markup converted to Markdown
+func (m Multiline) Markdown() (string, error) {
+	return ToMarkdown(string(m))
+}
+
+// Text returns the text content of the markup
+func (m Multiline) Text() string {
+	return Text(string(m))
+}
+
+func (m *Multiline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v innerXML
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*m = Multiline(v.Content)
+	return nil
+}
+
+func (m Multiline) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(innerXML{string(m)}, start)
+}
+
+func (m *Multiline) UnmarshalJSON(b []byte) error {
+	var md string
+	if err := json.Unmarshal(b, &md); err != nil {
+		return err
+	}
+	*m = Multiline(FromMarkdown(md))
+	return nil
+}
+
+func (m Multiline) MarshalJSON() ([]byte, error) {
+	return marshalMarkdown(string(m))
+}
+
+func (m *Multiline) UnmarshalYAML(value *yaml.Node) error {
+	var md string
+	if err := value.Decode(&md); err != nil {
+		return err
+	}
+	*m = Multiline(FromMarkdown(md))
+	return nil
+}
+
+func (m Multiline) MarshalYAML() (interface{}, error) {
+	return ToMarkdown(string(m))
+}
+
+// XHTML returns the markup
+func (u Unwrapped) XHTML() string {
+	return string(u)
+}
+
+// Markdown returns the markup converted to Markdown
+func (u Unwrapped) Markdown() (string, error) {
+	return ToMarkdown(string(u))
+}
+
+// Text returns the text content of the markup
+func (u Unwrapped) Text() string {
+	return Text(string(u))
+}
+
+// UnmarshalXML is called for each block element found within the parent
+// assembly, the block is appended to the content
+func (u *Unwrapped) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v innerXML
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	sb.WriteString(string(*u))
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("<" + start.Name.Local)
+	for _, a := range start.Attr {
+		if a.Name.Space == "" && a.Name.Local != "xmlns" {
+			sb.WriteString(" " + a.Name.Local + `="` + escapeAttr(a.Value) + `"`)
+		}
+	}
+	sb.WriteString(">" + v.Content + "</" + start.Name.Local + ">")
+	*u = Unwrapped(sb.String())
+	return nil
+}
+
+// MarshalXML writes the blocks without any wrapping element. Content of
+// each block is written as is, so that the encoder does not indent inline
+// content.
+func (u Unwrapped) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	d := xml.NewDecoder(strings.NewReader(string(u)))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	d.AutoClose = xml.HTMLAutoClose
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var v innerXML
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		block := xml.StartElement{Name: xml.Name{Local: start.Name.Local}}
+		for _, a := range start.Attr {
+			if a.Name.Space == "" && a.Name.Local != "xmlns" {
+				block.Attr = append(block.Attr, a)
+			}
+		}
+		if err := e.EncodeElement(v, block); err != nil {
+			return err
+		}
+	}
+}
+
+func (u *Unwrapped) UnmarshalJSON(b []byte) error {
+	var md string
+	if err := json.Unmarshal(b, &md); err != nil {
+		return err
+	}
+	*u = Unwrapped(FromMarkdown(md))
+	return nil
+}
+
+func (u Unwrapped) MarshalJSON() ([]byte, error) {
+	return marshalMarkdown(string(u))
+}
+
+func (u *Unwrapped) UnmarshalYAML(value *yaml.Node) error {
+	var md string
+	if err := value.Decode(&md); err != nil {
+		return err
+	}
+	*u = Unwrapped(FromMarkdown(md))
+	return nil
+}
+
+func (u Unwrapped) MarshalYAML() (interface{}, error) {
+	return ToMarkdown(string(u))
+}
+
+func marshalMarkdown(xhtml string) ([]byte, error) {
+	md, err := ToMarkdown(xhtml)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(md)
+}
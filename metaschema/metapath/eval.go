@@ -0,0 +1,558 @@
+package metapath
+
+import (
+	"fmt"
+	"math"
+)
+
+type evalContext struct {
+	item     Item
+	position int
+	size     int
+	vars     map[string]Sequence
+}
+
+func (ctx *evalContext) with(item Item, position, size int) *evalContext {
+	return &evalContext{item: item, position: position, size: size, vars: ctx.vars}
+}
+
+func (ctx *evalContext) bind(name string, value Sequence) *evalContext {
+	vars := make(map[string]Sequence, len(ctx.vars)+1)
+	for k, v := range ctx.vars {
+		vars[k] = v
+	}
+	vars[name] = value
+	return &evalContext{item: ctx.item, position: ctx.position, size: ctx.size, vars: vars}
+}
+
+func (ctx *evalContext) node() (Node, error) {
+	n, ok := ctx.item.(Node)
+	if !ok {
+		return nil, fmt.Errorf("metapath: context item is not a node")
+	}
+	return n, nil
+}
+
+type expr interface {
+	eval(ctx *evalContext) (Sequence, error)
+}
+
+type literalExpr struct {
+	value Item
+}
+
+func (e *literalExpr) eval(ctx *evalContext) (Sequence, error) {
+	return Sequence{e.value}, nil
+}
+
+type variableExpr struct {
+	name string
+}
+
+func (e *variableExpr) eval(ctx *evalContext) (Sequence, error) {
+	v, ok := ctx.vars[e.name]
+	if !ok {
+		return nil, fmt.Errorf("metapath: variable $%s is not bound", e.name)
+	}
+	return v, nil
+}
+
+type contextItemExpr struct{}
+
+func (e *contextItemExpr) eval(ctx *evalContext) (Sequence, error) {
+	if ctx.item == nil {
+		return nil, fmt.Errorf("metapath: context item is absent")
+	}
+	return Sequence{ctx.item}, nil
+}
+
+type sequenceExpr struct {
+	items []expr
+}
+
+func (e *sequenceExpr) eval(ctx *evalContext) (Sequence, error) {
+	var result Sequence
+	for _, item := range e.items {
+		s, err := item.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s...)
+	}
+	return result, nil
+}
+
+type ifExpr struct {
+	cond, then, otherwise expr
+}
+
+func (e *ifExpr) eval(ctx *evalContext) (Sequence, error) {
+	cond, err := e.cond.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := effectiveBoolean(cond)
+	if err != nil {
+		return nil, err
+	}
+	if b {
+		return e.then.eval(ctx)
+	}
+	return e.otherwise.eval(ctx)
+}
+
+type binding struct {
+	name string
+	in   expr
+}
+
+// bindAll calls fn for every combination of values of the bindings
+func bindAll(ctx *evalContext, bindings []binding, fn func(*evalContext) (bool, error)) (bool, error) {
+	if len(bindings) == 0 {
+		return fn(ctx)
+	}
+	seq, err := bindings[0].in.eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range seq {
+		more, err := bindAll(ctx.bind(bindings[0].name, Sequence{item}), bindings[1:], fn)
+		if err != nil || !more {
+			return more, err
+		}
+	}
+	return true, nil
+}
+
+type quantifiedExpr struct {
+	every     bool
+	bindings  []binding
+	satisfies expr
+}
+
+func (e *quantifiedExpr) eval(ctx *evalContext) (Sequence, error) {
+	result := e.every
+	_, err := bindAll(ctx, e.bindings, func(c *evalContext) (bool, error) {
+		s, err := e.satisfies.eval(c)
+		if err != nil {
+			return false, err
+		}
+		b, err := effectiveBoolean(s)
+		if err != nil {
+			return false, err
+		}
+		if b != e.every {
+			result = b
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Sequence{result}, nil
+}
+
+type forExpr struct {
+	bindings []binding
+	ret      expr
+}
+
+func (e *forExpr) eval(ctx *evalContext) (Sequence, error) {
+	var result Sequence
+	_, err := bindAll(ctx, e.bindings, func(c *evalContext) (bool, error) {
+		s, err := e.ret.eval(c)
+		result = append(result, s...)
+		return err == nil, err
+	})
+	return result, err
+}
+
+type logicalExpr struct {
+	or          bool
+	left, right expr
+}
+
+func (e *logicalExpr) eval(ctx *evalContext) (Sequence, error) {
+	for _, operand := range []expr{e.left, e.right} {
+		s, err := operand.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := effectiveBoolean(s)
+		if err != nil {
+			return nil, err
+		}
+		if b == e.or {
+			return Sequence{e.or}, nil
+		}
+	}
+	return Sequence{!e.or}, nil
+}
+
+type comparisonExpr struct {
+	op          string
+	general     bool
+	left, right expr
+}
+
+func (e *comparisonExpr) eval(ctx *evalContext) (Sequence, error) {
+	left, err := e.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	left, right = atomize(left), atomize(right)
+	if !e.general {
+		if len(left) == 0 || len(right) == 0 {
+			return Sequence{}, nil
+		}
+		if len(left) > 1 || len(right) > 1 {
+			return nil, fmt.Errorf("metapath: value comparison requires single items")
+		}
+		return Sequence{compareAtomic(e.op, left[0], right[0])}, nil
+	}
+	for _, l := range left {
+		for _, r := range right {
+			if compareAtomic(e.op, l, r) {
+				return Sequence{true}, nil
+			}
+		}
+	}
+	return Sequence{false}, nil
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *arithmeticExpr) eval(ctx *evalContext) (Sequence, error) {
+	left, err := e.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	left, right = atomize(left), atomize(right)
+	if e.op == "||" {
+		return Sequence{stringOf(left) + stringOf(right)}, nil
+	}
+	if len(left) == 0 || len(right) == 0 {
+		return Sequence{}, nil
+	}
+	if len(left) > 1 || len(right) > 1 {
+		return nil, fmt.Errorf("metapath: arithmetic operator '%s' requires single items", e.op)
+	}
+	l, r := numberValue(left[0]), numberValue(right[0])
+	// numbers are not typed, division by zero is reported as it is for
+	// xs:integer and xs:decimal operands rather than giving INF or NaN
+	if r == 0 && (e.op == "div" || e.op == "idiv" || e.op == "mod") {
+		return nil, fmt.Errorf("metapath: division by zero in '%s'", e.op)
+	}
+	switch e.op {
+	case "+":
+		return Sequence{l + r}, nil
+	case "-":
+		return Sequence{l - r}, nil
+	case "*":
+		return Sequence{l * r}, nil
+	case "div":
+		return Sequence{l / r}, nil
+	case "idiv":
+		return Sequence{math.Trunc(l / r)}, nil
+	case "mod":
+		return Sequence{math.Mod(l, r)}, nil
+	}
+	return nil, fmt.Errorf("metapath: unknown operator '%s'", e.op)
+}
+
+type negateExpr struct {
+	operand expr
+}
+
+func (e *negateExpr) eval(ctx *evalContext) (Sequence, error) {
+	s, err := e.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s = atomize(s)
+	if len(s) == 0 {
+		return s, nil
+	}
+	if len(s) > 1 {
+		return nil, fmt.Errorf("metapath: unary minus requires single item")
+	}
+	return Sequence{-numberValue(s[0])}, nil
+}
+
+type rangeExpr struct {
+	from, to expr
+}
+
+func (e *rangeExpr) eval(ctx *evalContext) (Sequence, error) {
+	from, err := e.from.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	to, err := e.to.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, to = atomize(from), atomize(to)
+	if len(from) != 1 || len(to) != 1 {
+		return Sequence{}, nil
+	}
+	var result Sequence
+	for i := numberValue(from[0]); i <= numberValue(to[0]); i++ {
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+type unionExpr struct {
+	left, right expr
+}
+
+func (e *unionExpr) eval(ctx *evalContext) (Sequence, error) {
+	left, err := e.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, 0, len(left)+len(right))
+	for _, item := range append(left, right...) {
+		n, ok := item.(Node)
+		if !ok {
+			return nil, fmt.Errorf("metapath: union operands must be nodes")
+		}
+		nodes = append(nodes, n)
+	}
+	return nodeSequence(documentOrder(nodes)), nil
+}
+
+type pathExpr struct {
+	absolute bool
+	steps    []expr
+}
+
+func (e *pathExpr) eval(ctx *evalContext) (Sequence, error) {
+	current := Sequence{ctx.item}
+	if e.absolute {
+		n, err := ctx.node()
+		if err != nil {
+			return nil, err
+		}
+		current = Sequence{Root(n)}
+	}
+	for i, step := range e.steps {
+		var next Sequence
+		nodes, atomics := 0, 0
+		for pos, item := range current {
+			if _, ok := item.(Node); !ok && i > 0 {
+				return nil, fmt.Errorf("metapath: path step applied to atomic value")
+			}
+			s, err := step.eval(ctx.with(item, pos+1, len(current)))
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range s {
+				if _, ok := r.(Node); ok {
+					nodes++
+				} else {
+					atomics++
+				}
+			}
+			next = append(next, s...)
+		}
+		if nodes > 0 && atomics > 0 {
+			return nil, fmt.Errorf("metapath: path step returned both nodes and atomic values")
+		}
+		if nodes > 0 && len(current) > 1 {
+			next = nodeSequence(documentOrder(next.Nodes()))
+		}
+		current = next
+	}
+	return current, nil
+}
+
+type axis int
+
+const (
+	axisChild axis = iota
+	axisSelf
+	axisParent
+	axisDescendant
+	axisDescendantOrSelf
+	axisAncestor
+	axisAncestorOrSelf
+	axisFollowingSibling
+	axisPrecedingSibling
+	axisFlag
+)
+
+func (a axis) reverse() bool {
+	return a == axisParent || a == axisAncestor || a == axisAncestorOrSelf || a == axisPrecedingSibling
+}
+
+type nodeTest struct {
+	any  bool
+	name string
+}
+
+func (t nodeTest) matches(n Node) bool {
+	if t.any {
+		return true
+	}
+	if n.Kind() == DocumentNode {
+		return false
+	}
+	return t.name == "*" || t.name == n.Name()
+}
+
+type axisStep struct {
+	axis       axis
+	test       nodeTest
+	predicates []expr
+}
+
+func (e *axisStep) eval(ctx *evalContext) (Sequence, error) {
+	n, err := ctx.node()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Node
+	switch e.axis {
+	case axisChild:
+		candidates = n.Children()
+	case axisSelf:
+		candidates = []Node{n}
+	case axisParent:
+		if p := n.Parent(); p != nil {
+			candidates = []Node{p}
+		}
+	case axisDescendant:
+		candidates = descendants(n, false, nil)
+	case axisDescendantOrSelf:
+		candidates = descendants(n, true, nil)
+	case axisAncestor:
+		candidates = ancestors(n, false)
+	case axisAncestorOrSelf:
+		candidates = ancestors(n, true)
+	case axisFollowingSibling, axisPrecedingSibling:
+		if p := n.Parent(); p != nil && n.Kind() != FlagNode {
+			siblings := p.Children()
+			for i, s := range siblings {
+				if s != n {
+					continue
+				}
+				if e.axis == axisFollowingSibling {
+					candidates = siblings[i+1:]
+				} else {
+					for j := i - 1; j >= 0; j-- {
+						candidates = append(candidates, siblings[j])
+					}
+				}
+			}
+		}
+	case axisFlag:
+		candidates = n.Flags()
+	}
+
+	result := make(Sequence, 0, len(candidates))
+	for _, c := range candidates {
+		if e.test.matches(c) {
+			result = append(result, c)
+		}
+	}
+	result, err = applyPredicates(ctx, result, e.predicates)
+	if err != nil {
+		return nil, err
+	}
+	if e.axis.reverse() {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+	return result, nil
+}
+
+type filterExpr struct {
+	primary    expr
+	predicates []expr
+}
+
+func (e *filterExpr) eval(ctx *evalContext) (Sequence, error) {
+	s, err := e.primary.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return applyPredicates(ctx, s, e.predicates)
+}
+
+func applyPredicates(ctx *evalContext, s Sequence, predicates []expr) (Sequence, error) {
+	for _, pred := range predicates {
+		filtered := make(Sequence, 0, len(s))
+		for i, item := range s {
+			r, err := pred.eval(ctx.with(item, i+1, len(s)))
+			if err != nil {
+				return nil, err
+			}
+			if len(r) == 1 {
+				if f, ok := r[0].(float64); ok {
+					if int(f) == i+1 && f == math.Trunc(f) {
+						filtered = append(filtered, item)
+					}
+					continue
+				}
+			}
+			b, err := effectiveBoolean(r)
+			if err != nil {
+				return nil, err
+			}
+			if b {
+				filtered = append(filtered, item)
+			}
+		}
+		s = filtered
+	}
+	return s, nil
+}
+
+type functionCall struct {
+	name string
+	fn   function
+	args []expr
+}
+
+func (e *functionCall) eval(ctx *evalContext) (Sequence, error) {
+	args := make([]Sequence, 0, len(e.args))
+	for _, a := range e.args {
+		s, err := a.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, s)
+	}
+	result, err := e.fn.call(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("metapath: %s(): %w", e.name, err)
+	}
+	return result, nil
+}
+
+func nodeSequence(nodes []Node) Sequence {
+	s := make(Sequence, 0, len(nodes))
+	for _, n := range nodes {
+		s = append(s, n)
+	}
+	return s
+}
@@ -0,0 +1,321 @@
+package metapath
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type function struct {
+	minArgs int
+	// maxArgs is -1 for functions accepting any number of arguments
+	maxArgs int
+	call    func(ctx *evalContext, args []Sequence) (Sequence, error)
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"true":  {0, 0, constant(true)},
+		"false": {0, 0, constant(false)},
+		"not": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			b, err := effectiveBoolean(args[0])
+			return Sequence{!b}, err
+		}},
+		"boolean": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			b, err := effectiveBoolean(args[0])
+			return Sequence{b}, err
+		}},
+		"count": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			return Sequence{float64(len(args[0]))}, nil
+		}},
+		"exists": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			return Sequence{len(args[0]) > 0}, nil
+		}},
+		"empty": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			return Sequence{len(args[0]) == 0}, nil
+		}},
+		"position": {0, 0, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			return Sequence{float64(ctx.position)}, nil
+		}},
+		"last": {0, 0, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			return Sequence{float64(ctx.size)}, nil
+		}},
+		"string": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			return Sequence{stringOf(s)}, err
+		}},
+		"data": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			return atomize(s), err
+		}},
+		"number": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			if err != nil || len(s) == 0 {
+				return Sequence{math.NaN()}, err
+			}
+			return Sequence{numberValue(atomize(s)[0])}, nil
+		}},
+		"name":       {0, 1, nodeName},
+		"local-name": {0, 1, nodeName},
+		"root": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			if err != nil || len(s) == 0 {
+				return Sequence{}, err
+			}
+			n, ok := s[0].(Node)
+			if !ok {
+				return nil, fmt.Errorf("argument is not a node")
+			}
+			return Sequence{Root(n)}, nil
+		}},
+		"path": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			if err != nil || len(s) == 0 {
+				return Sequence{}, err
+			}
+			n, ok := s[0].(Node)
+			if !ok {
+				return nil, fmt.Errorf("argument is not a node")
+			}
+			return Sequence{Path(n)}, nil
+		}},
+		"concat": {2, -1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			var sb strings.Builder
+			for _, a := range args {
+				sb.WriteString(stringOf(atomize(a)))
+			}
+			return Sequence{sb.String()}, nil
+		}},
+		"string-join": {1, 2, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			separator := ""
+			if len(args) > 1 {
+				separator = stringOf(atomize(args[1]))
+			}
+			return Sequence{strings.Join(atomize(args[0]).Strings(), separator)}, nil
+		}},
+		"string-length": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			return Sequence{float64(utf8.RuneCountInString(stringOf(atomize(s))))}, err
+		}},
+		"normalize-space": {0, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			s, err := contextOrArg(ctx, args)
+			return Sequence{strings.Join(strings.Fields(stringOf(atomize(s))), " ")}, err
+		}},
+		"upper-case":       stringFunction(strings.ToUpper),
+		"lower-case":       stringFunction(strings.ToLower),
+		"contains":         stringPredicate(strings.Contains),
+		"starts-with":      stringPredicate(strings.HasPrefix),
+		"ends-with":        stringPredicate(strings.HasSuffix),
+		"substring-before": stringBinary(substringBefore),
+		"substring-after":  stringBinary(substringAfter),
+		"substring": {2, 3, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			runes := []rune(stringOf(atomize(args[0])))
+			start := math.Round(numberValue(stringOf(atomize(args[1]))))
+			end := math.Inf(1)
+			if len(args) > 2 {
+				end = start + math.Round(numberValue(stringOf(atomize(args[2]))))
+			}
+			var sb strings.Builder
+			for i, r := range runes {
+				if pos := float64(i + 1); pos >= start && pos < end {
+					sb.WriteRune(r)
+				}
+			}
+			return Sequence{sb.String()}, nil
+		}},
+		"matches": {2, 3, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			re, err := compileRegex(args[1:])
+			if err != nil {
+				return nil, err
+			}
+			return Sequence{re.MatchString(stringOf(atomize(args[0])))}, nil
+		}},
+		"replace": {3, 4, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			re, err := compileRegex(append([]Sequence{args[1]}, args[3:]...))
+			if err != nil {
+				return nil, err
+			}
+			return Sequence{re.ReplaceAllString(stringOf(atomize(args[0])), stringOf(atomize(args[2])))}, nil
+		}},
+		"tokenize": {1, 3, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			input := stringOf(atomize(args[0]))
+			var parts []string
+			if len(args) == 1 {
+				parts = strings.Fields(input)
+			} else {
+				re, err := compileRegex(args[1:])
+				if err != nil {
+					return nil, err
+				}
+				if input != "" {
+					parts = re.Split(input, -1)
+				}
+			}
+			result := make(Sequence, 0, len(parts))
+			for _, p := range parts {
+				result = append(result, p)
+			}
+			return result, nil
+		}},
+		"distinct-values": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			seen := map[string]bool{}
+			var result Sequence
+			for _, item := range atomize(args[0]) {
+				key := stringValue(item)
+				if !seen[key] {
+					seen[key] = true
+					result = append(result, item)
+				}
+			}
+			return result, nil
+		}},
+		"reverse": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			result := make(Sequence, len(args[0]))
+			for i, item := range args[0] {
+				result[len(result)-1-i] = item
+			}
+			return result, nil
+		}},
+		"sum": {1, 2, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			if len(args[0]) == 0 && len(args) > 1 {
+				return args[1], nil
+			}
+			total := 0.0
+			for _, item := range atomize(args[0]) {
+				total += numberValue(item)
+			}
+			return Sequence{total}, nil
+		}},
+		"avg": {1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+			if len(args[0]) == 0 {
+				return Sequence{}, nil
+			}
+			total := 0.0
+			for _, item := range atomize(args[0]) {
+				total += numberValue(item)
+			}
+			return Sequence{total / float64(len(args[0]))}, nil
+		}},
+		"min":     {1, 1, extreme(func(a, b float64) bool { return a < b })},
+		"max":     {1, 1, extreme(func(a, b float64) bool { return a > b })},
+		"abs":     numberFunction(math.Abs),
+		"ceiling": numberFunction(math.Ceil),
+		"floor":   numberFunction(math.Floor),
+		"round": numberFunction(func(f float64) float64 {
+			return math.Floor(f + 0.5)
+		}),
+	}
+}
+
+func constant(value Item) func(*evalContext, []Sequence) (Sequence, error) {
+	return func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		return Sequence{value}, nil
+	}
+}
+
+func contextOrArg(ctx *evalContext, args []Sequence) (Sequence, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if ctx.item == nil {
+		return nil, fmt.Errorf("context item is absent")
+	}
+	return Sequence{ctx.item}, nil
+}
+
+func nodeName(ctx *evalContext, args []Sequence) (Sequence, error) {
+	s, err := contextOrArg(ctx, args)
+	if err != nil || len(s) == 0 {
+		return Sequence{""}, err
+	}
+	n, ok := s[0].(Node)
+	if !ok {
+		return nil, fmt.Errorf("argument is not a node")
+	}
+	return Sequence{n.Name()}, nil
+}
+
+func stringFunction(fn func(string) string) function {
+	return function{1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		return Sequence{fn(stringOf(atomize(args[0])))}, nil
+	}}
+}
+
+func stringPredicate(fn func(string, string) bool) function {
+	return function{2, 2, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		return Sequence{fn(stringOf(atomize(args[0])), stringOf(atomize(args[1])))}, nil
+	}}
+}
+
+func stringBinary(fn func(string, string) string) function {
+	return function{2, 2, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		return Sequence{fn(stringOf(atomize(args[0])), stringOf(atomize(args[1])))}, nil
+	}}
+}
+
+func numberFunction(fn func(float64) float64) function {
+	return function{1, 1, func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		s := atomize(args[0])
+		if len(s) == 0 {
+			return Sequence{}, nil
+		}
+		return Sequence{fn(numberValue(s[0]))}, nil
+	}}
+}
+
+func extreme(better func(a, b float64) bool) func(*evalContext, []Sequence) (Sequence, error) {
+	return func(ctx *evalContext, args []Sequence) (Sequence, error) {
+		s := atomize(args[0])
+		if len(s) == 0 {
+			return Sequence{}, nil
+		}
+		best := numberValue(s[0])
+		for _, item := range s[1:] {
+			if v := numberValue(item); better(v, best) {
+				best = v
+			}
+		}
+		return Sequence{best}, nil
+	}
+}
+
+func substringBefore(s, sep string) string {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i]
+	}
+	return ""
+}
+
+func substringAfter(s, sep string) string {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[i+len(sep):]
+	}
+	return ""
+}
+
+// compileRegex compiles XPath regular expression with optional flags
+func compileRegex(args []Sequence) (*regexp.Regexp, error) {
+	pattern := stringOf(atomize(args[0]))
+	if len(args) > 1 {
+		flags := stringOf(atomize(args[1]))
+		goFlags := ""
+		for _, f := range flags {
+			switch f {
+			case 'i', 'm', 's':
+				goFlags += string(f)
+			case 'x':
+				pattern = strings.Join(strings.Fields(pattern), "")
+			default:
+				return nil, fmt.Errorf("unsupported regular expression flag '%c'", f)
+			}
+		}
+		if goFlags != "" {
+			pattern = "(?" + goFlags + ")" + pattern
+		}
+	}
+	return regexp.Compile(pattern)
+}
@@ -0,0 +1,165 @@
+package metapath
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenString
+	tokenNumber
+	tokenVariable
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string literal '%s'", t.value)
+	case tokenVariable:
+		return "$" + t.value
+	}
+	return "'" + t.value + "'"
+}
+
+// symbols are ordered so that longer symbols are matched first
+var symbols = []string{
+	"//", "::", "..", "!=", "<=", ">=", "||",
+	"(", ")", "[", "]", ",", "/", "@", ".", "=", "<", ">", "+", "-", "*", "|",
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(expression) {
+		r, size := utf8.DecodeRuneInString(expression[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case strings.HasPrefix(expression[pos:], "(:"):
+			end := strings.Index(expression[pos:], ":)")
+			if end < 0 {
+				return nil, &SyntaxError{Expression: expression, Pos: pos, Msg: "unterminated comment"}
+			}
+			pos += end + 2
+		case r == '"' || r == '\'':
+			value, next, err := lexString(expression, pos, byte(r))
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			pos = next
+		case isDigit(r) || (r == '.' && pos+1 < len(expression) && isDigit(rune(expression[pos+1]))):
+			start := pos
+			for pos < len(expression) && (isDigit(rune(expression[pos])) || expression[pos] == '.') {
+				pos++
+			}
+			if pos < len(expression) && (expression[pos] == 'e' || expression[pos] == 'E') {
+				pos++
+				if pos < len(expression) && (expression[pos] == '+' || expression[pos] == '-') {
+					pos++
+				}
+				for pos < len(expression) && isDigit(rune(expression[pos])) {
+					pos++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: expression[start:pos], pos: start})
+		case r == '$':
+			start := pos
+			pos++
+			name := lexName(expression, pos)
+			if name == "" {
+				return nil, &SyntaxError{Expression: expression, Pos: start, Msg: "expected variable name after '$'"}
+			}
+			pos += len(name)
+			tokens = append(tokens, token{kind: tokenVariable, value: name, pos: start})
+		case isNameStart(r):
+			start := pos
+			name := lexName(expression, pos)
+			pos += len(name)
+			// prefixed name such as fn:count, but not an axis separator
+			if pos+1 < len(expression) && expression[pos] == ':' && expression[pos+1] != ':' {
+				if local := lexName(expression, pos+1); local != "" {
+					name += ":" + local
+					pos += 1 + len(local)
+				}
+			}
+			tokens = append(tokens, token{kind: tokenName, value: name, pos: start})
+		default:
+			matched := false
+			for _, s := range symbols {
+				if strings.HasPrefix(expression[pos:], s) {
+					tokens = append(tokens, token{kind: tokenSymbol, value: s, pos: pos})
+					pos += len(s)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &SyntaxError{Expression: expression, Pos: pos, Msg: fmt.Sprintf("unexpected character '%c'", r)}
+			}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(expression)})
+	return tokens, nil
+}
+
+func lexString(expression string, pos int, quote byte) (string, int, error) {
+	var sb strings.Builder
+	i := pos + 1
+	for i < len(expression) {
+		if expression[i] == quote {
+			// doubled quote stands for the quote itself
+			if i+1 < len(expression) && expression[i+1] == quote {
+				sb.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteByte(expression[i])
+		i++
+	}
+	return "", 0, &SyntaxError{Expression: expression, Pos: pos, Msg: "unterminated string literal"}
+}
+
+func lexName(expression string, pos int) string {
+	end := pos
+	for end < len(expression) {
+		r, size := utf8.DecodeRuneInString(expression[end:])
+		if end == pos && !isNameStart(r) {
+			break
+		}
+		if !isNameStart(r) && !isDigit(r) && r != '-' && r != '.' {
+			break
+		}
+		end += size
+	}
+	return expression[pos:end]
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,90 @@
+// Package metapath implements Metapath, the XPath 3.1 subset used by
+// metaschema constraints to address and test content of instance documents.
+//
+// Expressions are evaluated against any tree implementing Node. The package
+// provides two such trees: Element, a generic document tree, and a reflection
+// based view of the structs generated by this project (see FromStruct).
+package metapath
+
+// Expression is a compiled metapath expression
+type Expression struct {
+	source string
+	root   expr
+}
+
+// Compile parses metapath expression
+func Compile(expression string) (*Expression, error) {
+	root, err := parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{source: expression, root: root}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+// It is meant for initialization of global variables holding expressions.
+func MustCompile(expression string) *Expression {
+	e, err := Compile(expression)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source text of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate evaluates the expression with the context node as context item
+func (e *Expression) Evaluate(context Node) (Sequence, error) {
+	return e.EvaluateWithVariables(context, nil)
+}
+
+// EvaluateWithVariables evaluates the expression with variables bound to the
+// given values
+func (e *Expression) EvaluateWithVariables(context Node, vars map[string]Sequence) (Sequence, error) {
+	ctx := &evalContext{position: 1, size: 1, vars: vars}
+	if context != nil {
+		ctx.item = context
+	}
+	return e.root.eval(ctx)
+}
+
+// EvaluateBoolean evaluates the expression and returns its effective boolean
+// value
+func (e *Expression) EvaluateBoolean(context Node) (bool, error) {
+	s, err := e.Evaluate(context)
+	if err != nil {
+		return false, err
+	}
+	return effectiveBoolean(s)
+}
+
+// EvaluateNodes evaluates the expression and returns the nodes it selects
+func (e *Expression) EvaluateNodes(context Node) ([]Node, error) {
+	s, err := e.Evaluate(context)
+	if err != nil {
+		return nil, err
+	}
+	return s.Nodes(), nil
+}
+
+// EvaluateString evaluates the expression and returns string value of the
+// first item of the result, or empty string when the result is empty
+func (e *Expression) EvaluateString(context Node) (string, error) {
+	s, err := e.Evaluate(context)
+	if err != nil {
+		return "", err
+	}
+	return stringOf(atomize(s)), nil
+}
+
+// Evaluate compiles and evaluates the expression against the context node
+func Evaluate(expression string, context Node) (Sequence, error) {
+	e, err := Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return e.Evaluate(context)
+}
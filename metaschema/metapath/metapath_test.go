@@ -0,0 +1,147 @@
+package metapath
+
+import (
+	"encoding/xml"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/markup"
+)
+
+func loadFixture(t *testing.T, name string) *Element {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	doc, err := ParseXML(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestEvaluate(t *testing.T) {
+	doc := loadFixture(t, "catalog.xml")
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"/catalog/@id", []string{"c1"}},
+		{"catalog/title", []string{"Hello world"}},
+		{"//control/@id", []string{"a", "b", "c"}},
+		{"/catalog/group[2]/control/@id", []string{"c"}},
+		{"//control[@class='base']/@id", []string{"a", "c"}},
+		{"//control[prop/@name='status']/title", []string{"Backup"}},
+		{"//control[last()]/@id", []string{"b", "c"}},
+		{"count(//prop)", []string{"3"}},
+		{"sum(//prop[@name='weight']/@value)", []string{"8"}},
+		{"//prop[@name='weight']/@value > 4", []string{"true"}},
+		{"exists(//control[@id='x'])", []string{"false"}},
+		{"empty(/catalog/back-matter)", []string{"true"}},
+		{"string-length(/catalog/group[1]/control[1]/title)", []string{"6"}},
+		{"concat(/catalog/@id, '-', /catalog/@version)", []string{"c1-1.0"}},
+		{"upper-case(//group[1]/control[1]/title)", []string{"ACCESS"}},
+		{"for $c in //control return $c/@id || '!'", []string{"a!", "b!", "c!"}},
+		{"some $p in //prop satisfies $p/@value = 'withdrawn'", []string{"true"}},
+		{"every $c in //control satisfies $c/title", []string{"true"}},
+		{"if (count(//group) = 2) then 'two' else 'other'", []string{"two"}},
+		{"(1, 2, 3)[. mod 2 = 1]", []string{"1", "3"}},
+		{"1 to 3", []string{"1", "2", "3"}},
+		{"7 idiv 2", []string{"3"}},
+		{"7 div 2", []string{"3.5"}},
+		{"-(2 + 3) * 2", []string{"-10"}},
+		{"matches(//control[1]/title, '^[A-C]')", []string{"true"}},
+		{"//control/@id = ('b', 'z')", []string{"true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			s, err := Evaluate(tt.expression, doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := atomize(s).Strings(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	doc := loadFixture(t, "catalog.xml")
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{"1 div 0", "division by zero"},
+		{"1 idiv 0", "division by zero"},
+		{"5 mod 0", "division by zero"},
+		{"count(//control) div (count(//prop) - 3)", "division by zero"},
+		{"//control/@id + 1", "requires single items"},
+		{"unknown-function()", "unknown-function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := Evaluate(tt.expression, doc)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expression := range []string{"", "/catalog[", "(1, 2", "'unterminated", "1 +"} {
+		if _, err := Compile(expression); err == nil {
+			t.Errorf("Compile(%q) succeeded, want error", expression)
+		}
+	}
+}
+
+type testControl struct {
+	Id    string      `xml:"id,attr"`
+	Class string      `xml:"class,attr,omitempty"`
+	Title markup.Line `xml:"title"`
+}
+
+type testCatalog struct {
+	XMLName  xml.Name      `xml:"catalog"`
+	Id       string        `xml:"id,attr"`
+	Title    markup.Line   `xml:"title"`
+	Controls []testControl `xml:"group>control"`
+}
+
+func TestFromStructMatchesParseXML(t *testing.T) {
+	catalog := &testCatalog{
+		Id:    "c1",
+		Title: markup.Line("Hello <em>world</em>"),
+		Controls: []testControl{
+			{Id: "a", Class: "base", Title: "Access"},
+			{Id: "b", Title: "Backup"},
+			{Id: "c", Class: "base", Title: "Crypto"},
+		},
+	}
+	tree := FromStruct(catalog)
+	doc := loadFixture(t, "catalog.xml")
+	for _, expression := range []string{
+		"/catalog/title",
+		"//control/@id",
+		"//control[@class='base']/title",
+		"count(//control)",
+	} {
+		want, err := Evaluate(expression, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Evaluate(expression, tree)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, w := atomize(got).Strings(), atomize(want).Strings(); !reflect.DeepEqual(g, w) {
+			t.Errorf("%s: got %q from struct, want %q", expression, g, w)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package metapath
+
+import (
+	"sort"
+	"strconv"
+)
+
+// NodeKind distinguishes nodes of a document tree
+type NodeKind int
+
+const (
+	// DocumentNode is the root of the tree, its only child is the root assembly
+	DocumentNode NodeKind = iota
+	// AssemblyNode is a node with model children
+	AssemblyNode
+	// FieldNode is a node with a value and optional flags
+	FieldNode
+	// FlagNode is a named value attached to an assembly or field
+	FlagNode
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case DocumentNode:
+		return "document"
+	case AssemblyNode:
+		return "assembly"
+	case FieldNode:
+		return "field"
+	case FlagNode:
+		return "flag"
+	}
+	return "unknown"
+}
+
+// Node is a node of a document tree that metapath expressions are evaluated
+// against. Implementations must return the same Node value for the same
+// underlying node, so that nodes can be compared and deduplicated.
+type Node interface {
+	// Kind returns the kind of the node
+	Kind() NodeKind
+	// Name returns the name of the node as declared in the metaschema
+	Name() string
+	// Parent returns the parent node or nil for the document node
+	Parent() Node
+	// Flags returns the flags of an assembly or field node
+	Flags() []Node
+	// Children returns model children of a document or assembly node in
+	// document order
+	Children() []Node
+	// Value returns the string value of a field or flag node
+	Value() string
+}
+
+// Item is a member of a sequence. It is either a Node or an atomic value of
+// type string, float64, bool or UntypedAtomic.
+type Item interface{}
+
+// UntypedAtomic is a value of a node that has not been cast to any type yet.
+// It is compared as a number with numbers and as a string otherwise.
+type UntypedAtomic string
+
+// Sequence is a result of metapath expression evaluation
+type Sequence []Item
+
+// Nodes returns the nodes within the sequence
+func (s Sequence) Nodes() []Node {
+	nodes := make([]Node, 0, len(s))
+	for _, item := range s {
+		if n, ok := item.(Node); ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Strings returns the string values of all the items in the sequence
+func (s Sequence) Strings() []string {
+	result := make([]string, 0, len(s))
+	for _, item := range s {
+		result = append(result, stringValue(item))
+	}
+	return result
+}
+
+// Root returns the document (or topmost) node of the tree the node belongs to
+func Root(n Node) Node {
+	for n.Parent() != nil {
+		n = n.Parent()
+	}
+	return n
+}
+
+// Path returns a metapath expression locating the node within its document,
+// for example /catalog/group[2]/control[1]/@id
+func Path(n Node) string {
+	if n == nil || n.Kind() == DocumentNode {
+		return "/"
+	}
+	var steps []string
+	for ; n != nil && n.Kind() != DocumentNode; n = n.Parent() {
+		if n.Kind() == FlagNode {
+			steps = append(steps, "@"+n.Name())
+			continue
+		}
+		step := n.Name()
+		if p := n.Parent(); p != nil && p.Kind() != DocumentNode {
+			index, total := 0, 0
+			for _, sibling := range p.Children() {
+				if sibling.Name() == n.Name() {
+					total++
+					if sibling == n {
+						index = total
+					}
+				}
+			}
+			if total > 1 {
+				step += "[" + strconv.Itoa(index) + "]"
+			}
+		}
+		steps = append(steps, step)
+	}
+	path := ""
+	for i := len(steps) - 1; i >= 0; i-- {
+		path += "/" + steps[i]
+	}
+	return path
+}
+
+func descendants(n Node, includeSelf bool, out []Node) []Node {
+	if includeSelf {
+		out = append(out, n)
+	}
+	for _, c := range n.Children() {
+		out = descendants(c, true, out)
+	}
+	return out
+}
+
+func ancestors(n Node, includeSelf bool) []Node {
+	var out []Node
+	if includeSelf {
+		out = append(out, n)
+	}
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		out = append(out, p)
+	}
+	return out
+}
+
+// orderKey returns positions of the node and its ancestors within their
+// parents. Flags precede model children of their parent.
+func orderKey(n Node) []int {
+	var key []int
+	for ; n.Parent() != nil; n = n.Parent() {
+		p := n.Parent()
+		pos := -1
+		if n.Kind() == FlagNode {
+			for i, f := range p.Flags() {
+				if f == n {
+					pos = i
+				}
+			}
+		} else {
+			for i, c := range p.Children() {
+				if c == n {
+					pos = len(p.Flags()) + i
+				}
+			}
+		}
+		key = append([]int{pos}, key...)
+	}
+	return key
+}
+
+func compareKeys(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// documentOrder removes duplicate nodes and sorts them in document order
+func documentOrder(nodes []Node) []Node {
+	seen := make(map[Node]bool, len(nodes))
+	uniq := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			uniq = append(uniq, n)
+		}
+	}
+	if len(uniq) < 2 {
+		return uniq
+	}
+	keys := make(map[Node][]int, len(uniq))
+	for _, n := range uniq {
+		keys[n] = orderKey(n)
+	}
+	sort.SliceStable(uniq, func(i, j int) bool {
+		return compareKeys(keys[uniq[i]], keys[uniq[j]]) < 0
+	})
+	return uniq
+}
@@ -0,0 +1,601 @@
+package metapath
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SyntaxError describes malformed metapath expression
+type SyntaxError struct {
+	Expression string
+	Pos        int
+	Msg        string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("metapath: %s at position %d in '%s'", e.Msg, e.Pos, e.Expression)
+}
+
+type parser struct {
+	expression string
+	tokens     []token
+	pos        int
+}
+
+func parse(expression string) (expr, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expression: expression, tokens: tokens}
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) acceptSymbol(value string) bool {
+	if p.peek().is(tokenSymbol, value) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptKeyword(value string) bool {
+	if p.peek().is(tokenName, value) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(value string) error {
+	if !p.acceptSymbol(value) {
+		return p.errorf(p.peek(), "expected '%s', found %s", value, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) expectKeyword(value string) error {
+	if !p.acceptKeyword(value) {
+		return p.errorf(p.peek(), "expected '%s', found %s", value, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Expression: p.expression, Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Expr ::= ExprSingle ("," ExprSingle)*
+func (p *parser) parseExpr() (expr, error) {
+	first, err := p.parseExprSingle()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek().is(tokenSymbol, ",") {
+		return first, nil
+	}
+	seq := &sequenceExpr{items: []expr{first}}
+	for p.acceptSymbol(",") {
+		e, err := p.parseExprSingle()
+		if err != nil {
+			return nil, err
+		}
+		seq.items = append(seq.items, e)
+	}
+	return seq, nil
+}
+
+// ExprSingle ::= IfExpr | QuantifiedExpr | ForExpr | OrExpr
+func (p *parser) parseExprSingle() (expr, error) {
+	t := p.peek()
+	if t.kind == tokenName {
+		switch {
+		case t.value == "if" && p.peekAt(1).is(tokenSymbol, "("):
+			return p.parseIf()
+		case (t.value == "some" || t.value == "every") && p.peekAt(1).kind == tokenVariable:
+			return p.parseQuantified()
+		case t.value == "for" && p.peekAt(1).kind == tokenVariable:
+			return p.parseFor()
+		}
+	}
+	return p.parseOr()
+}
+
+func (p *parser) parseIf() (expr, error) {
+	p.next()
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parseExprSingle()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("else"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExprSingle()
+	if err != nil {
+		return nil, err
+	}
+	return &ifExpr{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+func (p *parser) parseBindings() ([]binding, error) {
+	var bindings []binding
+	for {
+		t := p.next()
+		if t.kind != tokenVariable {
+			return nil, p.errorf(t, "expected variable, found %s", t)
+		}
+		if err := p.expectKeyword("in"); err != nil {
+			return nil, err
+		}
+		in, err := p.parseExprSingle()
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding{name: t.value, in: in})
+		if !p.acceptSymbol(",") {
+			return bindings, nil
+		}
+	}
+}
+
+func (p *parser) parseQuantified() (expr, error) {
+	every := p.next().value == "every"
+	bindings, err := p.parseBindings()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("satisfies"); err != nil {
+		return nil, err
+	}
+	satisfies, err := p.parseExprSingle()
+	if err != nil {
+		return nil, err
+	}
+	return &quantifiedExpr{every: every, bindings: bindings, satisfies: satisfies}, nil
+}
+
+func (p *parser) parseFor() (expr, error) {
+	p.next()
+	bindings, err := p.parseBindings()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("return"); err != nil {
+		return nil, err
+	}
+	ret, err := p.parseExprSingle()
+	if err != nil {
+		return nil, err
+	}
+	return &forExpr{bindings: bindings, ret: ret}, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+var generalComparisons = map[string]bool{"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+var valueComparisons = map[string]string{"eq": "=", "ne": "!=", "lt": "<", "le": "<=", "gt": ">", "ge": ">="}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseStringConcat()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokenSymbol && generalComparisons[t.value] {
+		p.next()
+		right, err := p.parseStringConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonExpr{op: t.value, general: true, left: left, right: right}, nil
+	}
+	if op, ok := valueComparisons[t.value]; ok && t.kind == tokenName {
+		p.next()
+		right, err := p.parseStringConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonExpr{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseStringConcat() (expr, error) {
+	left, err := p.parseRange()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptSymbol("||") {
+		right, err := p.parseRange()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRange() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("to") {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &rangeExpr{from: left, to: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.is(tokenSymbol, "+") && !t.is(tokenSymbol, "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{op: t.value, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		isOp := t.is(tokenSymbol, "*") ||
+			(t.kind == tokenName && (t.value == "div" || t.value == "idiv" || t.value == "mod"))
+		if !isOp {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{op: t.value, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnion() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptSymbol("|") || p.acceptKeyword("union") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &unionExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	negate := false
+	for {
+		if p.acceptSymbol("-") {
+			negate = !negate
+		} else if !p.acceptSymbol("+") {
+			break
+		}
+	}
+	e, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	if negate {
+		return &negateExpr{operand: e}, nil
+	}
+	return e, nil
+}
+
+func (p *parser) startsStep() bool {
+	t := p.peek()
+	switch t.kind {
+	case tokenName, tokenString, tokenNumber, tokenVariable:
+		return true
+	case tokenSymbol:
+		switch t.value {
+		case "*", "@", ".", "..", "(":
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parsePath() (expr, error) {
+	path := &pathExpr{}
+	switch {
+	case p.acceptSymbol("//"):
+		path.absolute = true
+		path.steps = append(path.steps, descendantOrSelfStep())
+	case p.acceptSymbol("/"):
+		path.absolute = true
+		if !p.startsStep() {
+			return path, nil
+		}
+	}
+	if err := p.parseRelativePath(path); err != nil {
+		return nil, err
+	}
+	if !path.absolute && len(path.steps) == 1 {
+		return path.steps[0], nil
+	}
+	return path, nil
+}
+
+func (p *parser) parseRelativePath(path *pathExpr) error {
+	for {
+		step, err := p.parseStep()
+		if err != nil {
+			return err
+		}
+		path.steps = append(path.steps, step)
+		if p.acceptSymbol("//") {
+			path.steps = append(path.steps, descendantOrSelfStep())
+		} else if !p.acceptSymbol("/") {
+			return nil
+		}
+	}
+}
+
+func descendantOrSelfStep() expr {
+	return &axisStep{axis: axisDescendantOrSelf, test: nodeTest{any: true}}
+}
+
+var axes = map[string]axis{
+	"child":              axisChild,
+	"self":               axisSelf,
+	"parent":             axisParent,
+	"descendant":         axisDescendant,
+	"descendant-or-self": axisDescendantOrSelf,
+	"ancestor":           axisAncestor,
+	"ancestor-or-self":   axisAncestorOrSelf,
+	"following-sibling":  axisFollowingSibling,
+	"preceding-sibling":  axisPrecedingSibling,
+	"attribute":          axisFlag,
+	"flag":               axisFlag,
+}
+
+func (p *parser) parseStep() (expr, error) {
+	t := p.peek()
+	var step expr
+	switch {
+	case t.is(tokenSymbol, ".."):
+		p.next()
+		step = &axisStep{axis: axisParent, test: nodeTest{any: true}}
+	case t.is(tokenSymbol, "@"):
+		p.next()
+		test, err := p.parseNodeTest()
+		if err != nil {
+			return nil, err
+		}
+		step = &axisStep{axis: axisFlag, test: test}
+	case t.kind == tokenName && p.peekAt(1).is(tokenSymbol, "::"):
+		a, ok := axes[t.value]
+		if !ok {
+			return nil, p.errorf(t, "unsupported axis '%s'", t.value)
+		}
+		p.next()
+		p.next()
+		test, err := p.parseNodeTest()
+		if err != nil {
+			return nil, err
+		}
+		step = &axisStep{axis: a, test: test}
+	case t.kind == tokenName && p.peekAt(1).is(tokenSymbol, "(") && !isKindTest(t.value):
+		call, err := p.parseFunctionCall()
+		if err != nil {
+			return nil, err
+		}
+		step = call
+	case t.kind == tokenName || t.is(tokenSymbol, "*"):
+		test, err := p.parseNodeTest()
+		if err != nil {
+			return nil, err
+		}
+		step = &axisStep{axis: axisChild, test: test}
+	default:
+		primary, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		step = primary
+	}
+
+	var predicates []expr
+	for p.acceptSymbol("[") {
+		pred, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol("]"); err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, pred)
+	}
+	if len(predicates) == 0 {
+		return step, nil
+	}
+	if as, ok := step.(*axisStep); ok {
+		as.predicates = predicates
+		return as, nil
+	}
+	return &filterExpr{primary: step, predicates: predicates}, nil
+}
+
+func isKindTest(name string) bool {
+	return name == "node"
+}
+
+func (p *parser) parseNodeTest() (nodeTest, error) {
+	t := p.next()
+	if t.is(tokenSymbol, "*") {
+		return nodeTest{name: "*"}, nil
+	}
+	if t.kind != tokenName {
+		return nodeTest{}, p.errorf(t, "expected name test, found %s", t)
+	}
+	if isKindTest(t.value) && p.peek().is(tokenSymbol, "(") {
+		p.next()
+		if err := p.expectSymbol(")"); err != nil {
+			return nodeTest{}, err
+		}
+		return nodeTest{any: true}, nil
+	}
+	return nodeTest{name: localName(t.value)}, nil
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenString:
+		return &literalExpr{value: t.value}, nil
+	case t.kind == tokenNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %s", t.value)
+		}
+		return &literalExpr{value: f}, nil
+	case t.kind == tokenVariable:
+		return &variableExpr{name: t.value}, nil
+	case t.is(tokenSymbol, "."):
+		return &contextItemExpr{}, nil
+	case t.is(tokenSymbol, "("):
+		if p.acceptSymbol(")") {
+			return &sequenceExpr{}, nil
+		}
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+func (p *parser) parseFunctionCall() (expr, error) {
+	t := p.next()
+	name := localName(t.value)
+	fn, ok := functions[name]
+	if !ok {
+		return nil, p.errorf(t, "unknown function '%s'", t.value)
+	}
+	p.next() // (
+	call := &functionCall{name: name, fn: fn}
+	if !p.acceptSymbol(")") {
+		for {
+			arg, err := p.parseExprSingle()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.acceptSymbol(")") {
+				break
+			}
+			if err = p.expectSymbol(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(call.args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.args) > fn.maxArgs) {
+		return nil, p.errorf(t, "wrong number of arguments for function '%s'", name)
+	}
+	return call, nil
+}
+
+// localName strips namespace prefix, all the names are matched by local part
+func localName(name string) string {
+	for i := 0; i < len(name); i++ {
+		if name[i] == ':' {
+			return name[i+1:]
+		}
+	}
+	return name
+}
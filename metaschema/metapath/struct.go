@@ -0,0 +1,263 @@
+package metapath
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// FromStruct returns document node of a tree backed by the given struct, as
+// generated by this project. Model children and flags are discovered through
+// the xml struct tags. Name of the root node is taken from the XMLName
+// member, or derived from the type name when missing.
+//
+// The view is read-only and is built lazily, the struct must not be modified
+// while the tree is in use.
+func FromStruct(v interface{}) Node {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	doc := &structNode{kind: DocumentNode, loaded: true}
+	root := newStructNode(rootName(rv), rv, doc)
+	doc.children = []Node{root}
+	return doc
+}
+
+type structNode struct {
+	name     string
+	kind     NodeKind
+	value    reflect.Value
+	text     string
+	parent   *structNode
+	flags    []Node
+	children []Node
+	loaded   bool
+}
+
+func newStructNode(name string, v reflect.Value, parent *structNode) *structNode {
+	n := &structNode{name: name, value: v, parent: parent, kind: FieldNode}
+	if v.Kind() == reflect.Struct && hasElements(v.Type()) {
+		n.kind = AssemblyNode
+	}
+	return n
+}
+
+func (n *structNode) Kind() NodeKind {
+	return n.kind
+}
+
+func (n *structNode) Name() string {
+	return n.name
+}
+
+func (n *structNode) Parent() Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *structNode) Flags() []Node {
+	n.load()
+	return n.flags
+}
+
+func (n *structNode) Children() []Node {
+	n.load()
+	return n.children
+}
+
+func (n *structNode) Value() string {
+	n.load()
+	return n.text
+}
+
+func (n *structNode) load() {
+	if n.loaded {
+		return
+	}
+	n.loaded = true
+	if n.kind == FlagNode {
+		return
+	}
+	v := n.value
+	if v.Kind() != reflect.Struct || isTextValue(v) {
+		n.text = formatValue(v)
+		return
+	}
+	var text strings.Builder
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || sf.Name == "XMLName" {
+			continue
+		}
+		name, opts := parseTag(sf.Tag.Get("xml"))
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		switch {
+		case opts["attr"]:
+			if isEmpty(fv) {
+				continue
+			}
+			n.flags = append(n.flags, &structNode{name: name, kind: FlagNode, text: formatValue(fv), parent: n, loaded: true})
+		case opts["chardata"]:
+			text.WriteString(formatValue(fv))
+		case opts["innerxml"], opts["any"]:
+			text.WriteString(markupText(formatValue(fv)))
+		default:
+			if name == "" {
+				name = strcase.ToKebab(sf.Name)
+			}
+			n.appendChildren(name, fv)
+		}
+	}
+	n.text = strings.TrimSpace(text.String())
+}
+
+func (n *structNode) appendChildren(name string, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			n.appendChildren(name, v.Index(i))
+		}
+		return
+	}
+	if v.Kind() == reflect.String && v.Len() == 0 {
+		return
+	}
+	n.children = append(n.children, newStructNode(name, v, n))
+}
+
+// parseTag returns local element name and options of xml struct tag. The
+// namespace and the names of grouping elements are dropped, as metapath
+// addresses nodes by names declared in the metaschema.
+func parseTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ">"); i >= 0 {
+		name = name[i+1:]
+	}
+	opts := make(map[string]bool, len(parts)-1)
+	for _, o := range parts[1:] {
+		opts[o] = true
+	}
+	return name, opts
+}
+
+func hasElements(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textMarshalerType) {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || sf.Name == "XMLName" {
+			continue
+		}
+		name, opts := parseTag(sf.Tag.Get("xml"))
+		if name == "-" || opts["attr"] || opts["chardata"] || opts["innerxml"] || opts["any"] || opts["comment"] {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func rootName(v reflect.Value) string {
+	if v.Kind() == reflect.Struct {
+		if sf, ok := v.Type().FieldByName("XMLName"); ok {
+			if name, _ := parseTag(sf.Tag.Get("xml")); name != "" {
+				return name
+			}
+		}
+		return strcase.ToKebab(v.Type().Name())
+	}
+	return ""
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func isTextValue(v reflect.Value) bool {
+	if v.Type().Implements(textMarshalerType) {
+		return true
+	}
+	return v.CanAddr() && v.Addr().Type().Implements(textMarshalerType)
+}
+
+// textValue is implemented by markup types, their value is the text content
+// rather than the serialized XHTML
+type textValue interface {
+	Text() string
+}
+
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(textValue); ok {
+		return t.Text()
+	}
+	var tm encoding.TextMarshaler
+	if v.Type().Implements(textMarshalerType) {
+		tm = v.Interface().(encoding.TextMarshaler)
+	} else if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		tm = v.Addr().Interface().(encoding.TextMarshaler)
+	}
+	if tm != nil {
+		text, err := tm.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil() || (v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.Len() == 0)
+	case reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// markupText returns text content of serialized markup
+func markupText(markup string) string {
+	if !strings.Contains(markup, "<") {
+		return markup
+	}
+	d := xml.NewDecoder(strings.NewReader("<m>" + markup + "</m>"))
+	var text strings.Builder
+	for {
+		t, err := d.Token()
+		if err != nil {
+			break
+		}
+		if cd, ok := t.(xml.CharData); ok {
+			text.Write(cd)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,179 @@
+package metapath
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Element is a node of a generic document tree. It does not require any
+// metaschema, elements with neither flags nor children are considered fields.
+type Element struct {
+	name     string
+	kind     NodeKind
+	value    string
+	parent   *Element
+	flags    []*Element
+	children []*Element
+}
+
+// NewDocument creates document node of a generic tree
+func NewDocument() *Element {
+	return &Element{kind: DocumentNode}
+}
+
+// NewElement creates an assembly or a field node named name
+func NewElement(name string) *Element {
+	return &Element{name: name, kind: AssemblyNode}
+}
+
+// AppendChild adds child element at the end of model children
+func (e *Element) AppendChild(child *Element) *Element {
+	child.parent = e
+	e.children = append(e.children, child)
+	return child
+}
+
+// SetFlag sets value of the named flag
+func (e *Element) SetFlag(name, value string) {
+	for _, f := range e.flags {
+		if f.name == name {
+			f.value = value
+			return
+		}
+	}
+	e.flags = append(e.flags, &Element{name: name, kind: FlagNode, value: value, parent: e})
+}
+
+// SetValue sets value of a field node
+func (e *Element) SetValue(value string) {
+	e.value = value
+}
+
+func (e *Element) Kind() NodeKind {
+	if e.kind == AssemblyNode && len(e.children) == 0 {
+		return FieldNode
+	}
+	return e.kind
+}
+
+func (e *Element) Name() string {
+	return e.name
+}
+
+func (e *Element) Parent() Node {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent
+}
+
+func (e *Element) Flags() []Node {
+	result := make([]Node, 0, len(e.flags))
+	for _, f := range e.flags {
+		result = append(result, f)
+	}
+	return result
+}
+
+func (e *Element) Children() []Node {
+	result := make([]Node, 0, len(e.children))
+	for _, c := range e.children {
+		result = append(result, c)
+	}
+	return result
+}
+
+func (e *Element) Value() string {
+	return e.value
+}
+
+// ParseXML reads XML document into a generic tree. Attributes become flags and
+// character data of leaf elements become field values. Mixed content (such as
+// markup) is kept as a value of its enclosing element.
+func ParseXML(r io.Reader) (*Element, error) {
+	d := xml.NewDecoder(r)
+	doc := NewDocument()
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return doc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			if err := parseXMLElement(d, doc, start); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func parseXMLElement(d *xml.Decoder, parent *Element, start xml.StartElement) error {
+	e := parent.AppendChild(NewElement(start.Name.Local))
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		e.SetFlag(attr.Name.Local, attr.Value)
+	}
+	var text strings.Builder
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			if isMarkupElement(v.Name.Local) {
+				inner, err := collectText(d)
+				if err != nil {
+					return err
+				}
+				text.WriteString(inner)
+				continue
+			}
+			if err := parseXMLElement(d, e, v); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if len(e.children) == 0 {
+				e.value = strings.TrimSpace(text.String())
+			}
+			return nil
+		}
+	}
+}
+
+// isMarkupElement recognizes inline elements of metaschema markup that are
+// part of field value rather than model children
+func isMarkupElement(name string) bool {
+	switch name {
+	case "a", "b", "br", "code", "em", "i", "img", "insert", "q", "strong", "sub", "sup":
+		return true
+	}
+	return false
+}
+
+func collectText(d *xml.Decoder) (string, error) {
+	var text strings.Builder
+	depth := 1
+	for depth > 0 {
+		t, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch v := t.(type) {
+		case xml.CharData:
+			text.Write(v)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return text.String(), nil
+}
@@ -0,0 +1,156 @@
+package metapath
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// atomize replaces nodes within the sequence by their values
+func atomize(s Sequence) Sequence {
+	result := make(Sequence, 0, len(s))
+	for _, item := range s {
+		if n, ok := item.(Node); ok {
+			result = append(result, UntypedAtomic(n.Value()))
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// effectiveBoolean computes effective boolean value of the sequence
+func effectiveBoolean(s Sequence) (bool, error) {
+	if len(s) == 0 {
+		return false, nil
+	}
+	if _, ok := s[0].(Node); ok {
+		return true, nil
+	}
+	if len(s) > 1 {
+		return false, fmt.Errorf("metapath: effective boolean value is not defined for sequence of %d atomic values", len(s))
+	}
+	switch v := s[0].(type) {
+	case bool:
+		return v, nil
+	case string:
+		return v != "", nil
+	case UntypedAtomic:
+		return v != "", nil
+	case float64:
+		return v != 0 && !math.IsNaN(v), nil
+	}
+	return false, fmt.Errorf("metapath: effective boolean value is not defined for %T", s[0])
+}
+
+func stringValue(item Item) string {
+	switch v := item.(type) {
+	case Node:
+		return v.Value()
+	case string:
+		return v
+	case UntypedAtomic:
+		return string(v)
+	case float64:
+		return formatNumber(v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	return fmt.Sprint(item)
+}
+
+// stringOf returns string value of the first item, empty string for empty
+// sequence
+func stringOf(s Sequence) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return stringValue(s[0])
+}
+
+func formatNumber(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	case f == math.Trunc(f) && math.Abs(f) < 1e15:
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func numberValue(item Item) float64 {
+	switch v := item.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(stringValue(item)), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return f
+}
+
+func booleanValue(item Item) bool {
+	switch v := item.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	}
+	s := strings.TrimSpace(stringValue(item))
+	return s == "true" || s == "1"
+}
+
+// compareAtomic compares two atomic values. Untyped values are converted to
+// the type of the other operand.
+func compareAtomic(op string, a, b Item) bool {
+	_, aBool := a.(bool)
+	_, bBool := b.(bool)
+	if aBool || bBool {
+		return compareOrdered(op, boolToNumber(booleanValue(a)), boolToNumber(booleanValue(b)))
+	}
+	_, aNum := a.(float64)
+	_, bNum := b.(float64)
+	if aNum || bNum {
+		return compareOrdered(op, numberValue(a), numberValue(b))
+	}
+	return compareOrdered(op, stringValue(a), stringValue(b))
+}
+
+func boolToNumber(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func compareOrdered[T float64 | string](op string, a, b T) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
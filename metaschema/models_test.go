@@ -0,0 +1,103 @@
+package metaschema
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// modelsModule is the go import path of this package, code generated into its
+// testdata is imported relative to it
+const modelsModule = "github.com/gocomply/metaschema/metaschema"
+
+// TestGeneratedModels generates go code of the metaschemas found in
+// testdata/models into a temporary directory of this module, copies there the
+// tests found in testdata/models/<package> and runs them by go test, so that
+// the behaviour of the generated code is checked, not just its text. Proto
+// conversions are generated as well, the models have to build without the
+// output of protoc.
+func TestGeneratedModels(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go test of generated code skipped in short mode")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	outputDir, err := os.MkdirTemp("testdata", "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(outputDir)
+	})
+	modelsDir := filepath.Join("testdata", "models")
+	if err := Generate(modelsDir, modelsModule, outputDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateProto(modelsDir, modelsModule, outputDir); err != nil {
+		t.Fatal(err)
+	}
+	checkComments(t, outputDir)
+	tests, err := filepath.Glob(filepath.Join(modelsDir, "*", "*_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		source, err := os.ReadFile(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkg := filepath.Base(filepath.Dir(test))
+		if err := os.WriteFile(filepath.Join(outputDir, pkg, filepath.Base(test)), source, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	packages, err := filepath.Glob(filepath.Join(outputDir, "*", "generated_models.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) == 0 {
+		t.Fatal("no models generated")
+	}
+	args := []string{"test"}
+	for _, p := range packages {
+		args = append(args, "./"+filepath.ToSlash(filepath.Dir(p)))
+	}
+	out, err := exec.Command(goCmd, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test of generated code: %v\n%s", err, out)
+	}
+}
+
+// checkComments reports comment lines left empty, definitions and flags
+// without description are generated without comment, and comments separated
+// from the declaration they document by an empty line
+func checkComments(t *testing.T, dir string) {
+	t.Helper()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(string(source), "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "//" && (i == 0 || !strings.HasPrefix(strings.TrimSpace(lines[i-1]), "//")) {
+				t.Errorf("%s:%d: empty comment", path, i+1)
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), "//") && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+				t.Errorf("%s:%d: comment detached from declaration", path, i+1)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
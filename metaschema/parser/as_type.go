@@ -1,5 +1,7 @@
 package parser
 
+import "strings"
+
 type AsType string
 
 const (
@@ -10,9 +12,17 @@ const (
 	AsTypeMarkupLine         AsType = "markup-line"
 	AsTypeMarkupMultiLine    AsType = "markup-multiline"
 	AsTypeDate               AsType = "date"
+	AsTypeDateTZ             AsType = "date-with-timezone"
+	AsTypeDateTime           AsType = "dateTime"
 	AsTypeDateTimeTZ         AsType = "dateTime-with-timezone"
+	AsTypeDayTimeDuration    AsType = "day-time-duration"
+	AsTypeYearMonthDuration  AsType = "year-month-duration"
 	AsTypeNCName             AsType = "NCName"
+	AsTypeToken              AsType = "token"
 	AsTypeEmail              AsType = "email"
+	AsTypeHostname           AsType = "hostname"
+	AsTypeIPv4Address        AsType = "ip-v4-address"
+	AsTypeIPv6Address        AsType = "ip-v6-address"
 	AsTypeURI                AsType = "uri"
 	AsTypeBase64             AsType = "base64Binary"
 	AsTypeIDRef              AsType = "IDREF"
@@ -21,17 +31,92 @@ const (
 	AsTypeAnyURI             AsType = "anyURI"
 	AsTypeURIRef             AsType = "uri-reference"
 	AsTypeUUID               AsType = "uuid"
+	AsTypeDecimal            AsType = "decimal"
+	AsTypeInteger            AsType = "integer"
 	AsTypeNonNegativeInteger AsType = "nonNegativeInteger"
+	AsTypePositiveInteger    AsType = "positiveInteger"
 )
 
+// asTypeAliases maps alternative names used by newer metaschema versions to
+// the names used by this package
+var asTypeAliases = map[AsType]AsType{
+	"date-time":               AsTypeDateTime,
+	"date-time-with-timezone": AsTypeDateTimeTZ,
+	"non-negative-integer":    AsTypeNonNegativeInteger,
+	"positive-integer":        AsTypePositiveInteger,
+	"base64":                  AsTypeBase64,
+	"email-address":           AsTypeEmail,
+}
+
+// Canonical returns the name of the data type used by this package
+func (t AsType) Canonical() AsType {
+	if alias, ok := asTypeAliases[t]; ok {
+		return alias
+	}
+	return t
+}
+
+// DatatypeName returns name of the data type definition within generated
+// schemas, for example NCNameDatatype or DateTimeWithTimezoneDatatype
+func (t AsType) DatatypeName() string {
+	var name strings.Builder
+	for _, part := range strings.Split(string(t.Canonical()), "-") {
+		if part != "" {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return name.String() + "Datatype"
+}
+
+// goDatatypeMap maps data types to go types. Types of values that need
+// parsing are wrappers of the datatype package, they are used through
+// pointers so that zero values are not omitted.
 var goDatatypeMap = map[AsType]string{
 	AsTypeString:             "string",
 	AsTypeIDRef:              "string",
 	AsTypeNCName:             "string",
 	AsTypeNMToken:            "string",
 	AsTypeID:                 "string",
+	AsTypeToken:              "string",
 	AsTypeURIRef:             "string",
 	AsTypeURI:                "string",
+	AsTypeAnyURI:             "string",
 	AsTypeUUID:               "string",
-	AsTypeNonNegativeInteger: "uint64",
+	AsTypeEmail:              "string",
+	AsTypeHostname:           "string",
+	AsTypeIPv4Address:        "string",
+	AsTypeIPv6Address:        "string",
+	AsTypeDayTimeDuration:    "string",
+	AsTypeYearMonthDuration:  "string",
+	AsTypeBoolean:            "*datatype.Boolean",
+	AsTypeInteger:            "*datatype.Integer",
+	AsTypeNonNegativeInteger: "*datatype.NonNegativeInteger",
+	AsTypePositiveInteger:    "*datatype.PositiveInteger",
+	AsTypeDecimal:            "*datatype.Decimal",
+	AsTypeDate:               "*datatype.Date",
+	AsTypeDateTZ:             "*datatype.DateWithTimezone",
+	AsTypeDateTime:           "*datatype.DateTime",
+	AsTypeDateTimeTZ:         "*datatype.DateTimeWithTimezone",
+	AsTypeBase64:             "*datatype.Base64",
+}
+
+// UsesDatatype returns true when generated types refer to the datatype
+// package
+func (metaschema *Metaschema) UsesDatatype() bool {
+	var flags []Flag
+	for _, da := range metaschema.AllDefineAssemblies() {
+		flags = append(flags, da.Flags...)
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		if df.HasTypedValue() {
+			return true
+		}
+		flags = append(flags, df.Flags...)
+	}
+	for i := range flags {
+		if dt, err := flags[i].GoDatatype(); err == nil && strings.HasPrefix(dt, "*datatype.") {
+			return true
+		}
+	}
+	return false
 }
@@ -10,22 +10,74 @@ type DefineAssembly struct {
 	Name    string `xml:"name,attr"`
 	Address string `xml:"address,attr"`
 
-	JsonKey     *JsonKey  `xml:"json-key"`
-	Flags       []Flag    `xml:"flag"`
-	FormalName  string    `xml:"formal-name"`
-	Description string    `xml:"description"`
-	Remarks     *Remarks  `xml:"remarks"`
-	Model       *Model    `xml:"model"`
-	Examples    []Example `xml:"example"`
-	Metaschema  *Metaschema
+	// RootName is declared on assemblies that may be used as root element of
+	// an instance document
+	RootName    string      `xml:"root-name"`
+	JsonKey     *JsonKey    `xml:"json-key"`
+	Flags       []Flag      `xml:"flag"`
+	DefineFlag  []Flag      `xml:"define-flag"`
+	FormalName  string      `xml:"formal-name"`
+	Description string      `xml:"description"`
+	Remarks     *Remarks    `xml:"remarks"`
+	Model       *Model      `xml:"model"`
+	Constraint  *Constraint `xml:"constraint"`
+	Examples    []Example   `xml:"example"`
+	// GroupAs and occurrences are only used by inline (local) definitions
+	// within a model
+	GroupAs    *GroupAs `xml:"group-as"`
+	MinOccurs  string   `xml:"min-occurs,attr"`
+	MaxOccurs  string   `xml:"max-occurs,attr"`
+	Metaschema *Metaschema
+	// Pos is the location of the definition within the metaschema source
+	Pos Position `xml:"-"`
+
+	// parent is the definition enclosing an inline (local) definition
+	parent GoType
 }
 
 func (da *DefineAssembly) GoTypeName() string {
+	if da.parent != nil {
+		return da.parent.GoTypeName() + strcase.ToCamel(da.Name)
+	}
 	return strcase.ToCamel(da.Name)
 }
 
+// IsInline returns true for definitions declared locally within a model
+func (da *DefineAssembly) IsInline() bool {
+	return da.parent != nil
+}
+
+// RepresentsRootElement returns true for assemblies declared by <root-name>
+// or by the top or root attribute of older metaschemas
 func (da *DefineAssembly) RepresentsRootElement() bool {
-	return da.Name == "catalog" || da.Name == "profile" || da.Name == "declarations" || da.Name == "system-security-plan"
+	if da.IsInline() {
+		return false
+	}
+	if da.RootName != "" {
+		return true
+	}
+	if da.Metaschema == nil {
+		return false
+	}
+	return (da.Metaschema.Top != "" && da.Metaschema.Top == da.Name) ||
+		(da.Metaschema.Root != "" && da.Metaschema.Root == da.Name)
+}
+
+// RootXmlAnnotation is the xml struct tag of the XMLName member
+func (da *DefineAssembly) RootXmlAnnotation() string {
+	if da.Metaschema == nil || da.Metaschema.XmlNamespace() == "" {
+		return da.RootXmlName()
+	}
+	return da.Metaschema.XmlNamespace() + " " + da.RootXmlName()
+}
+
+// RootXmlName is the element name used when the assembly is the root of an
+// instance document
+func (da *DefineAssembly) RootXmlName() string {
+	if da.RootName != "" {
+		return da.RootName
+	}
+	return da.Name
 }
 
 func (a *DefineAssembly) GoComment() string {
@@ -40,9 +92,14 @@ type Assembly struct {
 	Description string   `xml:"description"`
 	Remarks     *Remarks `xml:"remarks"`
 	Ref         string   `xml:"ref,attr"`
+	MinOccurs   string   `xml:"min-occurs,attr"`
+	MaxOccurs   string   `xml:"max-occurs,attr"`
 	GroupAs     *GroupAs `xml:"group-as"`
 	Def         *DefineAssembly
 	Metaschema  *Metaschema
+	choice      *Choice
+	// Pos is the location of the reference within the metaschema source
+	Pos Position `xml:"-"`
 }
 
 func (a *Assembly) GoComment() string {
@@ -52,6 +109,17 @@ func (a *Assembly) GoComment() string {
 	return a.Def.GoComment()
 }
 
+func (a *Assembly) GoChoiceComment() string {
+	return choiceComment(a.choice)
+}
+
+func (a *Assembly) GoIsSet(receiver string) string {
+	if a.GoMemLayout() == "*" {
+		return receiver + "." + a.GoName() + " != nil"
+	}
+	return "len(" + receiver + "." + a.GoName() + ") > 0"
+}
+
 func (a *Assembly) GoTypeName() string {
 	return a.Def.GoTypeName()
 }
@@ -88,6 +156,12 @@ func (a *Assembly) XmlName() string {
 	return a.Def.Name
 }
 
+// IsInline returns true when the assembly carries its own (local) definition
+// instead of referencing a top-level one
+func (a *Assembly) IsInline() bool {
+	return a.Ref == "" && a.Def != nil
+}
+
 func (a *Assembly) GoPackageName() string {
 	if a.Ref == "" {
 		return ""
@@ -98,35 +172,34 @@ func (a *Assembly) GoPackageName() string {
 	}
 }
 
+func (a *Assembly) setChoice(c *Choice) {
+	a.choice = c
+}
+
 func (a *Assembly) groupAs() *GroupAs {
 	return a.GroupAs
 }
 
+// IndexBy returns go name of the flag keying BY_KEY group of the assembly,
+// Compile reports definitions of such groups without <json-key>
 func (a *Assembly) IndexBy() string {
-	if a.Def == nil {
-		panic("Not implemented: IndexBy requires define-assembly to exist")
-	}
-	if a.Def.JsonKey == nil {
-		panic("Not implemented: IndexBy requires define-assembly to define <json-key>")
+	if a.Def == nil || a.Def.JsonKey == nil {
+		return ""
 	}
 	return strcase.ToCamel(a.Def.JsonKey.FlagName)
 }
 
+// XmlGroupping returns the wrapper element of GROUPED assemblies, Compile
+// reports other values of group-as/@in-xml than GROUPED and UNGROUPED
 func (a *Assembly) XmlGroupping() string {
-	if a.GroupAs == nil {
+	if a.GroupAs == nil || a.GroupAs.InXml != "GROUPED" {
 		return ""
 	}
-	if a.GroupAs.InXml == "UNGROUPED" || a.GroupAs.InXml == "" {
-		return ""
-	}
-	if a.GroupAs.InXml == "GROUPED" {
-		return a.GroupAs.Name + ">"
-	}
-	panic("Not implemented group-as/@in-xml=" + a.GroupAs.InXml)
+	return a.GroupAs.Name + ">"
 }
 
 func (a *Assembly) XmlAnnotation() string {
-	return a.XmlGroupping() + a.XmlName() + ",omitempty"
+	return xmlNamespacePrefix(a.Metaschema, a.Def.Metaschema) + a.XmlGroupping() + a.XmlName() + ",omitempty"
 }
 
 func (a *Assembly) JsonAnnotation() string {
@@ -134,14 +207,18 @@ func (a *Assembly) JsonAnnotation() string {
 }
 
 func (a *Assembly) compile(metaschema *Metaschema) error {
+	a.Metaschema = metaschema
 	if a.Ref != "" {
 		var err error
-		a.Metaschema = metaschema
 		a.Def, err = a.Metaschema.GetDefineAssembly(a.Ref)
 		if err != nil {
-			return err
+			return metaschema.errorf(a.Pos, "%v", err)
 		}
 		a.Metaschema.registerDependency(a.Ref, a.Def)
+	} else if a.Def != nil {
+		a.Def.Metaschema = metaschema
+	} else {
+		return metaschema.errorf(a.Pos, "Assembly element is missing both 'ref' attribute and local definition.")
 	}
 	return nil
 }
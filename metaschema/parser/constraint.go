@@ -0,0 +1,370 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ConstraintLevel is the severity of violating a constraint
+type ConstraintLevel string
+
+const (
+	ConstraintLevelCritical      ConstraintLevel = "CRITICAL"
+	ConstraintLevelError         ConstraintLevel = "ERROR"
+	ConstraintLevelWarning       ConstraintLevel = "WARNING"
+	ConstraintLevelInformational ConstraintLevel = "INFORMATIONAL"
+	ConstraintLevelDebug         ConstraintLevel = "DEBUG"
+)
+
+// Constraint is the <constraint> element of a definition. It groups rules
+// that instance documents have to satisfy on top of the model structure.
+type Constraint struct {
+	AllowedValues  []AllowedValues  `xml:"allowed-values"`
+	Matches        []Matches        `xml:"matches"`
+	Index          []Index          `xml:"index"`
+	IndexHasKey    []IndexHasKey    `xml:"index-has-key"`
+	IsUnique       []IsUnique       `xml:"is-unique"`
+	HasCardinality []HasCardinality `xml:"has-cardinality"`
+	Expect         []Expect         `xml:"expect"`
+}
+
+// Rules returns all rules of the constraint in the order of their kinds
+func (c *Constraint) Rules() []Rule {
+	if c == nil {
+		return nil
+	}
+	var rules []Rule
+	for i := range c.AllowedValues {
+		rules = append(rules, &c.AllowedValues[i])
+	}
+	for i := range c.Matches {
+		rules = append(rules, &c.Matches[i])
+	}
+	for i := range c.Index {
+		rules = append(rules, &c.Index[i])
+	}
+	for i := range c.IndexHasKey {
+		rules = append(rules, &c.IndexHasKey[i])
+	}
+	for i := range c.IsUnique {
+		rules = append(rules, &c.IsUnique[i])
+	}
+	for i := range c.HasCardinality {
+		rules = append(rules, &c.HasCardinality[i])
+	}
+	for i := range c.Expect {
+		rules = append(rules, &c.Expect[i])
+	}
+	return rules
+}
+
+// Rule is a single constraint rule such as allowed-values or expect
+type Rule interface {
+	// Kind returns name of the element that declared the rule
+	Kind() string
+	// Base returns the properties shared by all the rules
+	Base() *ConstraintBase
+}
+
+// ConstraintBase holds properties shared by all the constraint rules
+type ConstraintBase struct {
+	Id     string          `xml:"id,attr"`
+	Level  ConstraintLevel `xml:"level,attr"`
+	Target string          `xml:"target,attr"`
+
+	FormalName  string   `xml:"formal-name"`
+	Description string   `xml:"description"`
+	Message     string   `xml:"message"`
+	Remarks     *Remarks `xml:"remarks"`
+
+	// Pos is the location of the rule within the metaschema source
+	Pos   Position `xml:"-"`
+	owner GoType
+}
+
+func (cb *ConstraintBase) Base() *ConstraintBase {
+	return cb
+}
+
+// EffectiveLevel returns the declared level or ERROR when not declared
+func (cb *ConstraintBase) EffectiveLevel() ConstraintLevel {
+	if cb.Level == "" {
+		return ConstraintLevelError
+	}
+	return cb.Level
+}
+
+// EffectiveTarget returns the declared metapath target or the context node
+// when not declared
+func (cb *ConstraintBase) EffectiveTarget() string {
+	target := strings.TrimSpace(cb.Target)
+	if target == "" {
+		return "."
+	}
+	return target
+}
+
+// TargetsSelf returns true when the rule applies to the definition itself
+func (cb *ConstraintBase) TargetsSelf() bool {
+	return cb.EffectiveTarget() == "."
+}
+
+// Owner returns the definition that declared the rule
+func (cb *ConstraintBase) Owner() GoType {
+	return cb.owner
+}
+
+// AllowedValues restricts the value of the target to the enumerated ones
+type AllowedValues struct {
+	ConstraintBase
+	AllowOther string `xml:"allow-other,attr"`
+	Extension  string `xml:"extension,attr"`
+	Enum       []Enum `xml:"enum"`
+}
+
+func (av *AllowedValues) Kind() string {
+	return "allowed-values"
+}
+
+// AllowsOther returns true when values not enumerated are still acceptable
+func (av *AllowedValues) AllowsOther() bool {
+	return av.AllowOther == "yes"
+}
+
+// Values returns the enumerated values
+func (av *AllowedValues) Values() []string {
+	values := make([]string, 0, len(av.Enum))
+	for _, e := range av.Enum {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+// Enum is a single value enumerated by allowed-values
+type Enum struct {
+	Value      string `xml:"value,attr"`
+	Deprecated string `xml:"deprecated,attr"`
+
+	InnerXML string `xml:",innerxml"`
+}
+
+// Matches requires the target value to satisfy given data type and/or regular
+// expression
+type Matches struct {
+	ConstraintBase
+	Datatype AsType `xml:"datatype,attr"`
+	Regex    string `xml:"regex,attr"`
+}
+
+func (m *Matches) Kind() string {
+	return "matches"
+}
+
+// KeyField is a component of a key used by index, index-has-key and
+// is-unique rules
+type KeyField struct {
+	Target  string   `xml:"target,attr"`
+	Pattern string   `xml:"pattern,attr"`
+	Remarks *Remarks `xml:"remarks"`
+}
+
+// Index declares named index of target nodes by their keys
+type Index struct {
+	ConstraintBase
+	Name     string     `xml:"name,attr"`
+	KeyField []KeyField `xml:"key-field"`
+}
+
+func (i *Index) Kind() string {
+	return "index"
+}
+
+// IndexHasKey requires the key of each target to be present in the named
+// index
+type IndexHasKey struct {
+	ConstraintBase
+	Name     string     `xml:"name,attr"`
+	KeyField []KeyField `xml:"key-field"`
+
+	// Index is the resolved index referenced by Name
+	Index *Index `xml:"-"`
+}
+
+func (ihk *IndexHasKey) Kind() string {
+	return "index-has-key"
+}
+
+// IsUnique requires keys of the target nodes to be unique
+type IsUnique struct {
+	ConstraintBase
+	KeyField []KeyField `xml:"key-field"`
+}
+
+func (iu *IsUnique) Kind() string {
+	return "is-unique"
+}
+
+// HasCardinality restricts number of the target nodes
+type HasCardinality struct {
+	ConstraintBase
+	MinOccurs string `xml:"min-occurs,attr"`
+	MaxOccurs string `xml:"max-occurs,attr"`
+}
+
+func (hc *HasCardinality) Kind() string {
+	return "has-cardinality"
+}
+
+// Min returns the minimal number of occurrences, 0 if not declared
+func (hc *HasCardinality) Min() int {
+	n, err := strconv.Atoi(hc.MinOccurs)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Max returns the maximal number of occurrences, -1 if unbounded or not
+// declared
+func (hc *HasCardinality) Max() int {
+	n, err := strconv.Atoi(hc.MaxOccurs)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Expect requires the metapath test to evaluate to true for each target
+type Expect struct {
+	ConstraintBase
+	Test string `xml:"test,attr"`
+}
+
+func (e *Expect) Kind() string {
+	return "expect"
+}
+
+func (metaschema *Metaschema) linkConstraints() error {
+	for _, da := range metaschema.AllDefineAssemblies() {
+		bindConstraint(da.Constraint, da)
+		bindFlagRules(da.Constraint, da.Flags)
+		for i := range da.Flags {
+			da.Flags[i].ownerTypeName = da.GoTypeName()
+			da.Flags[i].linkConstraint()
+			metaschema.registerEnum(&da.Flags[i])
+		}
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		bindConstraint(df.Constraint, df)
+		bindFlagRules(df.Constraint, df.Flags)
+		for i := range df.Flags {
+			df.Flags[i].ownerTypeName = df.GoTypeName()
+			df.Flags[i].linkConstraint()
+			metaschema.registerEnum(&df.Flags[i])
+		}
+	}
+	for i := range metaschema.DefineFlag {
+		df := &metaschema.DefineFlag[i]
+		df.Metaschema = metaschema
+		bindConstraint(df.Constraint, df)
+	}
+
+	var errs []error
+	for _, rule := range metaschema.allRules() {
+		ihk, ok := rule.(*IndexHasKey)
+		if !ok {
+			continue
+		}
+		ihk.Index = metaschema.getIndex(ihk.Name)
+		if ihk.Index == nil {
+			errs = append(errs, metaschema.errorf(ihk.Pos, "Could not find index with name='%s' referenced by index-has-key.", ihk.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (metaschema *Metaschema) allRules() []Rule {
+	var rules []Rule
+	for _, da := range metaschema.AllDefineAssemblies() {
+		rules = append(rules, da.Constraint.Rules()...)
+		for i := range da.Flags {
+			rules = append(rules, da.Flags[i].Constraint.Rules()...)
+		}
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		rules = append(rules, df.Constraint.Rules()...)
+		for i := range df.Flags {
+			rules = append(rules, df.Flags[i].Constraint.Rules()...)
+		}
+	}
+	for i := range metaschema.DefineFlag {
+		rules = append(rules, metaschema.DefineFlag[i].Constraint.Rules()...)
+	}
+	return rules
+}
+
+func (metaschema *Metaschema) getIndex(name string) *Index {
+	for _, rule := range metaschema.allRules() {
+		if index, ok := rule.(*Index); ok && index.Name == name {
+			return index
+		}
+	}
+	for _, m := range metaschema.ImportedMetaschema {
+		if index := m.getIndex(name); index != nil {
+			return index
+		}
+	}
+	return nil
+}
+
+func bindConstraint(c *Constraint, owner GoType) {
+	for _, rule := range c.Rules() {
+		rule.Base().owner = owner
+	}
+}
+
+// linkConstraint binds constraint rules of the flag to its parent and
+// converts legacy <value> elements to an open allowed-values rule
+func (f *Flag) linkConstraint() {
+	if len(f.Values) > 0 && !f.legacyValuesLinked {
+		av := AllowedValues{AllowOther: "yes"}
+		for _, v := range f.Values {
+			av.Enum = append(av.Enum, Enum{Value: strings.TrimSpace(v.InnerXML)})
+		}
+		if f.Constraint == nil {
+			f.Constraint = &Constraint{}
+		}
+		f.Constraint.AllowedValues = append(f.Constraint.AllowedValues, av)
+		f.legacyValuesLinked = true
+	}
+	bindConstraint(f.Constraint, f)
+}
+
+// registerEnum registers enum type of flag defined by imported metaschema as
+// dependency, the type is declared within the package of the definition
+func (metaschema *Metaschema) registerEnum(f *Flag) {
+	if f.sharesEnum() && f.GoEnum() != nil {
+		metaschema.registerDependency(f.Def.GoTypeName(), f.Def)
+	}
+}
+
+// Rules returns constraint rules applicable to the flag, including the ones
+// declared by its definition
+func (f *Flag) Rules() []Rule {
+	rules := f.Constraint.Rules()
+	if f.Def != nil {
+		rules = append(rules, f.Def.Constraint.Rules()...)
+	}
+	return rules
+}
+
+// Rules returns constraint rules declared by the definition of the field
+func (f *Field) Rules() []Rule {
+	return f.Def.Constraint.Rules()
+}
+
+// Rules returns constraint rules declared by the definition of the assembly
+func (a *Assembly) Rules() []Rule {
+	return a.Def.Constraint.Rules()
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Position is the location of an element within the metaschema source. It
+// is taken from xml.Decoder.InputPos when the element is decoded, that is
+// the end of its start tag.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func positionOf(d *xml.Decoder) Position {
+	line, column := d.InputPos()
+	return Position{Line: line, Column: column}
+}
+
+// Diagnostic is an error found in a metaschema module together with the
+// location of the element that caused it
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Error renders the diagnostic in the style of compiler messages:
+// file:line:column: severity: message
+func (d *Diagnostic) Error() string {
+	var location []string
+	if d.File != "" {
+		location = append(location, d.File)
+	}
+	if d.Line > 0 {
+		location = append(location, fmt.Sprint(d.Line))
+		if d.Column > 0 {
+			location = append(location, fmt.Sprint(d.Column))
+		}
+	}
+	message := d.Severity + ": " + d.Message
+	if len(location) == 0 {
+		return message
+	}
+	return strings.Join(location, ":") + ": " + message
+}
+
+// NewDiagnostic returns error diagnostic at the position within the file
+func NewDiagnostic(file string, pos Position, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{
+		File:     file,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Severity: "error",
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Diagnostics returns diagnostics found within the error, joined errors
+// give one diagnostic each. Errors that carry no diagnostic are returned as
+// diagnostics without location.
+func Diagnostics(err error) []*Diagnostic {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []*Diagnostic
+		for _, e := range joined.Unwrap() {
+			result = append(result, Diagnostics(e)...)
+		}
+		return result
+	}
+	var d *Diagnostic
+	if errors.As(err, &d) {
+		return []*Diagnostic{d}
+	}
+	return []*Diagnostic{{Severity: "error", Message: err.Error()}}
+}
+
+// SourcePath returns path of the file the metaschema was loaded from, the
+// path is relative to the working directory when the file lies within it
+func (metaschema *Metaschema) SourcePath() string {
+	return sourcePath(metaschema.URI)
+}
+
+func sourcePath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := FilePath(u)
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
+
+// FilePath returns operating system path of the file:// URI. The slash
+// preceding drive letter of Windows paths (file:///C:/dir) is dropped.
+func FilePath(u *url.URL) string {
+	p := u.Path
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' && isDriveLetter(p[1]) {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+func isDriveLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// errorf returns diagnostic of the element at pos within the metaschema
+func (metaschema *Metaschema) errorf(pos Position, format string, args ...interface{}) error {
+	var file string
+	if metaschema != nil {
+		file = metaschema.SourcePath()
+	}
+	return NewDiagnostic(file, pos, format, args...)
+}
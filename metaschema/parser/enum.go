@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+)
+
+// GoEnum is a named string type generated for a flag or field value that has
+// allowed-values rules. A constant is generated for each enumerated value.
+type GoEnum struct {
+	GoTypeName string
+	// Comment is the go comment of the type
+	Comment string
+	Values  []GoEnumValue
+	// Allowed lists values accepted when the type is marshalled or
+	// unmarshalled, it is empty when other values are allowed as well
+	Allowed []string
+	// FieldType is true when the enum is the type of a field without flags,
+	// which is declared along with the field
+	FieldType bool
+}
+
+// GoEnumValue is a constant of the enum type
+type GoEnumValue struct {
+	GoName  string
+	Value   string
+	Comment string
+}
+
+// Closed returns true when values not enumerated are rejected
+func (e *GoEnum) Closed() bool {
+	return len(e.Allowed) > 0
+}
+
+// GoEnum returns the enum type of the flag value, nil when the flag has no
+// allowed-values or its value is not a go string. Flags whose values are
+// restricted by their definition only share the enum type of the definition.
+func (f *Flag) GoEnum() *GoEnum {
+	if (f.Name == "" && f.Def == nil) || !f.HasStringValue() || f.ownerTypeName == "" {
+		// references to missing definitions are reported by Compile
+		return nil
+	}
+	if f.sharesEnum() {
+		return f.Def.GoEnum()
+	}
+	return newGoEnum(f.ownerTypeName+f.GoName(), f.GoComment(), f.valueRules())
+}
+
+// sharesEnum returns true when the flag refers to a definition and adds no
+// rules of its own to the values allowed by the definition
+func (f *Flag) sharesEnum() bool {
+	return f.Def != nil && f.Name == "" && (f.AsType == "" || f.AsType == f.Def.AsType) &&
+		len(selfRules(f.Constraint.Rules())) == 0 && len(f.parentRules) == 0
+}
+
+// GoEnum returns the enum type shared by the flags referring to the
+// definition, nil when the definition has no allowed-values or its value is
+// not a go string
+func (df *DefineFlag) GoEnum() *GoEnum {
+	asType := df.AsType
+	if asType == "" {
+		asType = AsTypeString
+	}
+	if goDatatypeMap[asType.Canonical()] != "string" {
+		return nil
+	}
+	return newGoEnum(df.GoTypeName(), handleMultiline(df.Description), selfRules(df.Constraint.Rules()))
+}
+
+// GoEnum returns the enum type of the field value, nil when the value has no
+// allowed-values or is not a go string. The field type itself is the enum
+// type when the field has no flags.
+func (df *DefineField) GoEnum() *GoEnum {
+	if !df.HasStringValue() || df.HasTypedValue() {
+		return nil
+	}
+	name := df.GoTypeName()
+	if len(df.Flags) > 0 {
+		name += df.GoName()
+	}
+	e := newGoEnum(name, df.GoComment(), selfRules(df.Constraint.Rules()))
+	if e != nil {
+		e.FieldType = len(df.Flags) == 0
+	}
+	return e
+}
+
+// GoValueType returns go type of the flag member, that is the enum type or
+// the data type of the flag
+func (f *Flag) GoValueType() (string, error) {
+	if e := f.GoEnum(); e != nil {
+		return e.GoTypeName, nil
+	}
+	return f.GoDatatype()
+}
+
+// GoValueType returns go type of the value member of the field with flags
+func (df *DefineField) GoValueType() (string, error) {
+	if e := df.GoEnum(); e != nil {
+		return e.GoTypeName, nil
+	}
+	return df.GoDatatype()
+}
+
+// GoEnums returns enum types of all the flags and fields defined by the
+// metaschema
+func (metaschema *Metaschema) GoEnums() []*GoEnum {
+	var result []*GoEnum
+	for i := range metaschema.DefineFlag {
+		if e := metaschema.DefineFlag[i].GoEnum(); e != nil {
+			result = append(result, e)
+		}
+	}
+	flagEnums := func(flags []Flag) {
+		for i := range flags {
+			if flags[i].sharesEnum() {
+				// declared along with the definition
+				continue
+			}
+			if e := flags[i].GoEnum(); e != nil {
+				result = append(result, e)
+			}
+		}
+	}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		flagEnums(da.Flags)
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		flagEnums(df.Flags)
+		if e := df.GoEnum(); e != nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// newGoEnum collects values of all the allowed-values rules. Values are
+// restricted when any of the rules of error level does not allow other
+// values.
+func newGoEnum(typeName, comment string, rules []Rule) *GoEnum {
+	e := GoEnum{GoTypeName: typeName, Comment: comment}
+	seen := map[string]bool{}
+	names := map[string]bool{}
+	for _, r := range rules {
+		av, ok := r.(*AllowedValues)
+		if !ok {
+			continue
+		}
+		for _, enum := range av.Enum {
+			if !av.AllowsOther() && isEnforced(av) {
+				e.Allowed = appendUnique(e.Allowed, enum.Value)
+			}
+			if seen[enum.Value] {
+				continue
+			}
+			seen[enum.Value] = true
+			name := typeName + goEnumName(enum.Value)
+			for i := 2; names[name]; i++ {
+				name = typeName + goEnumName(enum.Value) + strconv.Itoa(i)
+			}
+			names[name] = true
+			e.Values = append(e.Values, GoEnumValue{GoName: name, Value: enum.Value, Comment: enum.goComment()})
+		}
+	}
+	if len(e.Values) == 0 {
+		return nil
+	}
+	return &e
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// goEnumName turns the value into suffix of go identifier
+func goEnumName(value string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, value)
+	name = strcase.ToCamel(strings.Join(strings.Fields(name), "-"))
+	if name == "" {
+		return "Empty"
+	}
+	return name
+}
+
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+func (e *Enum) goComment() string {
+	text := html.UnescapeString(tagRe.ReplaceAllString(e.InnerXML, ""))
+	comment := strings.Join(strings.Fields(text), " ")
+	if e.Deprecated != "" {
+		if comment != "" {
+			comment += "\n\n"
+		}
+		comment += "Deprecated: since version " + e.Deprecated
+	}
+	return handleMultiline(comment)
+}
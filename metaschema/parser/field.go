@@ -1,29 +1,52 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/iancoleman/strcase"
 )
 
 type DefineField struct {
 	Name string `xml:"name,attr"`
 
-	Flags        []Flag    `xml:"flag"`
-	FormalName   string    `xml:"formal-name"`
-	Description  string    `xml:"description"`
-	Remarks      *Remarks  `xml:"remarks"`
-	Examples     []Example `xml:"example"`
-	AsType       AsType    `xml:"as-type,attr"`
-	JsonKey      *JsonKey  `xml:"json-key"`
-	JsonValueKey string    `xml:"json-value-key"`
-	Metaschema   *Metaschema
+	Flags        []Flag      `xml:"flag"`
+	DefineFlag   []Flag      `xml:"define-flag"`
+	FormalName   string      `xml:"formal-name"`
+	Description  string      `xml:"description"`
+	Remarks      *Remarks    `xml:"remarks"`
+	Constraint   *Constraint `xml:"constraint"`
+	Examples     []Example   `xml:"example"`
+	AsType       AsType      `xml:"as-type,attr"`
+	JsonKey      *JsonKey    `xml:"json-key"`
+	JsonValueKey string      `xml:"json-value-key"`
+	// GroupAs, InXml and occurrences are only used by inline (local)
+	// definitions within a model
+	GroupAs    *GroupAs `xml:"group-as"`
+	InXml      string   `xml:"in-xml,attr"`
+	MinOccurs  string   `xml:"min-occurs,attr"`
+	MaxOccurs  string   `xml:"max-occurs,attr"`
+	Metaschema *Metaschema
+	// Pos is the location of the definition within the metaschema source
+	Pos Position `xml:"-"`
+
+	// parent is the definition enclosing an inline (local) definition
+	parent GoType
 }
 
 func (df *DefineField) GoTypeName() string {
+	if df.parent != nil {
+		return df.parent.GoTypeName() + strcase.ToCamel(df.Name)
+	}
 	return strcase.ToCamel(df.Name)
 }
 
+// IsInline returns true for definitions declared locally within a model
+func (df *DefineField) IsInline() bool {
+	return df.parent != nil
+}
+
 func (df *DefineField) requiresPointer() bool {
-	return len(df.Flags) > 0 || df.IsMarkup()
+	return len(df.Flags) > 0 || df.IsMarkup() || df.HasTypedValue()
 }
 
 func (f *DefineField) GoComment() string {
@@ -38,6 +61,28 @@ func (df *DefineField) IsMarkup() bool {
 	return df.AsType == AsTypeMarkupMultiLine || df.AsType == AsTypeMarkupLine
 }
 
+// GoDatatype returns go type holding the value of the field
+func (df *DefineField) GoDatatype() (string, error) {
+	switch {
+	case df.IsMarkup():
+		return df.GoMarkupType(), nil
+	case df.AsType == "" || df.AsType == AsTypeMixed || df.Empty():
+		return "string", nil
+	}
+	goType := goDatatypeMap[df.AsType.Canonical()]
+	if goType == "" {
+		return "", df.Metaschema.errorf(df.Pos, "Unknown as-type='%s' found at <%s> definition", df.AsType, df.Name)
+	}
+	return strings.TrimPrefix(goType, "*"), nil
+}
+
+// HasTypedValue returns true when the value of the field is held in a type
+// of the datatype package
+func (df *DefineField) HasTypedValue() bool {
+	dt, err := df.GoDatatype()
+	return err == nil && strings.HasPrefix(dt, "datatype.")
+}
+
 func (df *DefineField) JsonName() string {
 	if df.JsonValueKey != "" {
 		return df.JsonValueKey
@@ -54,7 +99,7 @@ func (df *DefineField) GoName() string {
 }
 
 func (df *DefineField) JsonAnnotation() string {
-	if df.JsonValueKey != "" {
+	if df.JsonValueKey != "" || df.HasTypedValue() {
 		return df.JsonName()
 	}
 	return df.JsonName() + ",omitempty"
@@ -66,10 +111,15 @@ type Field struct {
 	Description string   `xml:"description"`
 	Remarks     *Remarks `xml:"remarks"`
 	Ref         string   `xml:"ref,attr"`
+	MinOccurs   string   `xml:"min-occurs,attr"`
+	MaxOccurs   string   `xml:"max-occurs,attr"`
 	GroupAs     *GroupAs `xml:"group-as"`
 	InXml       string   `xml:"in-xml,attr"`
 	Def         *DefineField
 	Metaschema  *Metaschema
+	choice      *Choice
+	// Pos is the location of the reference within the metaschema source
+	Pos Position `xml:"-"`
 }
 
 func (f *Field) GoComment() string {
@@ -79,6 +129,22 @@ func (f *Field) GoComment() string {
 	return f.Def.GoComment()
 }
 
+func (f *Field) GoChoiceComment() string {
+	return choiceComment(f.choice)
+}
+
+func (f *Field) GoIsSet(receiver string) string {
+	switch f.GoMemLayout() {
+	case "*":
+		return receiver + "." + f.GoName() + " != nil"
+	case "":
+		if !requiresMultiplexer(f) {
+			return receiver + "." + f.GoName() + ` != ""`
+		}
+	}
+	return "len(" + receiver + "." + f.GoName() + ") > 0"
+}
+
 func (f *Field) requiresPointer() bool {
 	return f.Def.requiresPointer()
 }
@@ -88,12 +154,21 @@ func (f *Field) GoTypeName() string {
 }
 
 func (f *Field) GoTypeNameMultiplexed() string {
+	if f.InXml == "UNWRAPPED" {
+		return "markup.Unwrapped"
+	}
 	if requiresMultiplexer(f) {
 		return (&Multiplexer{MultiplexedModel: f}).GoTypeName()
 	}
 	return f.GoTypeName()
 }
 
+// IsInline returns true when the field carries its own (local) definition
+// instead of referencing a top-level one
+func (f *Field) IsInline() bool {
+	return f.Ref == "" && f.Def != nil
+}
+
 func (f *Field) GoPackageName() string {
 	if f.Ref == "" {
 		return ""
@@ -135,16 +210,19 @@ func (f *Field) XmlName() string {
 	return f.Def.Name
 }
 
+func (f *Field) setChoice(c *Choice) {
+	f.choice = c
+}
+
 func (f *Field) groupAs() *GroupAs {
 	return f.GroupAs
 }
 
+// IndexBy returns go name of the flag keying BY_KEY group of the field,
+// Compile reports definitions of such groups without <json-key>
 func (f *Field) IndexBy() string {
-	if f.Def == nil {
-		panic("Not implemented: IndexBy requires define-field to exist")
-	}
-	if f.Def.JsonKey == nil {
-		panic("Not implemented: IndexBy requires define-field to define <json-key>")
+	if f.Def == nil || f.Def.JsonKey == nil {
+		return ""
 	}
 	return strcase.ToCamel(f.Def.JsonKey.FlagName)
 }
@@ -153,19 +231,22 @@ func (f *Field) XmlAnnotation() string {
 	if f.InXml == "UNWRAPPED" {
 		return ",any"
 	}
-	return f.XmlName() + ",omitempty"
-
+	return xmlNamespacePrefix(f.Metaschema, f.Def.Metaschema) + f.XmlName() + ",omitempty"
 }
 
 func (f *Field) compile(metaschema *Metaschema) error {
+	f.Metaschema = metaschema
 	if f.Ref != "" {
 		var err error
-		f.Metaschema = metaschema
 		f.Def, err = f.Metaschema.GetDefineField(f.Ref)
 		if err != nil {
-			return err
+			return metaschema.errorf(f.Pos, "%v", err)
 		}
 		f.Metaschema.registerDependency(f.Ref, f.Def)
+	} else if f.Def != nil {
+		f.Def.Metaschema = metaschema
+	} else {
+		return metaschema.errorf(f.Pos, "Field element is missing both 'ref' attribute and local definition.")
 	}
 	return nil
 }
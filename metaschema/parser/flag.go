@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"github.com/iancoleman/strcase"
 )
 
@@ -9,11 +8,14 @@ type DefineFlag struct {
 	Name   string `xml:"name,attr"`
 	AsType AsType `xml:"as-type,attr"`
 
-	FormalName  string    `xml:"formal-name"`
-	Description string    `xml:"description"`
-	Remarks     *Remarks  `xml:"remarks"`
-	Examples    []Example `xml:"example"`
+	FormalName  string      `xml:"formal-name"`
+	Description string      `xml:"description"`
+	Remarks     *Remarks    `xml:"remarks"`
+	Constraint  *Constraint `xml:"constraint"`
+	Examples    []Example   `xml:"example"`
 	Metaschema  *Metaschema
+	// Pos is the location of the definition within the metaschema source
+	Pos Position `xml:"-"`
 }
 
 func (df *DefineFlag) GoTypeName() string {
@@ -29,38 +31,53 @@ type Flag struct {
 	AsType   AsType `xml:"as-type,attr"`
 	Required string `xml:"required,attr"`
 
-	Description string   `xml:"description"`
-	Remarks     *Remarks `xml:"remarks"`
-	Values      []Value  `xml:"value"`
-	Ref         string   `xml:"ref,attr"`
+	Description string      `xml:"description"`
+	Remarks     *Remarks    `xml:"remarks"`
+	Values      []Value     `xml:"value"`
+	Constraint  *Constraint `xml:"constraint"`
+	Ref         string      `xml:"ref,attr"`
 	Def         *DefineFlag
 	Metaschema  *Metaschema
+	// Pos is the location of the flag within the metaschema source
+	Pos Position `xml:"-"`
+
+	legacyValuesLinked bool
+	// parentRules are rules of the enclosing definition targeting the flag
+	parentRules []Rule
+	// ownerTypeName is the go type name of the enclosing definition
+	ownerTypeName string
 }
 
+// GoComment returns description of the flag, or of its definition when the
+// flag does not describe itself. Inline flags may have no description.
 func (f *Flag) GoComment() string {
 	if f.Description != "" {
 		return handleMultiline(f.Description)
 	}
+	if f.Def == nil {
+		return ""
+	}
 	return handleMultiline(f.Def.Description)
 }
 
 func (f *Flag) GoDatatype() (string, error) {
-	dt := f.AsType
+	dt, metaschema, pos := f.AsType, f.Metaschema, f.Pos
 	if dt == "" {
 		if f.Ref == "" {
 			// workaround bug: inline definition without type hint https://github.com/usnistgov/OSCAL/pull/570
 			return "string", nil
 		}
-		dt = f.Def.AsType
+		dt, metaschema, pos = f.Def.AsType, f.Def.Metaschema, f.Def.Pos
 	}
 
 	if dt == "" {
 		return "string", nil
 	}
-	if goDatatypeMap[dt] == "" {
-		return "", fmt.Errorf("Unknown as-type='%s' found at <%s> definition", dt, f.Ref)
+	goType := goDatatypeMap[dt.Canonical()]
+	if goType == "" {
+		return "", metaschema.errorf(pos, "Unknown as-type='%s' found at <%s> definition", dt, f.XmlName())
 	}
-	return goDatatypeMap[dt], nil
+	return goType, nil
 }
 
 func (f *Flag) GoTypeName() string {
@@ -70,6 +87,10 @@ func (f *Flag) GoTypeName() string {
 	return f.Def.GoTypeName()
 }
 
+func (f *Flag) GetMetaschema() *Metaschema {
+	return f.Metaschema
+}
+
 func (f *Flag) GoName() string {
 	return strcase.ToCamel(f.JsonName())
 }
@@ -91,9 +112,13 @@ func (f *Flag) JsonAnnotation() string {
 
 func (f *Flag) compile(metaschema *Metaschema) error {
 	var err error
+	f.Metaschema = metaschema
 	if f.Ref != "" {
-		f.Metaschema = metaschema
-		f.Def, err = f.Metaschema.GetDefineFlag(f.Ref)
+		if f.Def, err = f.Metaschema.GetDefineFlag(f.Ref); err != nil {
+			err = metaschema.errorf(f.Pos, "%v", err)
+		}
+	} else if f.Name == "" {
+		err = metaschema.errorf(f.Pos, "Flag element is missing both 'ref' and 'name' attributes.")
 	}
 	return err
 }
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestFlagGoCommentWithoutDescription(t *testing.T) {
+	src := `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>doc</short-name>
+  <define-flag name="id"/>
+  <define-assembly name="doc">
+    <description>A document</description>
+    <flag name="version"/>
+    <flag ref="id"/>
+    <define-flag name="lang"/>
+    <flag name="kind"><description>Kind of
+the document</description></flag>
+  </define-assembly>
+</METASCHEMA>`
+	meta := &Metaschema{URI: "file:///doc.xml"}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"version": "",
+		"id":      "",
+		"lang":    "",
+		"kind":    "Kind of\n // the document",
+	}
+	flags := meta.DefineAssembly[0].Flags
+	if len(flags) != len(want) {
+		t.Fatalf("got %d flags, want %d", len(flags), len(want))
+	}
+	for i := range flags {
+		if got := flags[i].GoComment(); got != want[flags[i].XmlName()] {
+			t.Errorf("flag %s: got comment %q, want %q", flags[i].XmlName(), got, want[flags[i].XmlName()])
+		}
+	}
+}
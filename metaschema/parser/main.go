@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/url"
+	"path"
 	"sort"
 	"strings"
 )
@@ -14,21 +15,38 @@ type GoType interface {
 }
 
 type GoStructItem interface {
+	GoChoiceComment() string
 	GoComment() string
+	GoIsSet(receiver string) string
+	GoCount(receiver string) string
 	GoMemLayout() string
 	GoName() string
 	GoTypeNameMultiplexed() string
+	HasValidate() bool
+	InChoice() bool
+	IsMultiple() bool
 	JsonName() string
+	Max() int
+	Min() int
 	XmlAnnotation() string
+	XmlName() string
 	compile(*Metaschema) error
+	setChoice(*Choice)
 }
 
 // Metaschema is the root metaschema element
 type Metaschema struct {
-	XMLName xml.Name `xml:"http://csrc.nist.gov/ns/oscal/metaschema/1.0 METASCHEMA"`
+	XMLName xml.Name `xml:"METASCHEMA"`
 	Top     string   `xml:"top,attr"`
 	Root    string   `xml:"root,attr"`
 
+	// Namespace is the XML namespace of elements defined by the metaschema
+	Namespace string `xml:"namespace"`
+
+	// JsonBaseUri is the base URI used to identify JSON definitions of the
+	// metaschema
+	JsonBaseUri string `xml:"json-base-uri"`
+
 	// SchemaName describes the scope of application of the data format. For
 	// example "OSCAL Catalog"
 	SchemaName *SchemaName `xml:"schema-name"`
@@ -52,10 +70,14 @@ type Metaschema struct {
 	// DefineFlag is one or more flag definitions
 	DefineFlag []DefineFlag `xml:"define-flag"`
 
-	ImportedMetaschema []Metaschema
+	// ImportedMetaschema are the imported modules, shared by all the
+	// importers
+	ImportedMetaschema []*Metaschema
 	Dependencies       map[string]GoType
 	Multiplexers       []Multiplexer
 	GoMod              string
+	// URI is the canonical location the module was loaded from
+	URI string `xml:"-"`
 }
 
 func (metaschema *Metaschema) ImportedDependencies() []*Metaschema {
@@ -75,34 +97,152 @@ func (metaschema *Metaschema) ImportedDependencies() []*Metaschema {
 	return ret
 }
 
+// XmlNamespace returns the namespace of XML elements defined by the metaschema
+func (metaschema *Metaschema) XmlNamespace() string {
+	return strings.TrimSpace(metaschema.Namespace)
+}
+
+// JsonBaseURI returns the base URI of JSON definitions of the metaschema
+func (metaschema *Metaschema) JsonBaseURI() string {
+	return strings.TrimSpace(metaschema.JsonBaseUri)
+}
+
+// GoPackageName returns name of the go package generated for the metaschema.
+// It is derived from the legacy @root attribute, modules that do not declare
+// it are named by their <short-name> or by the name of their file.
 func (metaschema *Metaschema) GoPackageName() string {
-	return strings.ReplaceAll(strings.ToLower(metaschema.Root), "-", "_")
+	if name := goPackageName(metaschema.Root); name != "" {
+		return name
+	}
+	if metaschema.ShortName != nil {
+		if name := goPackageName(metaschema.ShortName.InnerXML); name != "" {
+			return name
+		}
+	}
+	if u, err := url.Parse(metaschema.URI); err == nil && u.Path != "" {
+		return goPackageName(strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path)))
+	}
+	return ""
+}
+
+// goPackageName lowers the name and replaces characters that are not allowed
+// within go identifiers by underscore
+func goPackageName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(strings.TrimSpace(name)))
+	name = strings.Trim(name, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "m" + name
+	}
+	return name
 }
 
 func (Metaschema *Metaschema) ContainsRootElement() bool {
-	for _, v := range Metaschema.DefineAssembly {
+	return len(Metaschema.RootAssemblies()) > 0
+}
+
+// RootAssemblies returns assembly definitions that may be used as root
+// elements of instance documents
+func (metaschema *Metaschema) RootAssemblies() []*DefineAssembly {
+	var result []*DefineAssembly
+	for _, v := range metaschema.AllDefineAssemblies() {
 		if v.RepresentsRootElement() {
-			return true
+			result = append(result, v)
 		}
 	}
-	return false
+	return result
+}
+
+// AllDefineAssemblies returns top-level assembly definitions followed by all
+// inline (local) assembly definitions declared within their models
+func (metaschema *Metaschema) AllDefineAssemblies() []*DefineAssembly {
+	var result []*DefineAssembly
+	var walk func(da *DefineAssembly)
+	walk = func(da *DefineAssembly) {
+		result = append(result, da)
+		if da.Model == nil {
+			return
+		}
+		for _, item := range da.Model.sortedChilds {
+			if a, ok := item.(*Assembly); ok && a.IsInline() {
+				walk(a.Def)
+			}
+		}
+	}
+	for i := range metaschema.DefineAssembly {
+		walk(&metaschema.DefineAssembly[i])
+	}
+	return result
+}
+
+// AllDefineFields returns top-level field definitions followed by all inline
+// (local) field definitions declared within models of assemblies
+func (metaschema *Metaschema) AllDefineFields() []*DefineField {
+	result := make([]*DefineField, 0, len(metaschema.DefineField))
+	for i := range metaschema.DefineField {
+		result = append(result, &metaschema.DefineField[i])
+	}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		if da.Model == nil {
+			continue
+		}
+		for _, item := range da.Model.sortedChilds {
+			if f, ok := item.(*Field); ok && f.IsInline() {
+				result = append(result, f.Def)
+			}
+		}
+	}
+	return result
 }
 
 type Model struct {
-	Assembly     []Assembly `xml:"assembly"`
-	Field        []Field    `xml:"field"`
-	Choice       []Choice   `xml:"choice"`
-	Prose        *struct{}  `xml:"prose"`
-	sortedChilds []GoStructItem
+	Assembly       []Assembly `xml:"assembly"`
+	Field          []Field    `xml:"field"`
+	DefineAssembly []Assembly `xml:"define-assembly"`
+	DefineField    []Field    `xml:"define-field"`
+	Choice         []Choice   `xml:"choice"`
+	Prose          *struct{}  `xml:"prose"`
+	sortedChilds   []GoStructItem
 }
 
 func (m *Model) GoStructItems() []GoStructItem {
 	return m.sortedChilds
 }
 
+// Choice groups model items of which exactly one may be present in the
+// instance document
 type Choice struct {
-	Field    []Field    `xml:"field"`
-	Assembly []Assembly `xml:"assembly"`
+	Field          []Field    `xml:"field"`
+	Assembly       []Assembly `xml:"assembly"`
+	DefineAssembly []Assembly `xml:"define-assembly"`
+	DefineField    []Field    `xml:"define-field"`
+	sortedChilds   []GoStructItem
+}
+
+func (c *Choice) GoStructItems() []GoStructItem {
+	return c.sortedChilds
+}
+
+// GoNames lists go names of the choice members separated by comma
+func (c *Choice) GoNames() string {
+	names := make([]string, 0, len(c.sortedChilds))
+	for _, item := range c.sortedChilds {
+		names = append(names, item.GoName())
+	}
+	return strings.Join(names, ", ")
+}
+
+// JsonNames lists json names of the choice members separated by comma
+func (c *Choice) JsonNames() string {
+	names := make([]string, 0, len(c.sortedChilds))
+	for _, item := range c.sortedChilds {
+		names = append(names, item.JsonName())
+	}
+	return strings.Join(names, ", ")
 }
 
 type GroupAs struct {
@@ -126,6 +266,8 @@ func (ga *GroupAs) requiresMultiplexer() bool {
 
 type Import struct {
 	Href *Href `xml:"href,attr"`
+	// Pos is the location of the import within the metaschema source
+	Pos Position `xml:"-"`
 }
 
 // Remarks are descriptions for a particular metaschema, assembly, field, flag
@@ -218,10 +360,30 @@ func (h *Href) UnmarshalXMLAttr(attr xml.Attr) error {
 	return nil
 }
 
+// xmlNamespacePrefix returns namespace to be prepended to xml struct tag of an
+// element defined in another metaschema module
+func xmlNamespacePrefix(owner, definer *Metaschema) string {
+	if owner == nil || definer == nil || owner == definer {
+		return ""
+	}
+	ns := definer.XmlNamespace()
+	if ns == "" || ns == owner.XmlNamespace() {
+		return ""
+	}
+	return ns + " "
+}
+
 func handleMultiline(comment string) string {
 	return strings.ReplaceAll(comment, "\n", "\n // ")
 }
 
+func choiceComment(c *Choice) string {
+	if c == nil {
+		return ""
+	}
+	return "Member of choice: exactly one of " + c.GoNames() + " must be set."
+}
+
 type JsonKey struct {
 	FlagName string `xml:"flag-name,attr"`
 }
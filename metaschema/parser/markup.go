@@ -0,0 +1,50 @@
+package parser
+
+// GoMarkupType returns the type of the markup package holding content of
+// markup field
+func (df *DefineField) GoMarkupType() string {
+	if df.AsType == AsTypeMarkupMultiLine {
+		return "markup.Multiline"
+	}
+	return "markup.Line"
+}
+
+// HasMarkupContent returns true for markup fields with flags. Such fields
+// are structs that decode their XML content by themselves.
+func (df *DefineField) HasMarkupContent() bool {
+	return len(df.Flags) > 0 && df.IsMarkup()
+}
+
+// UsesMarkup returns true when generated types refer to the markup package
+func (metaschema *Metaschema) UsesMarkup() bool {
+	for _, df := range metaschema.AllDefineFields() {
+		if df.IsMarkup() {
+			return true
+		}
+	}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		if da.Model == nil {
+			continue
+		}
+		for _, item := range da.Model.GoStructItems() {
+			if f, ok := item.(*Field); ok && f.InXml == "UNWRAPPED" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UsesXml returns true when generated types refer to the encoding/xml
+// package
+func (metaschema *Metaschema) UsesXml() bool {
+	if metaschema.ContainsRootElement() {
+		return true
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		if df.HasMarkupContent() {
+			return true
+		}
+	}
+	return false
+}
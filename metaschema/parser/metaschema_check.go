@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"errors"
+)
+
+// checkDefinitions reports definitions that would not give a usable
+// generated code: unknown data types, invalid group-as and names colliding
+// with each other. Items whose references were not resolved are skipped.
+func (metaschema *Metaschema) checkDefinitions() error {
+	var errs []error
+	if metaschema.GoPackageName() == "" {
+		errs = append(errs, metaschema.errorf(Position{}, "Go package name cannot be derived from the metaschema, declare <short-name>"))
+	}
+	errs = append(errs, metaschema.checkTypes()...)
+	errs = append(errs, metaschema.checkModelItems()...)
+	errs = append(errs, metaschema.checkCollisions()...)
+	errs = append(errs, metaschema.checkLevels()...)
+	return errors.Join(errs...)
+}
+
+func (metaschema *Metaschema) checkTypes() []error {
+	var errs []error
+	checkFlags := func(flags []Flag) {
+		for i := range flags {
+			f := &flags[i]
+			if f.AsType == "" {
+				continue
+			}
+			if _, err := f.GoDatatype(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for i := range metaschema.DefineFlag {
+		df := &metaschema.DefineFlag[i]
+		if df.AsType != "" && goDatatypeMap[df.AsType.Canonical()] == "" {
+			errs = append(errs, metaschema.errorf(df.Pos, "Unknown as-type='%s' found at <%s> definition", df.AsType, df.Name))
+		}
+	}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		checkFlags(da.Flags)
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		checkFlags(df.Flags)
+		if _, err := df.GoDatatype(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// checkModelItems reports model items of unknown XML or JSON representation,
+// including BY_KEY groups that have no key
+func (metaschema *Metaschema) checkModelItems() []error {
+	var errs []error
+	for _, da := range metaschema.AllDefineAssemblies() {
+		if da.Model == nil {
+			continue
+		}
+		for _, item := range da.Model.sortedChilds {
+			mm, ok := item.(MultiplexedModel)
+			if !ok || !isLinked(item) {
+				continue
+			}
+			pos := positionOfItem(item)
+			if f, ok := item.(*Field); ok {
+				switch f.InXml {
+				case "", "WRAPPED", "UNWRAPPED", "WITH_WRAPPER":
+				default:
+					errs = append(errs, metaschema.errorf(pos, "Unknown in-xml='%s' of <%s>", f.InXml, item.XmlName()))
+				}
+			}
+			groupAs := mm.groupAs()
+			if groupAs == nil {
+				if item.Max() != 1 {
+					errs = append(errs, metaschema.errorf(pos, "<%s> with max-occurs greater than 1 must declare <group-as> within <%s> definition", item.XmlName(), da.Name))
+				}
+				continue
+			}
+			if groupAs.Name == "" {
+				errs = append(errs, metaschema.errorf(pos, "<group-as> of <%s> is missing 'name' attribute", item.XmlName()))
+			}
+			switch groupAs.InJson {
+			case "", "ARRAY", "SINGLETON_OR_ARRAY":
+			case "BY_KEY":
+				if err := metaschema.checkJsonKey(item, pos); err != nil {
+					errs = append(errs, err)
+				}
+			default:
+				errs = append(errs, metaschema.errorf(pos, "Unknown group-as/@in-json='%s' of <%s>", groupAs.InJson, item.XmlName()))
+			}
+			switch groupAs.InXml {
+			case "", "GROUPED", "UNGROUPED":
+			default:
+				errs = append(errs, metaschema.errorf(pos, "Unknown group-as/@in-xml='%s' of <%s>", groupAs.InXml, item.XmlName()))
+			}
+		}
+	}
+	return errs
+}
+
+// checkJsonKey reports BY_KEY group of definitions without <json-key> naming
+// one of their flags, the key of the JSON object would have no source
+func (metaschema *Metaschema) checkJsonKey(item GoStructItem, pos Position) error {
+	var key *JsonKey
+	var flags []Flag
+	switch v := item.(type) {
+	case *Assembly:
+		key, flags = v.Def.JsonKey, v.Def.Flags
+	case *Field:
+		key, flags = v.Def.JsonKey, v.Def.Flags
+	}
+	if key == nil {
+		return metaschema.errorf(pos, "group-as/@in-json='BY_KEY' of <%s> requires its definition to declare <json-key>", item.XmlName())
+	}
+	for i := range flags {
+		if (flags[i].Name != "" || flags[i].Def != nil) && flags[i].XmlName() == key.FlagName {
+			return nil
+		}
+	}
+	return metaschema.errorf(pos, "<json-key> of <%s> refers to flag '%s' the definition does not declare", item.XmlName(), key.FlagName)
+}
+
+// checkCollisions reports definitions of the same kind declared twice,
+// generated types of the same name and properties of the same name within
+// a generated type
+func (metaschema *Metaschema) checkCollisions() []error {
+	var errs []error
+	duplicate := func(seen map[string]bool, name string) bool {
+		if seen[name] {
+			return true
+		}
+		seen[name] = true
+		return false
+	}
+
+	seen := map[string]bool{}
+	for i := range metaschema.DefineFlag {
+		if df := &metaschema.DefineFlag[i]; duplicate(seen, df.Name) {
+			errs = append(errs, metaschema.errorf(df.Pos, "Duplicate define-flag element with name='%s'.", df.Name))
+		}
+	}
+	seen = map[string]bool{}
+	for i := range metaschema.DefineField {
+		if df := &metaschema.DefineField[i]; duplicate(seen, df.Name) {
+			errs = append(errs, metaschema.errorf(df.Pos, "Duplicate define-field element with name='%s'.", df.Name))
+		}
+	}
+	seen = map[string]bool{}
+	for i := range metaschema.DefineAssembly {
+		if da := &metaschema.DefineAssembly[i]; duplicate(seen, da.Name) {
+			errs = append(errs, metaschema.errorf(da.Pos, "Duplicate define-assembly element with name='%s'.", da.Name))
+		}
+	}
+
+	types := map[string]bool{}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		if duplicate(types, da.GoTypeName()) {
+			errs = append(errs, metaschema.errorf(da.Pos, "Definition <%s> collides with another definition generating go type %s", da.Name, da.GoTypeName()))
+		}
+		props := map[string]bool{}
+		errs = append(errs, metaschema.checkFlagCollisions(da.Flags, props)...)
+		if da.Model == nil {
+			continue
+		}
+		for _, item := range da.Model.sortedChilds {
+			if !isLinked(item) {
+				continue
+			}
+			if duplicate(props, item.GoName()) {
+				errs = append(errs, metaschema.errorf(positionOfItem(item), "Property '%s' of <%s> definition is declared twice", item.JsonName(), da.Name))
+			}
+		}
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		if duplicate(types, df.GoTypeName()) {
+			errs = append(errs, metaschema.errorf(df.Pos, "Definition <%s> collides with another definition generating go type %s", df.Name, df.GoTypeName()))
+		}
+		errs = append(errs, metaschema.checkFlagCollisions(df.Flags, map[string]bool{})...)
+	}
+	for _, e := range metaschema.GoEnums() {
+		if !e.FieldType && duplicate(types, e.GoTypeName) {
+			errs = append(errs, metaschema.errorf(Position{}, "Enum type %s of allowed-values collides with another generated go type", e.GoTypeName))
+		}
+	}
+	return errs
+}
+
+func (metaschema *Metaschema) checkFlagCollisions(flags []Flag, props map[string]bool) []error {
+	var errs []error
+	for i := range flags {
+		f := &flags[i]
+		if f.Name == "" && f.Def == nil {
+			continue
+		}
+		if props[f.GoName()] {
+			errs = append(errs, metaschema.errorf(f.Pos, "Flag '%s' is declared twice", f.JsonName()))
+		}
+		props[f.GoName()] = true
+	}
+	return errs
+}
+
+func (metaschema *Metaschema) checkLevels() []error {
+	var errs []error
+	for _, rule := range metaschema.allRules() {
+		switch base := rule.Base(); base.Level {
+		case "", ConstraintLevelCritical, ConstraintLevelError, ConstraintLevelWarning, ConstraintLevelInformational, ConstraintLevelDebug:
+		default:
+			errs = append(errs, metaschema.errorf(base.Pos, "Unknown level='%s' of <%s> constraint", base.Level, rule.Kind()))
+		}
+	}
+	return errs
+}
+
+// isLinked returns true when the definition of the model item is known
+func isLinked(item GoStructItem) bool {
+	switch v := item.(type) {
+	case *Assembly:
+		return v.Def != nil
+	case *Field:
+		return v.Def != nil
+	}
+	return false
+}
+
+func positionOfItem(item GoStructItem) Position {
+	switch v := item.(type) {
+	case *Assembly:
+		return v.Pos
+	case *Field:
+		return v.Pos
+	}
+	return Position{}
+}
@@ -1,11 +1,19 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 )
 
+// Compile links the definitions and their constraints and checks them.
+// Errors of all the passes are returned joined, so that they can be fixed at
+// once.
 func (metaschema *Metaschema) Compile() error {
-	err := metaschema.linkDefinitions()
+	err := errors.Join(
+		metaschema.linkDefinitions(),
+		metaschema.linkConstraints(),
+		metaschema.checkDefinitions(),
+	)
 	if err != nil {
 		return err
 	}
@@ -24,80 +32,70 @@ func (metaschema *Metaschema) registerDependency(name string, dependency GoType)
 	}
 }
 
-func (metaschema *Metaschema) linkItems(list []GoStructItem) error {
+func (metaschema *Metaschema) linkItems(list []GoStructItem) []error {
+	var errs []error
 	for i := range list {
-		err := list[i].compile(metaschema)
-		if err != nil {
-			return err
+		if err := list[i].compile(metaschema); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return nil
-}
-func (metaschema *Metaschema) linkAssemblies(list []Assembly) error {
-	for i := range list {
-		a := &list[i]
-		err := a.compile(metaschema)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return errs
 }
 
-func (metaschema *Metaschema) linkFields(list []Field) error {
+func (metaschema *Metaschema) linkFlags(list []Flag) []error {
+	var errs []error
 	for i := range list {
-		f := &list[i]
-		err := f.compile(metaschema)
-		if err != nil {
-			return err
+		if err := list[i].compile(metaschema); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return nil
+	return errs
 }
 
-func (metaschema *Metaschema) linkFlags(list []Flag) error {
-	for i := range list {
-		f := &list[i]
-		err := f.compile(metaschema)
-		if err != nil {
-			return err
-		}
+func (metaschema *Metaschema) linkDefinitions() error {
+	var errs []error
+	for _, da := range metaschema.AllDefineAssemblies() {
+		da.Metaschema = metaschema
+		errs = append(errs, metaschema.linkFlags(da.Flags)...)
+		if da.Model == nil {
+			continue
+		}
+		linkInlineDefinitions(da)
+		errs = append(errs, metaschema.linkItems(da.Model.sortedChilds)...)
 	}
-	return nil
-}
 
-func (metaschema *Metaschema) linkDefinitions() error {
-	var err error
-	for _, da := range metaschema.DefineAssembly {
-		if err = metaschema.linkFlags(da.Flags); err != nil {
-			return err
-		}
-		if err = metaschema.linkItems(da.Model.sortedChilds); err != nil {
-			return err
-		}
-		if err = metaschema.linkAssemblies(da.Model.Assembly); err != nil {
-			return err
-		}
-		if err = metaschema.linkFields(da.Model.Field); err != nil {
-			return err
-		}
+	for _, df := range metaschema.AllDefineFields() {
+		df.Metaschema = metaschema
+		errs = append(errs, metaschema.linkFlags(df.Flags)...)
 	}
+	return errors.Join(errs...)
+}
 
-	for _, df := range metaschema.DefineField {
-		if err = metaschema.linkFlags(df.Flags); err != nil {
-			return err
+// linkInlineDefinitions points definitions declared within the model of the
+// assembly to it. It is done once decoding is over, as the definitions of
+// the module are moved while their slices grow.
+func linkInlineDefinitions(da *DefineAssembly) {
+	for _, item := range da.Model.sortedChilds {
+		switch v := item.(type) {
+		case *Assembly:
+			if v.IsInline() {
+				v.Def.parent = da
+			}
+		case *Field:
+			if v.IsInline() {
+				v.Def.parent = da
+			}
 		}
 	}
-	return nil
 }
 
 func (metaschema *Metaschema) GetDefineField(name string) (*DefineField, error) {
-	for _, v := range metaschema.DefineField {
-		if name == v.Name {
+	for i := range metaschema.DefineField {
+		if v := &metaschema.DefineField[i]; name == v.Name {
 			if v.Metaschema == nil {
 				v.Metaschema = metaschema
 			}
-			return &v, nil
+			return v, nil
 		}
 	}
 	for _, m := range metaschema.ImportedMetaschema {
@@ -110,12 +108,12 @@ func (metaschema *Metaschema) GetDefineField(name string) (*DefineField, error)
 }
 
 func (metaschema *Metaschema) GetDefineAssembly(name string) (*DefineAssembly, error) {
-	for _, v := range metaschema.DefineAssembly {
-		if name == v.Name {
+	for i := range metaschema.DefineAssembly {
+		if v := &metaschema.DefineAssembly[i]; name == v.Name {
 			if v.Metaschema == nil {
 				v.Metaschema = metaschema
 			}
-			return &v, nil
+			return v, nil
 		}
 	}
 	for _, m := range metaschema.ImportedMetaschema {
@@ -128,12 +126,12 @@ func (metaschema *Metaschema) GetDefineAssembly(name string) (*DefineAssembly, e
 }
 
 func (metaschema *Metaschema) GetDefineFlag(name string) (*DefineFlag, error) {
-	for _, v := range metaschema.DefineFlag {
-		if name == v.Name {
+	for i := range metaschema.DefineFlag {
+		if v := &metaschema.DefineFlag[i]; name == v.Name {
 			if v.Metaschema == nil {
 				v.Metaschema = metaschema
 			}
-			return &v, nil
+			return v, nil
 		}
 	}
 	for _, m := range metaschema.ImportedMetaschema {
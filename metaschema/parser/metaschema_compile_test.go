@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestCompileReportsAllPasses(t *testing.T) {
+	src := `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>broken</short-name>
+  <define-assembly name="catalog">
+    <description>A catalog</description>
+    <root-name>catalog</root-name>
+    <flag ref="missing-flag"/>
+    <constraint>
+      <matches target="@missing-flag" regex="[a-z]+"/>
+      <index-has-key name="no-such-index" target="item"><key-field target="@id"/></index-has-key>
+    </constraint>
+    <model>
+      <field ref="missing-field"/>
+    </model>
+  </define-assembly>
+  <define-field name="note" as-type="no-such-type">
+    <description>A note</description>
+  </define-field>
+</METASCHEMA>`
+	meta := &Metaschema{URI: "file:///broken.xml"}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	err := meta.Compile()
+	if err == nil {
+		t.Fatal("broken metaschema compiled")
+	}
+	var messages []string
+	for _, d := range Diagnostics(err) {
+		messages = append(messages, d.Message)
+	}
+	for _, want := range []string{
+		"missing-flag",
+		"missing-field",
+		"as-type='no-such-type'",
+		"index with name='no-such-index'",
+	} {
+		found := false
+		for _, m := range messages {
+			found = found || strings.Contains(m, want)
+		}
+		if !found {
+			t.Errorf("no diagnostic mentions %s, got:\n%s", want, strings.Join(messages, "\n"))
+		}
+	}
+}
+
+func TestCompileReportsEnumCollision(t *testing.T) {
+	src := `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>collision</short-name>
+  <define-flag name="status">
+    <description>A status</description>
+    <constraint><allowed-values><enum value="open">Open</enum></allowed-values></constraint>
+  </define-flag>
+  <define-field name="status">
+    <description>A status field</description>
+  </define-field>
+</METASCHEMA>`
+	meta := &Metaschema{URI: "file:///collision.xml"}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	err := meta.Compile()
+	if err == nil || !strings.Contains(err.Error(), "Enum type Status of allowed-values collides") {
+		t.Errorf("got error %v, want collision of enum type Status", err)
+	}
+}
@@ -0,0 +1,264 @@
+package parser
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// itemDecoder decodes model items of a single model or choice element into
+// the slices of the element and records the document order of the items.
+// Each element gets its own decoder, so no parse state is shared between
+// decodes.
+type itemDecoder struct {
+	assemblies       *[]Assembly
+	fields           *[]Field
+	defineAssemblies *[]Assembly
+	defineFields     *[]Field
+	// order lists local names of the child elements in document order
+	order []string
+}
+
+// decode decodes the content of the element, child elements other than
+// model items are passed to other
+func (id *itemDecoder) decode(d *xml.Decoder, other func(xml.StartElement) error) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			id.order = append(id.order, t.Name.Local)
+			switch t.Name.Local {
+			case "assembly":
+				err = decodeAssembly(d, t, id.assemblies)
+			case "field":
+				err = decodeField(d, t, id.fields)
+			case "define-assembly":
+				err = decodeAssembly(d, t, id.defineAssemblies)
+			case "define-field":
+				err = decodeField(d, t, id.defineFields)
+			default:
+				err = other(t)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// items returns the decoded model items in document order, choice(i) gives
+// members of the i-th choice element
+func (id *itemDecoder) items(choice func(i int) []GoStructItem) []GoStructItem {
+	var result []GoStructItem
+	next := map[string]int{}
+	for _, name := range id.order {
+		i := next[name]
+		next[name]++
+		switch name {
+		case "assembly":
+			result = append(result, &(*id.assemblies)[i])
+		case "field":
+			result = append(result, &(*id.fields)[i])
+		case "define-assembly":
+			result = append(result, &(*id.defineAssemblies)[i])
+		case "define-field":
+			result = append(result, &(*id.defineFields)[i])
+		case "choice":
+			if choice != nil {
+				result = append(result, choice(i)...)
+			}
+		}
+	}
+	return result
+}
+
+func decodeAssembly(d *xml.Decoder, start xml.StartElement, list *[]Assembly) error {
+	*list = append(*list, Assembly{})
+	return d.DecodeElement(&(*list)[len(*list)-1], &start)
+}
+
+func decodeField(d *xml.Decoder, start xml.StartElement, list *[]Field) error {
+	*list = append(*list, Field{})
+	return d.DecodeElement(&(*list)[len(*list)-1], &start)
+}
+
+func (m *Model) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	id := itemDecoder{
+		assemblies:       &m.Assembly,
+		fields:           &m.Field,
+		defineAssemblies: &m.DefineAssembly,
+		defineFields:     &m.DefineField,
+	}
+	err := id.decode(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "choice":
+			m.Choice = append(m.Choice, Choice{})
+			return d.DecodeElement(&m.Choice[len(m.Choice)-1], &child)
+		case "prose":
+			m.Prose = &struct{}{}
+		}
+		return d.Skip()
+	})
+	if err != nil {
+		return err
+	}
+	// choice members keep their position among other model items
+	m.sortedChilds = id.items(func(i int) []GoStructItem {
+		return m.Choice[i].sortedChilds
+	})
+	for i := range m.Choice {
+		for _, child := range m.Choice[i].sortedChilds {
+			child.setChoice(&m.Choice[i])
+		}
+	}
+	return nil
+}
+
+func (c *Choice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	id := itemDecoder{
+		assemblies:       &c.Assembly,
+		fields:           &c.Field,
+		defineAssemblies: &c.DefineAssembly,
+		defineFields:     &c.DefineField,
+	}
+	err := id.decode(d, func(xml.StartElement) error {
+		return d.Skip()
+	})
+	if err != nil {
+		return err
+	}
+	c.sortedChilds = id.items(nil)
+	return nil
+}
+
+func (a *Assembly) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type assembly Assembly
+	a.Pos = positionOf(d)
+	var err error
+	if start.Name.Local == "define-assembly" {
+		a.Def = &DefineAssembly{}
+		err = d.DecodeElement(a.Def, &start)
+		a.GroupAs = a.Def.GroupAs
+		a.MinOccurs, a.MaxOccurs = a.Def.MinOccurs, a.Def.MaxOccurs
+	} else {
+		err = d.DecodeElement((*assembly)(a), &start)
+	}
+	return err
+}
+
+func (f *Field) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type field Field
+	f.Pos = positionOf(d)
+	var err error
+	if start.Name.Local == "define-field" {
+		f.Def = &DefineField{}
+		err = d.DecodeElement(f.Def, &start)
+		f.GroupAs = f.Def.GroupAs
+		f.InXml = f.Def.InXml
+		f.MinOccurs, f.MaxOccurs = f.Def.MinOccurs, f.Def.MaxOccurs
+	} else {
+		err = d.DecodeElement((*field)(f), &start)
+	}
+	return err
+}
+
+func (da *DefineAssembly) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type defineAssembly DefineAssembly
+	da.Pos = positionOf(d)
+	if err := d.DecodeElement((*defineAssembly)(da), &start); err != nil {
+		return err
+	}
+	da.Flags = inDocumentOrder(da.Flags, da.DefineFlag)
+	return nil
+}
+
+func (df *DefineField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type defineField DefineField
+	df.Pos = positionOf(d)
+	if err := d.DecodeElement((*defineField)(df), &start); err != nil {
+		return err
+	}
+	df.Flags = inDocumentOrder(df.Flags, df.DefineFlag)
+	return nil
+}
+
+// inDocumentOrder merges flag references with inline flag definitions in the
+// order they are declared, which is the order of generated struct members,
+// schema properties and attributes
+func inDocumentOrder(flags, defined []Flag) []Flag {
+	result := append(flags, defined...)
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, pj := result[i].Pos, result[j].Pos
+		return pi.Line < pj.Line || (pi.Line == pj.Line && pi.Column < pj.Column)
+	})
+	return result
+}
+
+func (df *DefineFlag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type defineFlag DefineFlag
+	df.Pos = positionOf(d)
+	return d.DecodeElement((*defineFlag)(df), &start)
+}
+
+func (f *Flag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type flag Flag
+	f.Pos = positionOf(d)
+	return d.DecodeElement((*flag)(f), &start)
+}
+
+func (i *Import) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type imp Import
+	i.Pos = positionOf(d)
+	return d.DecodeElement((*imp)(i), &start)
+}
+
+// UnmarshalXML decodes the rules of the constraint recording position of
+// each of them
+func (c *Constraint) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "allowed-values":
+				err = decodeRule(d, t, &c.AllowedValues)
+			case "matches":
+				err = decodeRule(d, t, &c.Matches)
+			case "index":
+				err = decodeRule(d, t, &c.Index)
+			case "index-has-key":
+				err = decodeRule(d, t, &c.IndexHasKey)
+			case "is-unique":
+				err = decodeRule(d, t, &c.IsUnique)
+			case "has-cardinality":
+				err = decodeRule(d, t, &c.HasCardinality)
+			case "expect":
+				err = decodeRule(d, t, &c.Expect)
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func decodeRule[T any, P interface {
+	*T
+	Rule
+}](d *xml.Decoder, start xml.StartElement, list *[]T) error {
+	*list = append(*list, *new(T))
+	rule := P(&(*list)[len(*list)-1])
+	rule.Base().Pos = positionOf(d)
+	return d.DecodeElement(rule, &start)
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInlineDefinitionParent(t *testing.T) {
+	var src strings.Builder
+	src.WriteString(`<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0"><short-name>inline</short-name>`)
+	// enough definitions for the slice of them to be reallocated while
+	// decoding
+	for i := 0; i < 9; i++ {
+		fmt.Fprintf(&src, `<define-assembly name="outer-%d"><description>d</description><model>
+			<define-assembly name="inner"><description>d</description><model>
+				<define-field name="leaf"><description>d</description></define-field>
+			</model></define-assembly>
+			<define-field name="note"><description>d</description></define-field>
+		</model></define-assembly>`, i)
+	}
+	src.WriteString(`</METASCHEMA>`)
+
+	meta := &Metaschema{URI: "file:///inline.xml"}
+	if err := xml.Unmarshal([]byte(src.String()), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range meta.DefineAssembly {
+		outer := &meta.DefineAssembly[i]
+		items := outer.Model.sortedChilds
+		inner := items[0].(*Assembly).Def
+		note := items[1].(*Field).Def
+		if inner.parent != outer || note.parent != outer {
+			t.Errorf("inline definitions of %s do not point to it", outer.Name)
+		}
+		leaf := inner.Model.sortedChilds[0].(*Field).Def
+		if leaf.parent != inner {
+			t.Errorf("inline definition of %s/inner does not point to it", outer.Name)
+		}
+		prefix := fmt.Sprintf("Outer%d", i)
+		for _, name := range []string{inner.GoTypeName(), note.GoTypeName(), leaf.GoTypeName()} {
+			if !strings.HasPrefix(name, prefix) {
+				t.Errorf("go type %s of inline definition within %s", name, outer.Name)
+			}
+		}
+	}
+}
+
+func TestFlagsInDocumentOrder(t *testing.T) {
+	src := `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>order</short-name>
+  <define-flag name="id"><description>d</description></define-flag>
+  <define-flag name="class"><description>d</description></define-flag>
+  <define-assembly name="item">
+    <description>d</description>
+    <flag ref="id"/>
+    <define-flag name="first"><description>d</description></define-flag>
+    <flag ref="class"/><define-flag name="second"><description>d</description></define-flag>
+    <flag name="last"><description>d</description></flag>
+  </define-assembly>
+  <define-field name="note">
+    <description>d</description>
+    <define-flag name="first"><description>d</description></define-flag>
+    <flag ref="id"/>
+  </define-field>
+</METASCHEMA>`
+	meta := &Metaschema{URI: "file:///order.xml"}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	names := func(flags []Flag) string {
+		var result []string
+		for i := range flags {
+			result = append(result, flags[i].XmlName())
+		}
+		return strings.Join(result, ",")
+	}
+	if got, want := names(meta.DefineAssembly[0].Flags), "id,first,class,second,last"; got != want {
+		t.Errorf("got flags %s of assembly, want %s", got, want)
+	}
+	if got, want := names(meta.DefineField[0].Flags), "first,id"; got != want {
+		t.Errorf("got flags %s of field, want %s", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"sort"
+	"strings"
 )
 
 // Multiplexer represents model to be generated in go code that does not exists in the metaschema.
@@ -28,6 +29,97 @@ func (mplex *Multiplexer) JsonKey() string {
 	return mplex.MultiplexedModel.IndexBy()
 }
 
+// JsonValue returns go name of the member holding value of the field whose
+// occurrences are written as bare values within BY_KEY group, that is
+// fields that have no flag other than the key. It is empty for other items.
+func (mplex *Multiplexer) JsonValue() string {
+	f, ok := mplex.MultiplexedModel.(*Field)
+	if !ok || !mplex.InJsonMap() || f.Def == nil || f.Def.Empty() || f.Def.JsonKey == nil {
+		return ""
+	}
+	for i := range f.Def.Flags {
+		if f.Def.Flags[i].XmlName() != f.Def.JsonKey.FlagName {
+			return ""
+		}
+	}
+	return f.Def.GoName()
+}
+
+// jsonKeyFlag returns the flag whose value is the key of the item within
+// BY_KEY group, nil for other groups
+func (mplex *Multiplexer) jsonKeyFlag() *Flag {
+	var key *JsonKey
+	var flags []Flag
+	switch v := mplex.MultiplexedModel.(type) {
+	case *Assembly:
+		if v.Def != nil {
+			key, flags = v.Def.JsonKey, v.Def.Flags
+		}
+	case *Field:
+		if v.Def != nil {
+			key, flags = v.Def.JsonKey, v.Def.Flags
+		}
+	}
+	if key == nil || !mplex.InJsonMap() {
+		return nil
+	}
+	for i := range flags {
+		if (flags[i].Name != "" || flags[i].Def != nil) && flags[i].XmlName() == key.FlagName {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// JsonKeyType returns go type of the member holding key of the item within
+// BY_KEY group
+func (mplex *Multiplexer) JsonKeyType() string {
+	f := mplex.jsonKeyFlag()
+	if f == nil {
+		return "string"
+	}
+	goType, err := f.GoValueType()
+	if err != nil {
+		return "string"
+	}
+	return goType
+}
+
+// JsonKeyText returns true when the key of the item within BY_KEY group is
+// converted by its text marshalling, so that the values rejected by the key
+// type are rejected as keys as well
+func (mplex *Multiplexer) JsonKeyText() bool {
+	f := mplex.jsonKeyFlag()
+	if f == nil {
+		return false
+	}
+	if e := f.GoEnum(); e != nil {
+		return e.Closed()
+	}
+	return mplex.JsonKeyDatatype() != ""
+}
+
+// JsonKeyDatatype returns the datatype wrapper the key member points to,
+// empty string when the key is not parsed by the datatype package
+func (mplex *Multiplexer) JsonKeyDatatype() string {
+	goType := mplex.JsonKeyType()
+	if !strings.HasPrefix(goType, "*datatype.") {
+		return ""
+	}
+	return strings.TrimPrefix(goType, "*")
+}
+
+// MultiplexersUseDatatype returns true when the multiplexers parse keys by
+// the datatype package
+func (metaschema *Metaschema) MultiplexersUseDatatype() bool {
+	for i := range metaschema.Multiplexers {
+		if metaschema.Multiplexers[i].JsonKeyDatatype() != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (mplex *Multiplexer) GoTypeNameOriginal() string {
 	return mplex.MultiplexedModel.GoTypeName()
 }
@@ -49,35 +141,25 @@ func (metaschema *Metaschema) getMultiplexer(name string) *Multiplexer {
 
 func (metaschema *Metaschema) calculateMultiplexers() []Multiplexer {
 	uniq := map[string]Multiplexer{}
-	for _, da := range metaschema.DefineAssembly {
-		for i := range da.Model.Assembly {
-			if requiresMultiplexer(&da.Model.Assembly[i]) {
-				mplex := Multiplexer{
-					MultiplexedModel: &da.Model.Assembly[i],
-					Metaschema:       metaschema,
-				}
-				existing := metaschema.getMultiplexer(mplex.GoTypeName())
-				if existing != nil {
-					metaschema.registerDependency(mplex.GoTypeName(), existing)
-				} else {
-					uniq[mplex.GoTypeName()] = mplex
-				}
-			}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		if da.Model == nil {
+			continue
 		}
-		for i := range da.Model.Field {
-			if requiresMultiplexer(&da.Model.Field[i]) {
-				mplex := Multiplexer{
-					MultiplexedModel: &da.Model.Field[i],
-					Metaschema:       metaschema,
-				}
-				existing := metaschema.getMultiplexer(mplex.GoTypeName())
-				if existing != nil {
-					metaschema.registerDependency(mplex.GoTypeName(), existing)
-				} else {
-					uniq[mplex.GoTypeName()] = mplex
-				}
+		for _, item := range da.Model.GoStructItems() {
+			mm, ok := item.(MultiplexedModel)
+			if !ok || !requiresMultiplexer(mm) {
+				continue
+			}
+			mplex := Multiplexer{
+				MultiplexedModel: mm,
+				Metaschema:       metaschema,
+			}
+			existing := metaschema.getMultiplexer(mplex.GoTypeName())
+			if existing != nil {
+				metaschema.registerDependency(mplex.GoTypeName(), existing)
+			} else {
+				uniq[mplex.GoTypeName()] = mplex
 			}
-
 		}
 	}
 
@@ -0,0 +1,304 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// occurrences computes the cardinality of a model item, max is -1 when
+// unbounded
+func occurrences(minOccurs, maxOccurs, required string, groupAs *GroupAs) (int, int) {
+	min, err := strconv.Atoi(minOccurs)
+	if err != nil {
+		min = 0
+		if required == "yes" {
+			min = 1
+		}
+	}
+	if maxOccurs == "unbounded" {
+		return min, -1
+	}
+	max, err := strconv.Atoi(maxOccurs)
+	if err != nil {
+		// legacy metaschemas declare group-as without max-occurs
+		if groupAs != nil {
+			return min, -1
+		}
+		return min, 1
+	}
+	return min, max
+}
+
+// Min returns minimal number of occurrences of the assembly
+func (a *Assembly) Min() int {
+	min, _ := occurrences(a.MinOccurs, a.MaxOccurs, "", a.GroupAs)
+	return min
+}
+
+// Max returns maximal number of occurrences of the assembly, -1 if unbounded
+func (a *Assembly) Max() int {
+	_, max := occurrences(a.MinOccurs, a.MaxOccurs, "", a.GroupAs)
+	return max
+}
+
+func (a *Assembly) InChoice() bool {
+	return a.choice != nil
+}
+
+func (a *Assembly) IsMultiple() bool {
+	return a.GoMemLayout() != "*"
+}
+
+// GoCount returns go expression counting occurrences of the assembly
+func (a *Assembly) GoCount(receiver string) string {
+	if a.IsMultiple() {
+		return "len(" + receiver + "." + a.GoName() + ")"
+	}
+	return "validation.Count(" + a.GoIsSet(receiver) + ")"
+}
+
+// HasValidate returns true when go type of the item implements ValidatePath
+func (a *Assembly) HasValidate() bool {
+	return true
+}
+
+// Min returns minimal number of occurrences of the field
+func (f *Field) Min() int {
+	min, _ := occurrences(f.MinOccurs, f.MaxOccurs, f.Required, f.GroupAs)
+	return min
+}
+
+// Max returns maximal number of occurrences of the field, -1 if unbounded
+func (f *Field) Max() int {
+	_, max := occurrences(f.MinOccurs, f.MaxOccurs, f.Required, f.GroupAs)
+	return max
+}
+
+func (f *Field) InChoice() bool {
+	return f.choice != nil
+}
+
+func (f *Field) IsMultiple() bool {
+	return f.GroupAs != nil
+}
+
+// GoCount returns go expression counting occurrences of the field
+func (f *Field) GoCount(receiver string) string {
+	if f.IsMultiple() {
+		return "len(" + receiver + "." + f.GoName() + ")"
+	}
+	return "validation.Count(" + f.GoIsSet(receiver) + ")"
+}
+
+// HasValidate returns true when go type of the item implements ValidatePath
+func (f *Field) HasValidate() bool {
+	return f.Def.HasValidate()
+}
+
+// HasValidate returns true when ValidatePath method is generated for the
+// field. Markup and typed fields without flags are aliases of markup and
+// datatype package types.
+func (df *DefineField) HasValidate() bool {
+	return len(df.Flags) > 0 || !df.IsMarkup() && !df.HasTypedValue()
+}
+
+// HasStringValue returns true when the value of the field can be checked as
+// string, that is it is neither empty nor markup
+func (df *DefineField) HasStringValue() bool {
+	return !df.Empty() && !df.IsMarkup()
+}
+
+// CheckedAsType returns the data type whose lexical rules are checked by
+// Validate method, see Flag.CheckedAsType
+func (df *DefineField) CheckedAsType() string {
+	if !df.HasStringValue() || df.HasTypedValue() || df.AsType == AsTypeString || df.AsType == AsTypeMixed {
+		return ""
+	}
+	return string(df.AsType)
+}
+
+// ClosedAllowedValues returns allowed-values rules targeting value of the field
+// that do not allow other values
+func (df *DefineField) ClosedAllowedValues() []*AllowedValues {
+	return closedAllowedValues(selfRules(df.Constraint.Rules()))
+}
+
+// Patterns returns regular expressions the value of the field has to match
+func (df *DefineField) Patterns() []string {
+	return patterns(selfRules(df.Constraint.Rules()))
+}
+
+// IsRequired returns true when the flag has to be present
+func (f *Flag) IsRequired() bool {
+	return f.Required == "yes"
+}
+
+// HasStringValue returns true when the value of the flag is held in go string
+func (f *Flag) HasStringValue() bool {
+	dt, err := f.GoDatatype()
+	return err == nil && dt == "string"
+}
+
+// GoIsSet returns go expression testing presence of the flag, or empty
+// string when the presence cannot be told from the go value
+func (f *Flag) GoIsSet(receiver string) string {
+	dt, err := f.GoDatatype()
+	switch {
+	case err != nil:
+		return ""
+	case dt == "string":
+		return receiver + "." + f.GoName() + ` != ""`
+	case strings.HasPrefix(dt, "*"):
+		return receiver + "." + f.GoName() + " != nil"
+	}
+	return ""
+}
+
+// GoValue returns go expression holding the lexical value of the flag
+func (f *Flag) GoValue(receiver string) string {
+	if f.GoEnum() != nil {
+		return "string(" + receiver + "." + f.GoName() + ")"
+	}
+	if f.HasStringValue() {
+		return receiver + "." + f.GoName()
+	}
+	return receiver + "." + f.GoName() + ".String()"
+}
+
+// CheckedAsType returns the data type whose lexical rules are checked by
+// Validate method, that is a type held in go string other than string
+// itself. Values of other types are checked when decoded.
+func (f *Flag) CheckedAsType() string {
+	if !f.HasStringValue() {
+		return ""
+	}
+	dt := f.AsType
+	if dt == "" && f.Def != nil {
+		dt = f.Def.AsType
+	}
+	if dt == "" || dt == AsTypeString {
+		return ""
+	}
+	return string(dt)
+}
+
+// ClosedAllowedValues returns allowed-values rules applicable to the flag that
+// do not allow other values
+func (f *Flag) ClosedAllowedValues() []*AllowedValues {
+	return closedAllowedValues(f.valueRules())
+}
+
+// Patterns returns regular expressions the value of the flag has to match
+func (f *Flag) Patterns() []string {
+	return patterns(f.valueRules())
+}
+
+// valueRules returns rules of the flag, of its definition and of the parent
+// definition targeting the flag
+func (f *Flag) valueRules() []Rule {
+	return append(selfRules(f.Rules()), f.parentRules...)
+}
+
+func selfRules(rules []Rule) []Rule {
+	var result []Rule
+	for _, r := range rules {
+		if r.Base().TargetsSelf() {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// isEnforced returns true for rules whose violation is an error. Generated
+// Validate methods report errors only, rules of lower level are left to the
+// validator, which reports them with their level.
+func isEnforced(r Rule) bool {
+	switch r.Base().EffectiveLevel() {
+	case ConstraintLevelCritical, ConstraintLevelError:
+		return true
+	}
+	return false
+}
+
+func closedAllowedValues(rules []Rule) []*AllowedValues {
+	var result []*AllowedValues
+	for _, r := range rules {
+		if av, ok := r.(*AllowedValues); ok && isEnforced(r) && !av.AllowsOther() && len(av.Enum) > 0 {
+			result = append(result, av)
+		}
+	}
+	return result
+}
+
+func patterns(rules []Rule) []string {
+	var result []string
+	for _, r := range rules {
+		if m, ok := r.(*Matches); ok && isEnforced(r) && m.Regex != "" {
+			result = append(result, m.Regex)
+		}
+	}
+	return result
+}
+
+// bindFlagRules attaches rules of a definition that target one of its flags
+// (such as target="@id") to the flag. Flags whose reference was not resolved
+// are skipped.
+func bindFlagRules(c *Constraint, flags []Flag) {
+	for _, r := range c.Rules() {
+		target := r.Base().EffectiveTarget()
+		if !strings.HasPrefix(target, "@") {
+			continue
+		}
+		for i := range flags {
+			if flags[i].Name == "" && flags[i].Def == nil {
+				continue
+			}
+			if flags[i].XmlName() == target[1:] {
+				flags[i].parentRules = append(flags[i].parentRules, r)
+			}
+		}
+	}
+}
+
+// GoValidatePath returns the path used by Validate method of the assembly
+func (da *DefineAssembly) GoValidatePath() string {
+	return "/" + da.RootXmlName()
+}
+
+// GoValidatePath returns the path used by Validate method of the field
+func (df *DefineField) GoValidatePath() string {
+	return "/" + df.Name
+}
+
+// GeneratesValidation returns true when any of the generated types receives
+// validation methods
+func (metaschema *Metaschema) GeneratesValidation() bool {
+	if len(metaschema.AllDefineAssemblies()) > 0 {
+		return true
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		if df.HasValidate() {
+			return true
+		}
+	}
+	for _, e := range metaschema.GoEnums() {
+		// closed enums check their values when marshalled
+		if e.Closed() {
+			return true
+		}
+	}
+	return false
+}
+
+// GoValue returns go expression holding the value of the field
+func (df *DefineField) GoValue(receiver string) string {
+	switch {
+	case df.HasTypedValue():
+		return receiver + "." + df.GoName() + ".String()"
+	case len(df.Flags) > 0 && df.GoEnum() != nil:
+		return "string(" + receiver + "." + df.GoName() + ")"
+	case len(df.Flags) > 0:
+		return receiver + "." + df.GoName()
+	}
+	return "string(*" + receiver + ")"
+}
@@ -0,0 +1,88 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// Field numbers from minReserved to maxReserved are reserved by the protocol
+// buffers implementation, the range is skipped
+const (
+	minReserved = 19000
+	maxReserved = 19999
+)
+
+// Lock records field numbers of messages, so that numbers do not change
+// when the metaschema evolves. Fields are never removed from the lock, their
+// numbers are reserved instead of reused.
+type Lock struct {
+	Messages map[string]map[string]int `json:"messages"`
+}
+
+// ReadLock reads the lock file, missing file gives empty lock
+func ReadLock(path string) (*Lock, error) {
+	lock := &Lock{Messages: map[string]map[string]int{}}
+	data, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, err
+	}
+	if lock.Messages == nil {
+		lock.Messages = map[string]map[string]int{}
+	}
+	return lock, nil
+}
+
+// Write writes the lock file
+func (l *Lock) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), os.FileMode(0644))
+}
+
+// number sets numbers of the message fields, new fields are numbered after
+// the highest number of the message found in the lock
+func (l *Lock) number(m *Message) {
+	numbers := l.Messages[m.Name]
+	if numbers == nil {
+		numbers = map[string]int{}
+		l.Messages[m.Name] = numbers
+	}
+	next := 1
+	for _, n := range numbers {
+		if n >= next {
+			next = n + 1
+		}
+	}
+	used := map[string]bool{}
+	for _, f := range m.Fields {
+		used[f.Name] = true
+		if n, ok := numbers[f.Name]; ok {
+			f.Number = n
+			continue
+		}
+		if next >= minReserved && next <= maxReserved {
+			next = maxReserved + 1
+		}
+		f.Number, numbers[f.Name] = next, next
+		next++
+	}
+	for name, n := range numbers {
+		if !used[name] {
+			m.Reserved = append(m.Reserved, n)
+			m.ReservedNames = append(m.ReservedNames, name)
+		}
+	}
+	sort.Ints(m.Reserved)
+	sort.Strings(m.ReservedNames)
+}
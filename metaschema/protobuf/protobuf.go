@@ -0,0 +1,371 @@
+// Package protobuf builds Protocol Buffers (proto3) schema of the models
+// generated from compiled metaschemas, together with go statements that
+// convert between the generated structs and the messages generated by
+// protoc-gen-go. The conversions live in package <package>pbconv next to
+// package <package>pb of the messages, so that the models build before
+// protoc is run.
+//
+// Each assembly and each field with flags becomes a message. Flags, values
+// of fields and fields without flags are scalars: typed values are kept as
+// bool, int64, uint64 or bytes, all other values including markup (XHTML)
+// and dates are strings in their lexical form. Multiplexed groups are
+// repeated fields, members of BY_KEY groups keep their key flag.
+//
+// Field numbers are kept stable between runs by a Lock, numbers of removed
+// fields are reserved.
+package protobuf
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Schema is the .proto file of a single metaschema
+type Schema struct {
+	// Package is the proto package, that is the go package name of the
+	// metaschema
+	Package string
+	// GoImportPath is the go package of the messages generated by
+	// protoc-gen-go
+	GoImportPath string
+	// GoPackageName is the name of the GoImportPath package
+	GoPackageName string
+	// ModelsImportPath is the go package of the models generated from the
+	// metaschema
+	ModelsImportPath string
+	// ConvPackageName is the name of the package of the conversions
+	ConvPackageName string
+	// ConvImports are go packages of conversions of imported metaschemas
+	// the conversions refer to
+	ConvImports []string
+	// Imports are .proto files of imported metaschemas
+	Imports  []string
+	Messages []*Message
+	// UsesDatatype and UsesMarkup tell which packages the conversions refer
+	// to
+	UsesDatatype bool
+	UsesMarkup   bool
+}
+
+// Message is a message of an assembly or of a field with flags
+type Message struct {
+	Name    string
+	Comment string
+	Fields  []*Field
+	// Reserved lists numbers and names of fields removed since the lock
+	// was written
+	Reserved      []int
+	ReservedNames []string
+}
+
+// Field is a field of a message
+type Field struct {
+	Name    string
+	Number  int
+	Comment string
+	// Label is optional, repeated or empty
+	Label string
+	Type  string
+	// ToProto sets the message m from the go struct x, FromProto sets the
+	// struct from the message. Types of the models are qualified by name
+	// of the models package.
+	ToProto   string
+	FromProto string
+}
+
+// FileName returns path of the .proto file relative to the output directory
+func FileName(metaschema *parser.Metaschema) string {
+	return metaschema.GoPackageName() + "/" + metaschema.GoPackageName() + ".proto"
+}
+
+// LockFileName returns path of the lock file relative to the output
+// directory
+func LockFileName(metaschema *parser.Metaschema) string {
+	return FileName(metaschema) + ".lock"
+}
+
+// Generate builds the schema of the metaschema, field numbers are taken from
+// the lock, which is updated by numbers of new fields. Go code of the
+// metaschema is expected within baseDir of metaschema.GoMod.
+func Generate(metaschema *parser.Metaschema, lock *Lock, baseDir string) (*Schema, error) {
+	pkg := metaschema.GoPackageName()
+	s := &Schema{
+		Package:          pkg,
+		GoImportPath:     fmt.Sprintf("%s/%s/%s/%spb", metaschema.GoMod, baseDir, pkg, pkg),
+		GoPackageName:    pkg + "pb",
+		ModelsImportPath: fmt.Sprintf("%s/%s/%s", metaschema.GoMod, baseDir, pkg),
+		ConvPackageName:  pkg + "pbconv",
+	}
+	for _, im := range metaschema.ImportedDependencies() {
+		s.Imports = append(s.Imports, FileName(im))
+	}
+	sort.Strings(s.Imports)
+
+	b := &builder{models: pkg, convImports: map[string]bool{}, baseDir: baseDir}
+	for _, da := range metaschema.AllDefineAssemblies() {
+		m := &Message{Name: da.GoTypeName(), Comment: text(da.Description)}
+		if err := b.flags(m, da.Flags); err != nil {
+			return nil, err
+		}
+		if da.Model != nil {
+			for _, item := range da.Model.GoStructItems() {
+				f, err := b.modelItem(item)
+				if err != nil {
+					return nil, err
+				}
+				m.Fields = append(m.Fields, f)
+			}
+		}
+		s.Messages = append(s.Messages, m)
+	}
+	for _, df := range metaschema.AllDefineFields() {
+		if len(df.Flags) == 0 {
+			continue
+		}
+		m := &Message{Name: df.GoTypeName(), Comment: text(df.Description)}
+		if err := b.flags(m, df.Flags); err != nil {
+			return nil, err
+		}
+		if !df.Empty() {
+			goType, err := df.GoValueType()
+			if err != nil {
+				return nil, err
+			}
+			dt, err := df.GoDatatype()
+			if err != nil {
+				return nil, err
+			}
+			m.Fields = append(m.Fields, b.scalar(df.GoName(), df.JsonName(), goType, dt, ""))
+		}
+		s.Messages = append(s.Messages, m)
+	}
+	for path := range b.convImports {
+		s.ConvImports = append(s.ConvImports, path)
+	}
+	sort.Strings(s.ConvImports)
+
+	for _, m := range s.Messages {
+		lock.number(m)
+		for _, f := range m.Fields {
+			s.UsesDatatype = s.UsesDatatype || strings.Contains(f.FromProto, "datatype.")
+			s.UsesMarkup = s.UsesMarkup || strings.Contains(f.FromProto, "markup.")
+		}
+	}
+	return s, nil
+}
+
+// builder builds fields of messages of a single metaschema
+type builder struct {
+	// models is the go package name of the models
+	models string
+	// convImports collects go packages of conversions of imported
+	// metaschemas
+	convImports map[string]bool
+	baseDir     string
+}
+
+// qualify returns goType as referred to from the conversions package
+func (b *builder) qualify(goType string) string {
+	elemType := strings.TrimPrefix(goType, "*")
+	if strings.Contains(elemType, ".") || types.Universe.Lookup(elemType) != nil {
+		return goType
+	}
+	return strings.TrimSuffix(goType, elemType) + b.models + "." + elemType
+}
+
+func (b *builder) flags(m *Message, flags []parser.Flag) error {
+	for i := range flags {
+		f := &flags[i]
+		goType, err := f.GoValueType()
+		if err != nil {
+			return err
+		}
+		dt, err := f.GoDatatype()
+		if err != nil {
+			return err
+		}
+		field := b.scalar(f.GoName(), f.JsonName(), goType, strings.TrimPrefix(dt, "*"), f.GoComment())
+		m.Fields = append(m.Fields, field)
+	}
+	return nil
+}
+
+func (b *builder) modelItem(item parser.GoStructItem) (*Field, error) {
+	layout := item.GoMemLayout()
+	switch v := item.(type) {
+	case *parser.Assembly:
+		return b.message(v.GoName(), v.JsonName(), v.GoTypeName(), v.Def.Metaschema, v.Metaschema, layout, v.GoComment()), nil
+	case *parser.Field:
+		if v.InXml == "UNWRAPPED" {
+			return b.scalar(v.GoName(), v.JsonName(), layout+"markup.Unwrapped", "markup.Unwrapped", v.GoComment()), nil
+		}
+		if len(v.Def.Flags) > 0 {
+			return b.message(v.GoName(), v.JsonName(), v.GoTypeName(), v.Def.Metaschema, v.Metaschema, layout, v.GoComment()), nil
+		}
+		dt, err := v.Def.GoDatatype()
+		if err != nil {
+			return nil, err
+		}
+		if v.IsMultiple() {
+			return b.repeatedScalar(v.GoName(), v.JsonName(), v.GoTypeName(), dt, v.GoComment()), nil
+		}
+		return b.scalar(v.GoName(), v.JsonName(), layout+v.GoTypeName(), dt, v.GoComment()), nil
+	}
+	return nil, fmt.Errorf("Unknown model item <%s>", item.XmlName())
+}
+
+// representation of a go data type within messages
+type representation struct {
+	protoType string
+	// toProto is format of conversion of go value to the proto type
+	toProto string
+	// text is true for values converted through their lexical form
+	text bool
+}
+
+var representations = map[string]representation{
+	"datatype.Boolean":              {"bool", "bool(%s)", false},
+	"datatype.Integer":              {"int64", "int64(%s)", false},
+	"datatype.NonNegativeInteger":   {"uint64", "uint64(%s)", false},
+	"datatype.PositiveInteger":      {"uint64", "uint64(%s)", false},
+	"datatype.Base64":               {"bytes", "[]byte(%s)", false},
+	"datatype.Date":                 {"string", "%s.String()", true},
+	"datatype.DateTime":             {"string", "%s.String()", true},
+	"datatype.DateWithTimezone":     {"string", "%s.String()", true},
+	"datatype.DateTimeWithTimezone": {"string", "%s.String()", true},
+}
+
+func representationOf(datatype, goType string) representation {
+	if r, ok := representations[datatype]; ok {
+		return r
+	}
+	if goType == "string" {
+		return representation{"string", "%s", false}
+	}
+	return representation{"string", "string(%s)", false}
+}
+
+// fromProto returns format of conversion of proto value to goType
+func fromProto(goType string) string {
+	if goType == "string" {
+		return "%s"
+	}
+	return goType + "(%s)"
+}
+
+// scalar returns field holding single value of goType, that is a pointer
+// when the value may be absent
+func (b *builder) scalar(goName, jsonName, goType, datatype, comment string) *Field {
+	elemType := b.qualify(strings.TrimPrefix(goType, "*"))
+	r := representationOf(datatype, elemType)
+	f := &Field{Name: protoName(jsonName), Comment: text(comment), Type: r.protoType}
+	x, m := "x."+goName, "m."+goCamelCase(f.Name)
+	switch {
+	case !strings.HasPrefix(goType, "*"):
+		f.ToProto = fmt.Sprintf("%s = %s", m, fmt.Sprintf(r.toProto, x))
+		if r.text {
+			f.FromProto = fmt.Sprintf("if err := %s.UnmarshalText([]byte(%s)); err != nil {\nreturn err\n}", x, m)
+		} else {
+			f.FromProto = fmt.Sprintf("%s = %s", x, fmt.Sprintf(fromProto(elemType), m))
+		}
+	case r.protoType == "bytes":
+		// presence of bytes is told by nil
+		f.ToProto = fmt.Sprintf("if %s != nil {\n%s = %s\n}", x, m, fmt.Sprintf(r.toProto, "*"+x))
+		f.FromProto = fmt.Sprintf("if %s != nil {\nv := %s\n%s = &v\n}", m, fmt.Sprintf(fromProto(elemType), m), x)
+	default:
+		f.Label = "optional"
+		value := "*" + x
+		if r.text {
+			// String method of the pointer is used
+			value = x
+		}
+		f.ToProto = fmt.Sprintf("if %s != nil {\nv := %s\n%s = &v\n}", x, fmt.Sprintf(r.toProto, value), m)
+		if r.text {
+			f.FromProto = fmt.Sprintf("if %s != nil {\n%s = new(%s)\nif err := %s.UnmarshalText([]byte(*%s)); err != nil {\nreturn err\n}\n}", m, x, elemType, x, m)
+		} else {
+			f.FromProto = fmt.Sprintf("if %s != nil {\nv := %s\n%s = &v\n}", m, fmt.Sprintf(fromProto(elemType), "*"+m), x)
+		}
+	}
+	return f
+}
+
+// repeatedScalar returns field holding values of fields without flags
+func (b *builder) repeatedScalar(goName, jsonName, elemType, datatype, comment string) *Field {
+	elemType = b.qualify(elemType)
+	r := representationOf(datatype, elemType)
+	f := &Field{Name: protoName(jsonName), Comment: text(comment), Label: "repeated", Type: r.protoType}
+	x, m := "x."+goName, "m."+goCamelCase(f.Name)
+	f.ToProto = fmt.Sprintf("for _, v := range %s {\n%s = append(%s, %s)\n}", x, m, m, fmt.Sprintf(r.toProto, "v"))
+	if r.text {
+		f.FromProto = fmt.Sprintf("for _, v := range %s {\nvar item %s\nif err := item.UnmarshalText([]byte(v)); err != nil {\nreturn err\n}\n%s = append(%s, item)\n}", m, elemType, x, x)
+	} else {
+		f.FromProto = fmt.Sprintf("for _, v := range %s {\n%s = append(%s, %s)\n}", m, x, x, fmt.Sprintf(fromProto(elemType), "v"))
+	}
+	return f
+}
+
+// message returns field holding messages of assemblies or fields with flags,
+// messages of imported metaschemas are converted by their conversions package
+func (b *builder) message(goName, jsonName, goType string, def, owner *parser.Metaschema, layout, comment string) *Field {
+	f := &Field{Name: protoName(jsonName), Comment: text(comment), Type: goType}
+	conv := goType
+	if def != nil && def.GoPackageName() != owner.GoPackageName() {
+		pkg := def.GoPackageName()
+		f.Type = pkg + "." + goType
+		conv = pkg + "pbconv." + goType
+		b.convImports[fmt.Sprintf("%s/%s/%s/%spbconv", def.GoMod, b.baseDir, pkg, pkg)] = true
+	}
+	x, m := "x."+goName, "m."+goCamelCase(f.Name)
+	if layout == "*" {
+		f.ToProto = fmt.Sprintf("%s = %sToProto(%s)", m, conv, x)
+		f.FromProto = fmt.Sprintf("if %s != nil {\n%s = &%s{}\nif err := %sFromProto(%s, %s); err != nil {\nreturn err\n}\n}", m, x, b.qualify(goType), conv, x, m)
+		return f
+	}
+	f.Label = "repeated"
+	f.ToProto = fmt.Sprintf("for i := range %s {\n%s = append(%s, %sToProto(&%s[i]))\n}", x, m, m, conv, x)
+	f.FromProto = fmt.Sprintf("for _, v := range %s {\nvar item %s\nif err := %sFromProto(&item, v); err != nil {\nreturn err\n}\n%s = append(%s, item)\n}", m, b.qualify(goType), conv, x, x)
+	return f
+}
+
+// protoName returns snake_case name of the json property
+func protoName(jsonName string) string {
+	return strings.ToLower(strings.ReplaceAll(jsonName, "-", "_"))
+}
+
+// goCamelCase returns name of the go struct member generated by
+// protoc-gen-go for the proto field name
+func goCamelCase(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b.WriteByte('X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+		case c >= '0' && c <= '9':
+			b.WriteByte(c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b.WriteByte(c)
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b.WriteByte(name[i+1])
+			}
+		}
+	}
+	return b.String()
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// text collapses white space of a description into a single line comment
+func text(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n // ", " ")), " ")
+}
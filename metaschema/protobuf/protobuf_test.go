@@ -0,0 +1,183 @@
+package protobuf
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const catalogMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>catalog</short-name>
+  <define-assembly name="catalog">
+    <description>A catalog</description>
+    <root-name>catalog</root-name>
+    %s
+  </define-assembly>
+  <define-field name="title" as-type="markup-line"><description>A title</description></define-field>
+  <define-field name="note"><description>A note</description></define-field>
+  <define-assembly name="control">
+    <description>A control</description>
+    <flag name="id" as-type="NCName" required="yes"><description>id</description></flag>
+    <flag name="class" as-type="NCName"><description>class</description>
+      <constraint><allowed-values><enum value="basic">Basic</enum></allowed-values></constraint>
+    </flag>
+  </define-assembly>
+</METASCHEMA>`
+
+func compile(t *testing.T, catalog string) *parser.Metaschema {
+	t.Helper()
+	meta := &parser.Metaschema{URI: "file:///catalog.xml", GoMod: "example.com/out"}
+	src := strings.Replace(catalogMetaschema, "%s", catalog, 1)
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return meta
+}
+
+func numbers(m *Message) map[string]int {
+	result := map[string]int{}
+	for _, f := range m.Fields {
+		result[f.Name] = f.Number
+	}
+	return result
+}
+
+func message(s *Schema, name string) *Message {
+	for _, m := range s.Messages {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// TestRegenerate generates the schema of evolving metaschema, the lock is
+// read from the file written by the previous run
+func TestRegenerate(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "catalog.proto.lock")
+	runs := []struct {
+		name     string
+		catalog  string
+		numbers  map[string]int
+		reserved []int
+	}{
+		{"initial", `<flag name="id" as-type="NCName"><description>id</description></flag>
+    <model>
+      <field ref="title"/>
+      <field ref="note" max-occurs="unbounded"><group-as name="notes"/></field>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls"/></assembly>
+    </model>`, map[string]int{"id": 1, "title": 2, "notes": 3, "controls": 4}, nil},
+		{"unchanged", `<flag name="id" as-type="NCName"><description>id</description></flag>
+    <model>
+      <field ref="title"/>
+      <field ref="note" max-occurs="unbounded"><group-as name="notes"/></field>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls"/></assembly>
+    </model>`, map[string]int{"id": 1, "title": 2, "notes": 3, "controls": 4}, nil},
+		{"reordered and removed", `<flag name="version" as-type="string"><description>v</description></flag>
+    <flag name="id" as-type="NCName"><description>id</description></flag>
+    <model>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls"/></assembly>
+      <field ref="title"/>
+    </model>`, map[string]int{"version": 5, "id": 1, "controls": 4, "title": 2}, []int{3}},
+		{"removed field not reused", `<flag name="id" as-type="NCName"><description>id</description></flag>
+    <model>
+      <field ref="note" max-occurs="unbounded"><group-as name="remarks"/></field>
+    </model>`, map[string]int{"id": 1, "remarks": 6}, []int{2, 3, 4, 5}},
+	}
+	var locks []string
+	for _, run := range runs {
+		lock, err := ReadLock(lockPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := Generate(compile(t, run.catalog), lock, "types")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := message(s, "Catalog")
+		if got := numbers(m); !reflect.DeepEqual(got, run.numbers) {
+			t.Errorf("%s: got numbers %v, want %v", run.name, got, run.numbers)
+		}
+		if !reflect.DeepEqual(m.Reserved, run.reserved) {
+			t.Errorf("%s: got reserved %v, want %v", run.name, m.Reserved, run.reserved)
+		}
+		if err := lock.Write(lockPath); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(lockPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		locks = append(locks, string(data))
+	}
+	if locks[0] != locks[1] {
+		t.Errorf("lock changed by regeneration of unchanged metaschema:\n%s\n%s", locks[0], locks[1])
+	}
+}
+
+func TestNumberSkipsReservedRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		locked map[string]int
+		fields []string
+		want   map[string]int
+	}{
+		{"below", map[string]int{"a": 18998}, []string{"a", "b", "c"}, map[string]int{"a": 18998, "b": 18999, "c": 20000}},
+		{"at start", map[string]int{"a": 18999}, []string{"a", "b"}, map[string]int{"a": 18999, "b": 20000}},
+		{"above", map[string]int{"a": 20000}, []string{"a", "b"}, map[string]int{"a": 20000, "b": 20001}},
+	}
+	for _, tt := range tests {
+		lock := &Lock{Messages: map[string]map[string]int{"M": tt.locked}}
+		m := &Message{Name: "M"}
+		for _, name := range tt.fields {
+			m.Fields = append(m.Fields, &Field{Name: name})
+		}
+		lock.number(m)
+		if got := numbers(m); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	s, err := Generate(compile(t, `<model>
+      <field ref="title"/>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls"/></assembly>
+    </model>`), &Lock{Messages: map[string]map[string]int{}}, "types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.GoImportPath != "example.com/out/types/catalog/catalogpb" || s.ModelsImportPath != "example.com/out/types/catalog" || s.ConvPackageName != "catalogpbconv" {
+		t.Errorf("got packages %s, %s and %s", s.GoImportPath, s.ModelsImportPath, s.ConvPackageName)
+	}
+	tests := []struct {
+		message, field string
+		from           string
+	}{
+		// types of the models are qualified, the conversions are in a
+		// package of their own
+		{"Catalog", "title", "v := catalog.Title(*m.Title)"},
+		{"Catalog", "controls", "var item catalog.Control\nif err := ControlFromProto(&item, v); err != nil {"},
+		{"Control", "class", "x.Class = catalog.ControlClass(m.Class)"},
+		{"Control", "id", "x.Id = m.Id"},
+	}
+	for _, tt := range tests {
+		var field *Field
+		for _, f := range message(s, tt.message).Fields {
+			if f.Name == tt.field {
+				field = f
+			}
+		}
+		if field == nil || !strings.Contains(field.FromProto, tt.from) {
+			t.Errorf("%s.%s: got %+v, want conversion %q", tt.message, tt.field, field, tt.from)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/gocomply/metaschema/metaschema/docs"
+	"github.com/gocomply/metaschema/metaschema/markup"
+	"github.com/markbates/pkger"
+)
+
+// GenerateDocs writes the reference to its file within baseDir
+func GenerateDocs(ref *docs.Reference, format docs.Format, baseDir string) error {
+	var buf bytes.Buffer
+	var err error
+	if format == docs.HTML {
+		err = executeHTMLDocs(&buf, ref)
+	} else {
+		err = executeMarkdownDocs(&buf, ref)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(baseDir, os.FileMode(0755)); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(baseDir, ref.FileName()), buf.Bytes(), os.FileMode(0644))
+}
+
+func executeMarkdownDocs(w io.Writer, ref *docs.Reference) error {
+	text, err := readTemplate("docs_markdown")
+	if err != nil {
+		return err
+	}
+	t, err := template.New("docs_markdown.tmpl").Funcs(template.FuncMap{
+		"markdown": func(xhtml string) (string, error) {
+			return markup.ToMarkdown(xhtml)
+		},
+		// cell escapes text within a table cell
+		"cell": func(s string) string {
+			return strings.ReplaceAll(s, "|", `\|`)
+		},
+	}).Parse(text)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, ref)
+}
+
+func executeHTMLDocs(w io.Writer, ref *docs.Reference) error {
+	text, err := readTemplate("docs_html")
+	if err != nil {
+		return err
+	}
+	t, err := htmltemplate.New("docs_html.tmpl").Funcs(htmltemplate.FuncMap{
+		// markup passes remarks through, they are XHTML checked by the parser
+		"markup": func(xhtml string) htmltemplate.HTML {
+			return htmltemplate.HTML(xhtml) // #nosec G203
+		},
+	}).Parse(text)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, ref)
+}
+
+func readTemplate(templateName string) (string, error) {
+	in, err := pkger.Open("/metaschema/templates/" + templateName + ".tmpl")
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	tempText, err := io.ReadAll(in)
+	return string(tempText), err
+}
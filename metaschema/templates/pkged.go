@@ -10,4 +10,4 @@ import (
 	"github.com/markbates/pkger/pkging/mem"
 )
 
-var _ = pkger.Apply(mem.UnmarshalEmbed([]byte(`1f8b08000000000000ffec7b6d73a2c8d7f757f917afdde1494cb0eaff424844d438134d04d9da9a6aa00542f3b034a8b835dffdae6e10d11893d99d7bafbaeaca0bc63edd87d3e7b9e147e62f2688d70966fa7f31e4ba0b32a6cfb07e124116a3220438087f5b8b22eb821cb019747d90b35e90b37e61b35ee224518a4a368239c08e0f23c074183d4a932cff06729fe9334c87998108327de684e72e71aac527907930afc6f324a9470f20777ca61f17087598450e1064fa6b8030aca9390438892b5e2d190608e20377b57b43dec1b4193f419c9f7193a9b33b1e12b720fbfdc5d4267841ee17f6172789de30f8010431d3cfb302767e85ffb4e42171ff9110d64bbe44894b652d618603ea2bfe0b2f323f7efce830ebca057fbd635abf2d3287518a400e31ebc118662087eef7287121c25ff22845441a4924f2ebc21c04886e1057b1bf7c4b87c1c11e327d5ebc113b0c5961fa5db14787dff380de297002f71bcfff26724f3cd7e7a53e7ffbe596136f794eb8bdb5980e13e0ef2ef1799d1eb8a4fbdec10dd3ef756f88603d4e98feadcc776f7a7c8799a1200e993e4f030d99be280a5cb7c33c072ed3e7398eeb30da71687eff9e029763fa5c8799bb4426d761162da5151456367439b947c8c40931d3bfed30833c8888260be8307dbec7f56e445ee4e50e33c364a67723def2bd2ec7ffe8300f17596f0fac8db93f3a8cfa7156f3fbf7222e307499feef5c87eb707fd0d8fb30fb15757e3135ce8bff7a7abd25a2ee17eda96bede21fd55add687e67be307f349da6aae4d3468309f51f17a6307661ec94fdffb48c0b40ec2408462066719e3900c3765ffabd31e58b97906d9a86f33b6397958130cb928c0cd651ce747ec27129c830cc4e6f894016dab454d3d0ab1613769d641120b283840d92220f48fd2564cb14e43e4b5a021990a2ccb320f6c84a0e7779131aa279d54d7f67ec621d244ca7519fa898418cd935e56c4d78fb20a5749c832086198b029cd7137047475999e64933604125b1229c20f5a90535edb6175d0c8e04744e495790245e7e35c106710eb3182016ba5b90b9f89c0da120cd03e738e347a04535b76720766b2f9e2fe1c2ce113c2e44ae7424c87d2dcae9b688b601d807fc092548bd135ae285167db6658e5a7eda499c7c4ab16918ec48dac54ee206b1d71ab200c77c9bb60186bdeec94c1083ac6ccff8b02d8d7d2135d3a25318b5c95d84de4979bb58af014a581f66f01f97c3c5eabc5e2e6f2e5ed0e89ca3498308a4f83a6b1a7a95f3dfe56171eed262f51216d0026a97b497b40c4fd834a33a540476401c1f883c0921898c0fb05fffb04ee68824931abd495103e4b5a79cb46893eb28c74996b7a76298e71970607b2ec134c6eda93441a84d9fdf92c135824e8e82fc641a07b187e01a059e7fb22b2eb1031062e10e3a30de5c5a2a629ae9cd7c0e718e9213eb8a188335f42170a99f68676b77c98a3daaba23f961edc03b0e737c18d7b51d914797ea878d0a940729a0cea2137f16490e5d1a2260d3928d615efdcbfa799eb686f49f83579bc983daf51ce9a26996d00e4ae8226b1a7b82a963ea167fa1d3d7dea6230feed266c0e232ce01f15b56c479654e3d621d2f69518d13419e44b46dbe5aa91df76a9e3caa75983a914865263482c7e30797b153ff1cc5d7716d4e27606fb3a0caf6d3e3ea8cae2a84cc56e61471e09007c066c416f99aef9dd2b79424d9c174980d8cdd2463bd0481d8fb92641ebb630fc7940f1c1f08dcc7b8d20495bcc849ef70d31f528c1fe53bb4ee6bcc45b6818723ef0a9f1fbaebeb1caf4fbb2bccef584cf2d68d31b9228831f0de127752195e412bef5dbe344b76e53b8c02eba7c009af70056e0cde58c625ae3be4a5559a85183a4506593b7083ac78d35b55c26620c6a4b55f633ae42811f811be98c8fbe3f315b87a05ae1b22b12ff43e625efd4af233efc33f3a0c5195e933d3f2d67b14e4d05a28b16548c889869c653c7aab38f4c068ce39a387deb4946360ce13d7d08b9520e753cedd3c0a72ee683b692af0c8d586fb95e023dbb8bfd1efe7dff47be9db525586f37bf43cf5d21b58ceb72b6396ad0c173d45c3dc5a0c7adf1683622ec88f67f3f9dae4268e367c01c230b616ca0d2c0761433f4bbe6d3c7b84478df31b4b58720b83dfdae29873ca4171981f6b9cf7f585f3a641250f8a79b932a4d8227b6acb0818127a34677bcbe0037b84e4f563e2e9eaed461fe537ba2a8d6c6eb6b10d1ed9f123ddcb1da16da58b34b2f9b9e468cbaf2b83476b93f31c715ebac68cd34778a2abca0d1450a4abd2c23295d23267c88ee7fbe748deb8f74b6cb564eaaab27a7ade5139fac8cf6d55aac69ad2b58d6d4f8f7cce1d0d88ef5f1c71fc328da4d4111f0b4b9085a920eda782bc5f09433cdddf170f2af55351f95091d70bdd03f16c63bf24c17431f6d460e04111e7bab62cac112f131f4d17cad127d1ceb7c4d9bb7ea0f6111f70240e4b797df00937f75d6dc84153a13e59117bee92008ab830341e53bd4c5e9e6a43ce1de9d816f8d4d596b9339a4b7aa0fce90872f13518135913327e34a4c216894cb4b1635ed6a3c1e4447f6a4f9e5ac1a07832e4d03276b5eeb59da3b16f472ed25569660b7364135bf819e7c4cb17f71e7196c1ef2b7b06f41e520350c4de34acf2da369685ab2a8dccb7ede691fdbcf3a121f3ee889789bd73417e267ea0363ff3bc3d9aa78eb643505b86977cf3cad60ffa83ea35a271447634af741de537962185551dd0bc3fac5dac81798490adcddf8d3b147168aba466156c195d6f1a5a7865b87b5de5e5f523a96fce03c6cab3c4476fac6dbd07b5aabb9faa9b92f39c685858c27323bf8ed1665a56f93d17e43b5be073cb9038bdc903eaf3466e4ddfafccb94ff73779b9a92992f7c8cfedfb431c77be3b9a97534dce813947b6a970b0553b44d6828cc361614732b732e7a92d48f2baa929e2df476f6af2b2ea258778a456a014b6f8e84dc33ac7a99feaf5d0dd1cea411fcd38274285552a1ed478fc35d0f14af07d2722b1be0f742ddddb42b7d23b246349b12369436a0d1832e977c16a909ce5417aa86f648f66e858b7558ca621da3f19c31288cbed512f129be7a60696c46fc75eeb7d5b28b39539ce5c73d0c8b28cdd9ef69a0fdc4f620d0c29bed48748be367924d4f3f4cc50daf9ba257c9621bd0063b9a7b18f9b3d4f6b8ef8f58e3be882dcfb93f3e9c29943faa57429472787f8ae4deebff4052583717e3c988f472f3db5ff295c4ddfc510dcc1ec2741eb57371ea06b51bcbd7d0fba166e9ef89bbe70d3eff6be7022d7bde545aefbd3d0b5f0eba0eb4ae99f83ae79893b80cc3c2ff4f8ae7c2b5d82aebbf22dd7eb1d580fd65e42aedfe4fc04ae3f81eb4fe0fa13b8fe04ae3f81eb4fe0fa13b8fe04ae3f81eb4fe0fa13b8fe04aeffff01d7af5e70ff4df83ad94c4b65e61a3b0e980ab64c1f39f183b732c748d7506169a8b48c19a76bfcc6d296d82995c2329621d1c18ae4521fcd901321dfd650af828fba846f6f99e37465ec521855508e1adc6e746db8af211f6f654861050b2b113076c829951018566499ba671b43aebe1fc38592da81c2016d993a81b27747637e25ce79277a2e28143e52fc9590fb9650c11afa5d2253888f3f9b6fa0bc795ef1742b582b3ef055f0ce2b79155c93daa6b271e2476f323840a6e41a07d09c23a7d427c7b9416019d28b2dcc533b7236146a0bf4c9e40ca27c3297d8d5d0d6d6965dcb1cd750a04221e6038c76849928246b9be6abf96f4e84626048be3daa213acde2ede8c19b683c95adabe925e827d587cbc23686a523f8be7d9f3e3d71dd641528b6698c25577b4e756d593a34beb86d5b0b021c140ba3826f9f8ce1b60d37577c96ef044a6ac7b3d4d296b395392071dd1a620d09f2fc2bbd9ec4716a09a8585570f7f8288f428ec88975fad9a3f2a9b4b423de77e259b232b6c92ad87ae308158e8042eb99f71d35bca1360403ffdb422980b1f5e0b61dbbf6a594964972aaeb59e6b8548393b5caaf2dfe1a2ace57468e26c3dc7d0d8bcb2530dcd48e86786d7258bf1b605ddb213bf0dbfe48278fa772693d6918eba315b5f300170343da03638e889f5fc5a375b981d440ea0b6129d5d066766a4febd2b664dd77460ab2a3c7c456b79e1b84137571e4599fe958c39bd81196f2f95acb67c28ad4b1fa819c7d4bb7d14c04e6fc05a84a60ec15f79add2b61b8b7168318964eef4d796d3f4543d2b7523b72b165ce3776f01ac23fcbc57765ea1a8aea7c23b9413f714c51abc6543f98aa83c8115061093be46a723179c234df74f55ed63529b555e5e623fa7f2c6f2f5f1fb3e5755e9ce8fd76dc43cbb07c72967cf57e9dcfec9faae5cb97132d39d71c1754ce47741b716fe49b7296b387cf05d567a9535eef4f9b9ea95eaadf719e3d48ded0494ae9da889b10d956bc2c56e520a9eff526f1a5f320f49ecefaf76431480c9ea767d25425fd72bc7182b0e5ab93cf24f5a71fde775ed5a1f2a753d298d4e79bf4d5325dd5adcee7660f58ba3763914bcffba365f05b7714d21e46ce6beb529f1bdebeea7393a83eb3aed43ad15d37eacf4e57f85ab69c9d1d0fc5521ca7aeb654890d13d5c1e3f217e6d730775731e22c3397ab18e81fd1f14a1f7c2b0f8f7efeb650a295b1db5b8fc955bf99e5b6ea37e4f9474bf753e496c09ca3e7faf3d5441d3b7aa07b935211a6c74f7253cb44f27a31c874b2fe92047fb76ffd1d1fadafd874f9bc1b04fa9bf5ad7096e973f59eed6789d9ca1cef8136c413d512aef4b72697f427ceb323843f98834d9f6e6a47e4f0f55e74252748df24f553e5ec492c27a33982a3c7f46bf93fa5e72bfa9dfa73e59fabbf8feb787ed61dfb6dba9f86e43977b99fa8216efa6fab47d2cfbfe6dfee8bf6675ffcdfd1175d6dd975d5d3be680bdda2395b552f22cfc4d7fcfe2ff6c26bb15c1ffa1a7d962a25d38910672d7cdaf3fee59e7d956e7c76760e3d12f90b2f3617ce4ff5af0feb789e33a3317247cbd20ea8bfd4aaa6bd74aad25c6f78cfff34abfa73a3d69cf7df77ff7ca2fa840e3fff93e7ff05a0f0c7ff030000ffff010000ffff3e077d95d73b0000`)))
+var _ = pkger.Apply(mem.UnmarshalEmbed([]byte(`1f8b08000000000000ffecbd6973a3c8b23ffc556ee8edf46db348b671c47d61b0859064b5252cb6132726d80c98b5055af089f9ee4f6451ac42b2dd33679effb9572fdc2d8aa296ccacacacaa5f66fd6be045af713ab8fbd700fe1ebccde06e70b589e3ec2a8cad6d600fbe0d84308937d9b39eb983bbc1e0db60a187f6e06e50bd7f88cde2c58bbe71ecacf8bd8a63fceb49cf4c7770176d83e0db40ccf4c01edcbdea416ae3a795ada77154e4e5e3b117d86999bba8b97a7cb093eaf78b9d669ddc90d4f9e2a968e3ddbf06b8f98e97b95be3bb1987574e6cc66112e457a19de9a9e9daa18e5aeb4583bb6cb3b5bff553838f9f62ab937ce5c4dfc3d8426f257b937aa83fe4778a1cfcf1c71fdf06af45a7fef541f5778ddf57991d26819ed9e995159be9ef6e1606dfb33009a018e019fc6fd999ee05887b51c1944ede6f83d47bb7077734435e7f038ed983bb2145a09fbf671efa8422a8ebff2689ff266f5e08e68e1edd51f4f71b821ed2238ad106df065efabbe56d2a9ea539aaefc1de0deeae470435fc3610a278704792e490a2a96f8345e045fee08efc367842f5d134797bfb6db0f6acc11df16dc0e3ff95df7f4f748b40bf571694467c1b888dd6b2815f347e4830e83136fd747077fb6d709f7921b441b4cdc11d79c35014c5dca0aa5348a1a99b213dba25e83fbe0d9efab2527499b5ec27f1c7b701f751d65b92b9be666efef836507eff7d1b6d53db1adcfd83f8467c23fe89d8ecda9bfe41d4cbd7eec83a2f1ba78ac083b199746e3c365b558fcd7f0cbe0ffe590dce42f8db63d3d87a81f55fc2c37f855e1aa28f1a83f51f48ecbe3bf1e0db20d9c4595cfc2cdb044fff6c0ce67f0c8cbc68a9bdd9c41bf8f11a66836f5fa000d4f7a50f427de36f932f7d92e89bd4de7ced13e8bcb17d6d7f04751b682427be5394185fbdc69b50874ec3c0ae380ac30d48170351123d73af4075c08fc1b7419a6dbcc88137691e9988c087acfef49fa582fcc7c0d8bea2624a3a9b21741d9abeb1d3f4ea1557552538ef5e9121ca742fb23757aeadb713022fcd70827d40bf367992c5d58f2bdd4eeb07d34b602c54cf56f3a595eaf5836d5a6eeba9f5d2a24623926924048197649e59a7bc7a494a0e893ac1f5add7c653a83732bb896fd74f5e94d99b480fae8c18e87af2c595617867dea6bd2fcd384a333dca908e3d7e6d47d9264ef2ab1df99df84ef46438ea57f74d9be07d6faf1c333c9723f0f47325189e534c69a73298ae6dfa67de5b1bc339f3bacdf9bed7a97eee7d57367a72ecf58d957e25dbd5ab6707e7fadc96aee3d72d713b7a1d06e7fb1406be7d8e65919766f6b90a8a0c57af9e9e9dc9b539db88d4d5a9d1f5f90cf4f9d723923a97616b64817d264316a4670b80f7675a60eaa67ba678cb4ed22b508cf1c6b2371fe43393ed07399cd8b28ded194147b94ea8019cc5d5d33343218e82bce7ad07b6e071f2468ffa041892b799d7f7459aa7ed8f426bd47868cb6c4744db1f6ecc61e3a1f959eaea64eba925626d89ea0a50575eb2a0a1b6b2203d22582bc3614434463f3c5d25be77187c1bd891195b85e22f7f5ee96944369f0d3db569aa9b723d6ca57891bec99b29aedd2cffea0d4ca8ce73d5e8932f50b6d74077d2f359e224fb20c7dedbd84739ded26a2a6fbfd8b5ba9bd861f3f110061f986dc6f6f5550fe22bd7ded8dd77ff7b4c3a4f8fcc38b0433dba4ab38da9a7f68726dfc9973da4eae6a8d819ea497a3e6be23b854c7f98e72acd2c64673af1958eac3b27be42767e61bd6c742fc2a9a535533c59b17965c661684759c79675e20689e3ab64831a5ebc484d3d8aca872cf6ed08ffcc131bb7628797ce909ef8ce772fbacaf530f8be036d8f9524fc77656e4c342ad143a827e5bb2c0cfa2cea927abae1b51e533d6a3e1b5e6a9b592b25cf6c3d70ba49e5cc51259aae6ebafa2dd6867572bcb337ba635f6d3233deb5de24dbe66369e4075ebbc1af6196c69b56939c58df986e3ba59c81ba49693bcd3e24f6c6c36c6ba4c79d7cbb76e79cb8e64b9518766817d959b6d1cd56ebe314298a6652120741eb791343df37b6196f5aa4eb96b5b15f03dbccba04da6c23985aaff42c0e3db3ef8de96ce26dd2f7c63e78991bc77edf3ba7b72cc74452dcf70a0fcc9ef4cced4b4f924dfc7a15e8861df4bd864d96fe64530f82abc08bb6876686547fb5375edc4af22227b05f03cf715bfc067d15472d69c40bcb2e71f12ab3f59cd969bb34dc22fb609b76b4eb7bb58dbc565ba188206ec92b129de2df1dd57a01cae1aa9a6faaf46d043d766d1d0f44d4f3f8ea1551ad5039451541ec54da73f06d80d984b902ff5d158b3bfc332bdf967654f5fb0a352c2cec38f8ef2adc069997e86878a2849fdb38b32da4f47403592c910d2f233bbb72b32c69fc44cfe580a9121b0d3d4abbd253d3f37adfc01375f20d68ea383af93a7ddde177919d79651bc13240331f7ede6e827257224e11b3f1fe44cf36051eaae897631f92ea070850a6832c6079ae7f5d9968c7280d3c134d0658ebd5b27ab4f701ffd5c31ecb5ab527a21bfb0d9683f62649e7b998aee0b3a239dbc83363abf1eb6a9bbd92d7ede7dbe2f1e7b6c8079238f836d8d991156fae9c38d023e77bbc71ae0e57d8ea2ca6078af85cae240e729226461fe44645c33ae6b3f94ae3f64ce64a32cacd81cfe4fda0bd205a56945e59511ada69aa3ba71a5cc927fce36cb3f433f9924d7cc83fc8485db9896efa67727956a49f789de6e52ab0ef2d12a6d436b71bfbcaf02c6f539c119ccc9a6df4280573e95ca652d4a0c0cfe48b8af2f6b6ee0ffef99f74328255089ccef8ce679a8037bb1b89a78f49fef836b0f44c1fdc0d9eb9f1e30bb158cb123b16789730e4fd6fdcdb21b678327d76e25b9d975ced61387be6c9c0e21f1d95725d335a04d603e159ca2a9cbf381ee4b3f88030f8f56f369d6e5fc271a62924238ca781164ab9268fdeb497fdaeccc3bd1ddead49906a2fc3991a32be2db23702afed8c689969e138d3e5c3e887c7beabf2e87dae2c02330cc21f399ba98a935954e05bbc732d3ca87b4d266e049e74cdd04a8cb7d851158930de53e7759910aa3c4d3554ee746786abc0f4c837833aa4aac2be6b2fb1a3524c6af0e3bd49ad6f840931b3782715262b5f98a48e1a32b9c64bf90feffe604e1cc7a4985497978e402dded4f7d431f9b1aff1c1567b8f9d276e986b3201ff93a83d9355604f96992a1f128d1a5e0bfc21d0a2e58d30d102335a252a354ee7f238d5656bfbc3630983be47f59b936950d43d7ed3292b376869ab3dc48e40a984f6b60a170f47f516f5f10ca529d3d0e019fa87c7ba96b2dafdc859868b16814aaf12831a3a76ce7a063df53545c82c9ed99fead7eb32be9dd30bc2960f01f0744eb9812a2f411e3c835a8d204d7f783ccb6378cfbd1d48e36138b3e93413f82014b8d10f55260393665d959218a8c7e0fddfe447f249e047ae214bef263f7e03be3cf38b9dd692a3c55e9517c1ab42dccea9e2dd33c7a4fa4b59beb4d52624c345d9cd5c6413cdbbdf8ad16267f853d7a42469ed07557d62b0787ef1584f551681309644b153dfd1772ffb9d4a31bef632bc9d5387e4d9496eec9c703479e4bf2ac4ec993f24cfbeb51326d0afccd5a8f5b5f0707833a855f00ced09ca74a9a72ca0b59496655a5440e8dcfd761d4a99aa4c37265df4c990c7b94e4b7b811b31afcb76fd0dfab226bd080c799aeab2f40eef9e79277ff6c7ef2625656a784834e5e9764e3939f0d082310eed13d95c95479126de6f97cae25d9349cfe08300d5fd76001adfaa221b9ba1143e8bc21bf07d298fde749ec95f15c2437df411ff8afebd8c6e6cda4a2cde71e6beb63343d2351ef17bf1c173a64b07ca7855c81b9b2af8d6cbcbb7fdaed1c6c0085755df755985b2134d3ef8b89df1d3db88d76529d526e7fbb80a83c0e057983e87e4f9ede00abc9b6bb2ca08dee2c6ce47ac112e62839e32afa2d092f3678e71a1bf259f5621931bf2382ddf0b3f66f701c74219ccab92dd601ef5cb0dc734c6c7b1fcaec283abd10b24b7fa83f05bf1fc51dfea6f0cdeffed997f74f4c934d0de084fa0814fe3ad4ab93b332219e16d04ed4463b268238ce98cd695552c7023dea0a7a0bfcaf7ce30e6d6c224bb99472463d359c18f97fd0eea69f3a82dd3657f116f2753d708ade05856c91b9b5e0586c2a6aab20a046eea6bb296186140e832b315bcfb96bc9ce6e99f2d07d34fc96e2c5eca4cfee05afcda11c255a085c15657902ef504aec55fe065a51be7149e3f8b36e2fa8fcb40df4d6afdaccb4b4688ba3c8239d7797f46e95982e82bdedf16faa8cbbf52879de4e1751ffff487a7b2ade5778f9ab27833c3606ff1c1ce88d0f8daa3364468acee8f647952f31b8dcd60ba33e855596f31ce50dad2d1e44360c8d2d67a68ebdd75c8ecac524f7ca0731b3a6f6a8487c408ebf9e48567de5479ef68bc14eaf228417d78eb93d3b6ce5df1634295562393afcb4269224bd80a1bfcf05a74ff5c1b27a51e4cde0d6a34d794a37968a3297e7bbe28685c3c7fa2dd68be0a34d79848814849a38ade7ef2f2420c1d2b1ca796bc76744a1a7db50f6d1d85e8ad1af261a92aabb8d4bb8b8791fab2de835d42e885ee254ee9279927d335cad7d451b50c7c657e7f91995c9358d7e2dd5a976f67f78987f4efc2a0a7415597c866f07c769e29fa325b13cc0f615294fbcc31f1e261d8926f331c6f356a5df2b4498b62aefcaa8c14b651bbbd9836c3980bd198f5c99d194a555d020fcfeb4ff0ec986ecd39427f5836e795826ffcd8c3f63961be0d6f2dde4136da33c710fa43f18c6417e667def9ed79b28a9ffd856b86abc408c7a9aeac46cdbccd31d44e97de557a9a9813a4ff6ee7f42a7e863227c26f27f5f232beb52602d4e9833e42bac687f19f31af2f8d791cbe2fd345a1256320838df9bc1a672af08c9252136cd397a6fde66695fd52f17354cd21d0270dd165d9aa078dcba65d54ca01bdf29fdf0e84f680c6e083aa4c7d5d1eb9061f10b672babc967e3a5dde91eeb6e9aca2e3523ea4066d05dab8d01730869ff9695ed372911af422807ebd50a340e09910cd1513b0bd49d6e00f3b8b927c6182bfcfd9c40817a925af827a4e899d469dbe2eee9de944757ebc11ce1ceb29a8d7a633b0d37d5d219939c7d6f4acfbec5bde4856e503d9e25554cebbe4110fe6f42aef190fc53af0bc2ef135f9508d89e279ff27c704ccd74b2cd707427fe8f0faa59917adf5f6ba3c8a7e758c34fb508d91b73f3b269af2d5d26d339362b67ddf58fcb21a9f95cda26437bdf36cdb96f9707cc11cb1a4c639ac7f616d6d4d8af559bbce9a96683ef9787c9476cf83ce33095a9bbe1c3cf3ad7c5e3b06a5f6da4ea85e8e019968cb16a20396999eb9bb9e43c6a9c133b426afb09cd77ab91aab1c4be17737361584023fda59dcfd76491d76262581ce77663c43e8bc9437c6a56b866b47adca089af4c473daca55c3628d8fe5257ec6e3f357655042b6065e7b4e56798fdc1c8fe72399590526b5c8758545b63f1ae7d57a7215c01e894a8d094d2e6c614d267e5bf1d2de0ca537b0f9358e7dd7611f42c4eb30116c1b29abf5462913ad3e60fec0daa5a4cb197b2e9806c59abce657b5667f079b7af9dba7d6edf57a686cf363586f94eb6eb045aab4868c1ec9ef0faf6fbde910cf4edb4efcca9ec2f384cdb5b62da3beac0f9d7987cd8f74eadb1e64f2adb0b93bef9cfff91f7416b281e3d56a47b3deb344db9dbf0eff06448015efa3cf42c0dbf94b183875cd0c3f0903bfbe236ebe5f330c4153f49771e02473f357e0c08be67e11077ecd94886d9ab81931f4ededcd291cf8f54d8903af3a7a0a07de9ff58203bfe0c02f38f00b0efc8203bfe0c02f38f00b0efc8203bfe0c02f38f00b0efc8203bfe0c02f38f00b0efc8203bfe0c02f38f00b0efc8203bfe0c02f38f0bf1307de3effa8b1e042ce367165ce3a94424d990646b808383853a27ab0bc22e1f4e178d549850d2db0bd0ac9a8f7497516893100c7585d9170fa70ba45790877c341ba244da7af0ae970f5d95e263c76b0b83ceacf5295171b55b68232ffd973316ab5b3a8a10358b946d9b3b2bd5d9cac903f39adb47d0c79ab73d16efeb9c81a4d5ca9223a5d5c6d7206c7d78fc55cd674addb4c96fcc2d8d805f32a3ebd098f183bdb6967031b9b09e3ac2107a435e3bad8d8e0cb38df0e2d83121b80f1bca92a5b7076fc26e42c6ff0e3e8b87d180724128e4c3770c7121177f1ad33e5cb38dd591f2dcff1d1a6334293c9bdc18f094dbcf75a98434f705afc51b29bfadcbfc62eff893230bd7e09f37a16973a17898c7362c07d27da03e11d61be0b6cc84cc89fde8449139bca3a4db95627a779d0736e3d3b77cecd616ce4916cf34c2e7023d1a01802952dde6f457994eaf208ced51f5565e54a93600ffc2bf44c16d8e5ef0e6eb249e71e1c6b268c8f31acd0c7763ed2396a6313a72a124e2f46d569c95c4587aafd0ad21ba83fc2047060956e635ee5fbd6782a695ef5b7f8b6c036f2596097ba343ad2c765bd08df83f11c056eb5d6c9051e45c4d8d5136d28e8e766c6b8c668cebca48915fd3973dae31bb07935669338d5c63efc6726fc388ffdac7982dad1c279cebc78c645cdb1d5c676ce45b6d4657dbcedd447381fe0365b74ea9455e83dc08078f14f8ccd44747ae55844bb570ef371b277da5828df79e55af2819e5be3c8636fb968efc0f816261ffedfaf7b95bd53f1800fb63a4d323285e80ed8e06a0c28a20b694fba3cdae0f9acc4113a5dcc993aa978036d768ef093f23da4f7e225a1bdf0fddc5fb9163f5e03ce14f804e9369dbd69f221edd327ff69b848e8e374a296182be655aedf557588c0dfa64c1d8d991223f873e6933b8d97d242f6f785fd36d99763fc567804ecde12e8dea6b9c8de0a8f35c60ee8dcc18839aff7c9adc011d95c643ffd7f7bfc156d7b55c85b619c1dcbcb988821ad94bf1994c3b347b84495636f5bf3d3491c623dbe9bb42cbe6d639bb18c425d13336448137c00616c7f2c6fbf86333ccdd3b3f2cb79f1cf630ce1d3cf199669236408811b3d183cf38eb174b141af624d11b0cf80f42ef0e35c1359b794ef5963ae069991b06fc127f54c4b9f603906fba5e45d850d2cf9a652526a205e35e56bd4e16303fb37017f53e65153d85c9317aec5c3985c00463928e93a0f988a86b84dc7ba98eec5f9fd9c55cfd5dc85fcf4349ea10de4a3d4b6216a7996862ab213a5721eacb07d332f69d8d15dec23712c0fcbe444fb601e073fd8cc37686b5be1fa705fb99075d4899b19f7498de75b268ecab7e7ed92bfff26ac9e6347f646cf6ceb7700d905e927e07afd9f9488bdeb1b92f804628f24ee46a33bf2f63b43dd323445dfde7c11b1c7107f0962af68ee09c41e79dd07d9a38723b282ec5143fa8622e811d90bd983acb765d6aaa7bd90bd93592f90bd0b64ef02d9bb40f62e90bd0b64ef02d9bb40f62e90bd0b64ef02d9bb40f62e90bd0b64ef02d9bb40f62e90bd0b64ef02d9bb40f62e90bd0b64ef02d9fb9b207bfd6720356a6f9edf3a4b88cc20b291268f02338408194b478d7c479fac0873f2743dcf99084e202d59d8aa1493cd096bb7a41838851acd2988ec3a7e57293730e4c71be171f52c3c8e9e258e1daf1e83f51cd029f96a5fa2e830d2e6fa19900114b3eca467708204883f9d1a4745248e7bbf7a5e034a70ed401e38f5d5288910e1748c9e12665e9ca241fa94278aa841de7d864fd6ca13d40a69d08826ba9be7809068a6af1d5d79722cde0d5ac84491dd691ee4ddd7489ec95389c609348ef56c9125743e786f2009672ac56c4d7a894eca9b51498507c211303a4970922ed2aa6c333a995e86e3774d9244510184c7ed4e7884286d359a514075ae624d6c201f8bf662f44e13ad357a0724823059c5d0cf8abecbe40dde59182952d529de330237c5515184fad40f9d2856914820cad363810e938e228a0a1cdb8a2ab2a298078322334d1e11c02381bb073e14752c634798b4a2dfa0487a28cdb77608d5b4c6080f917db7265352a5978ebd2fbec3082c14f1c5a4a4ad35592094d7aae419c716758cddc50b421eae1d9b27d339428dac8bd3ce8718d06b25920be48635c21144e741d165043805f6d89fc09b1f9e90091e3b52653245bfc3fbaabf27a2b634d03af74e2b428c5f8c2d84120359f0ee1d18a338cadd51b90246141844450fa0e1c4200b6448890013787668c8fb6ba11b994c3cb8d66495cf7926d3158406246c51707440d1bcc55e8da8196fe1e45f55f049ba2838b63c2ee97422cfd4e1a2261f1bfca9901a88f725bfab53fe0271b2071df152f0773545913531cdea6fee7db5a41757e995ae6c3902d7a6f3945fec0c990c8c08b7abc8d3964194d6a5f7715905cfdc9d2e2f822eef1a7d03e493aff6bde76e779dbc35bd5aedbadf0ac7e576f29e9007820c8cf5c1b5658684b6015f571483504f4827ad49d298ac121435889740b69ae300a16896f2686bd080962a9050b5fc4f2b044d4f1e3c2ecee441e3a5a73f35ba6bc645c48c7362a003a03d120d2167d9379d97de741ae96ad7e240f7ae5b485201f81ca268c2a01f77e8395a1066384e8c6855e9414d64334d59b926b588359974b950238df0c9994d1c07902fc77ac7770089a2cbd08f753c13d9c08ca63bd3636f803f6628119632dd0a13675be72b2231ce0a5d5a8c513ef0018152a00e815efe76a54cf319e7cf5e97f10cc67ef77bd407fe001160df85899618d401d3f2a9d0af7cb0c57d6a21f20d9e29235d01ad225dd102c363f750e6d7fa8bfb8150908e63d2ab1c105d3311a185106f4047ce8376db0afdacb9a6c752c2a4ea3ba087b75218ec0cfc9d89f5f85c64db086784247c2aa2e8d6734417a1f6655d62791d74a0e8229accb9fb4af62b79f200ad85c6ce034459b7200f07a8f61add28ca4da4165b47875480ae6462b4505af05dba7d91c743403b56f34111dd535095452def4a63bec0516c4da21eb7783e080d5a707411746149037638c73cadfa3149f19853b74b85dd837e9b4d9c2dee1b9619d2d025e2587e3bbc13e591af294e8c22b9727be7788e115281f79399e8c3fcd5e803a60bf00cfa83e7775159bc68cad211220750e5c5bccedd3b963762cd09e887656c73a38e9e1bd57442e8deb23df71b811376c77cf4136847435e5a7ae713cf47f255ce01980fd4dc5fc40615bc691d796a2054c136f2105a932e6d835532e700c5b90a8cda4e3e925b2c6b280aa6c0a54e254f4a9b374b8a218d6809a8475c26d02c785f53122170d3a1803d1eb09e6dc81fb6457b9e6bdda6ce5a4831e7a43d587b91d4f42beddb894174e7e2db5d895e3b6a0fb2eff7c8be3778695bd9527969ebe3b1c15b84c0ad52e1e1de99c3b88910f2dd399af7807e93056146d29bc5b137557dff176d35d43fd04bcb82664ec52ba4634565b100d4a2a5609aa2fc4d7d4194cf381f8b51d5b52d31ffcbdb5bea91b5d340a78e0b3bf63efb25ba7b87371d10ac80f4fdf7b417cb72435e0b5d5fd2edc1a0464469bfa2f5de58da1af2383729d73560ddc2b1be2643c45ba95cf785684d91b32ef04fa7a4c09c946b5884ee244d8e7d2bcb6dd83d4d1b61a645d256cdef639bbbffd9375666222b1921e99ad12256e503ac85630df2a2f5d32a40911f152115260b4255a684003624377ab1f8716e3d4aa9866cee6522f0526e864c5ecc43459467e1b1f04cea5de72d9bb6437bbc0a5ce5f585e68925c56c2d1ee666b4364c8cd0ace58f0fb64628e5b0de2bec4e168d5b901d4d99e6c243cc08fc72bbe2251465b45e333aa1e5ed9d6954f46bf69216f9b94746e0478951b725d79415698643f4be9a63b864283c54e3a34dd3898abd249e0afd7b34b7dd33254d5e70f4cd52666713755bf7d79f35eb37c220457633c8cf638b67a09350ffccbcb099b16de5a8f2c8c7729195635dade909fb09473676d3763c213320d7ef3a3f4e610f64c6af9d5984e4626c844886f339c7be2319e1d018dcaee9956b46abb1c14b681dd7b5af2de8bf88f99e97e31974bc54c9728b176255575de65f2c473651c8ef6ce224f3fa9b1e5b2560eafefab36e14d523dbe8031d8c6981d6872dda8b6c253bc53c8bf5c0187bf954ba11ea3ebc6b651dfcf81dc957195db8d6a3d53e0c2aaf416f589762f946f3228a3aae9caae793e5017fc260abedbb3618b639b8ce9cdf672f5fd68c973563ef9ab19827e7beeb9a2496336c0f089cbb333d8894ed062a9505da231e0f621d3dfbc87326bfdfc21adee2a5dc889e92a6cc4fb177179a5b38cc4378c6f67739ff808d8e3d6860dcbacfe2bd2760d9e8ee9f1eb50deb8262dd016bf4311aab98de6963ff3685f156b6a9b4f98f6dd5fba375cec9be63dbb8a8bb1b99fca95a17b6fad7f4726978b1156b21166483d0bc7b6faa3c766fcdf9747b6b7ee07547b1865ca8ca0a74c07b459b363d107d4cd0f7d12a14382130c5c2237b86cb68d97027d790d3c09a48b9e1b1546de795765fd5978ef7a8b55bc923d250b0574e4e3853987f647c9e1155670320bf5b835e561ee14d1968af51beb2b6fa608d8474717baee2c2623f4de09cee1e37e67fef9aefc4de7fbdbfd96a57ff3ac3d78056627bde8035c8b3d82b43b83d150d4b5e25354d6b197f558eeca6b5a6b8843099067a28bd5993a786b71b44d6afbddd047e3afa687ef8c84e92786968714e2270aea1c8d391c5afd3d2669e89eccd91cddad18d488e44c7ebab47f0f6b5fe9d3849af47e0e47363f1470e7699e0082f04b237eb7da54afeb7babc2fda8ed6936dbb4c2649cf565601b699206f3d87c9a3ac4d0ff0ce4bca390cebdd621cd437169467706bc7902542551630d633759984963c7a1338772870499fbd98b4d758e826c6d82aea2d79d05db7c03844fd87f16955731d78328ec6462465f5bcddaacb6bda87451d4177ef04dd98724e1fd57b88fe0d6a9777ef3e8b8886d59e596553b4e87fffd3e6ea35457bce760990f3595b8761be74ed545cff127934362243e0755c53c79cdb4fe15668ed8ae7c73e7d71b467557d0bfb86f8fbb91fbcaf43e960c9418e3c535139eab64a5b43bbcc6a3e827dca298fd27af778cbb9a64717e13d2626afeb8631bed868f28a35915dbc76cab24fce9bf9bd8fd65d398a184068ca74dba10b85edefeb67b1ca7bce7ed836f74b9bf340dd96cfda0d1d7d82dad9dac72cdb539da9835ccf44bcff1b55746cd8347e739e2e65a7d1d652462a9a16b70a3df6de7652ed099763a5f40cfe336dff9b6d9493fc2ff841ba16bf88c186eae8163486dab61da6597bdf1dd6a180bfc05edc67da7e8a37136276dc8ff3cfadf1e280cea86d32f0ba867cb0ff0037380a1fd91df59a32b01e5b58931efc08ac77477dfa16ebaa256047fe5db78934a036080419d8077bf3453fe5a30f4b6fe5db1beabaf256a6afcf782bd3ccdd90fe7e4b3334c58c28eaabf78bdc0cfbbc952962f8256fe5a2b927bc95a961afb7323dba1d957ec53435ba195ed343a2df5b991eddd265d6aaa7fddecaa7b25ebc952fdeca176fe58bb7f2c55bf9e2ad7cf156be782b5fbc952fdeca176fe58bb7f2c55bf9e2ad7cf156be782b5fbc952fdeca176fe58bb7f2c55bf9e2ad7cf156fefbbd958f4e42fe4e9fe518f0280b4b3e10bac2a69ae20666f4e4a8ca3410206a330fe7cb0b42e00b1f463367b79a2cf9d0060d610316811906707e7a8dcf2021dfbba64c13f051b1c3c2cf09e14bf8f1bbc043d4f8e10ef95372052e0de193792d3178e95de05789166a81e9b119f86f15651f464289d99383bcc2444fa45c13d10dfa477ed3805180f3fe75271d61f97bfca63fe5871d0699c933b9c5ddc7955f229c2386805991de0527aed338741bcc0e22cf6b39837c670527ae7c2a05eeded32866af53e656974b7a8ca8a75c68e481b6100ef6216dfa2ce62629bd6bebfa0c1bb5afd526380b7749d51bbd1914b1d328e6cda0c8bd31f1772dac88c8b4703b73aa2eb3d59ff65969d2f17fe9c84f89833a73d639ceaca3f43593ebb285a28897fd2931fa707387a69cc15e36f033457476d713c699a54601a1893ec2a783af58891728ff6a5c45eb3687eaccbdfcb3c2710e32685281afad49d7e4403e59a3c416293292ebed3a1cd32f70930fc8dc329ed665b4f133c8e7d81b35fc0bf6b10a98fbb059c769accbf15f03ff8db1f88d770cd792cdc2cfda90a54c0da5dccc59d70cd78e454f136bb202ccab6be6e05b3f0d2aec51a75e03f91fb3992a67c1ec635e826f522af087c0f0dcc4881689c64b0b55b94f66cb76b948a7f0690afee356def2eb6b7e778e0e940ad1d9bd639cfc0b3d4d340add0471c4dfaff0c90cc7f49cbb0f2d6f54f9f95672237e85678dbf1a7f0418abd9a9fc9f6d371e2f5b13e2465437ba147e1d7afeff5b1b53c0cdaba50f26bf4f85899a708e5fe79910b3d637a5de992c023c4ea7860f636db450151cdbe02be35439d21fcf661844c81f16f019cdb6407da06b3d24874887cf83066e8e733d90035d1ebdebf22a7891c7fbd3be325fa4e7a4a3cf39364598319e74756a1dcb24795ebec5bdf3c4ed91dfe0ac450f3fe9945bf8d0e67bc7f27e69bcfdaf97bb57a5f3aef157cc0b7be7437e9c6edb3be0dc55ca71d420dd2b18efda9b975fb826b576a6741afd70e2fe3ccdbfc297e01de627839708b875e4a339b7b79c33fc3e398f4570a384956aca6a6778bfc8f7c6df11b6b3274febafcbcf4fcb45abdd27cb803838aa22a5d6433ceb7bff4b340bf7a731c69ffdfbe458a9ff588cc1fb783c75b1a18d77f12c4437770c67e083c5ef6727da04637e56d9233cc688f3c5b727b1ca352e1dc5ea9971be33ab6cbb7d69db254d5a35ecbab3f3851e3d21394665294fdb9750a29791146aa1945775286964e716335b76780dfee993d508e9e5c92ab7e475e7bd06fe4eaf738ea58a3ca5bd9c403ca9c03e3737c0dcc38db1dff3997c8dbfb65f67f23e0fac1cfce097d037d189e69cf957ca17c6894b37af98079f696397fe9f91c39ace84a385e3d4a4d6b3d379e1e6ba7d830e856ccd436433cc35c58fa7919a9cfe3e087f4957fd0a5dcee828e0a592ef7bf9b9c6b6cc8c9b9a822738b31cdd32e7ccf27b4fe1846be157f5ed2ff6e1f4bb32fe11a27dc7bfb2273fcca7c83fa0dd6f5857bd54369773caee3e5926d65b2037951f695f3e61a26e6b9ff6e24640b057ab5827657a6bdd4d6e75f950c73d1105afe12bd05f0fc722bf8339773427543e31334ea366a7bfef6092fbf234f4c1676cd1beb9b6d27f1983db3afb85710df6c26bd9576447e723c50c412761ff8bafd8cc7f713bbbcf1fe9d057f16b3af4d36decea827a2cfe34f311077e43665efb0d1dcb36f69ffe85b94dbecc6dff1973db04f969a5476b52bfb48ff6ff81731be8bea7ad047b4ffc1afba0fdbdf3d7d9e7ee1e40a9bbd650fe3a9e9244f48b6bfe0fdad895997a7f0fe885f491f214831f0fe820ceebac5bcbf967f2ebfba8f27abc78e1dcd22feb27dea3fe01b134bafefc8d76a2fd04f0e1b1e4f556a482adc6dd33cf621133127ca67579fcfe12a2338d3374c27e5f0f04f63d5dc30d8e89aa3c9de757337e848c6fba94c7fba37912f88af7368b9b2f97486fe03ec25a610febe60fdbc9c1b946110b06f217671cb0770bfc627726c45028fc6391bd50fa549eebb7e9b5f75e0c79bc9769ec93fcebfb0c95fc94f42ce3becc38ad595f477fe0b55af55cec1dc39c6828e53951169893a5a3cb431c8387c921ce492376846f500b12c596b9ffcbf691b15c8eaaf84133f136efdf4ff6d13cf8f4903aba981e8407bc2f56f11ffcba572856cf8f9c4d042e658487e3b382bf708fb971aed3b08dcfd9a66d3d74d47799f2374f12d1e42955c4c16b97ff355df5b93db0d6dc7c44d395013457c4d16311c3698de6a8afccd567f61dcedb5c67f78ad8cfd21adb33a3774d59217d2252d208ecf339d7c787c02afd27bf44ebbfb4fd7be75964dd327ea0c1c1deae3f6be5f9ca7e67b18f980a1f8fcf0f75ecf3afe8d8bf701fb35f56712c2fd1094d98afa84360f1ccf6ff25feb5dad5d12baf986f2774443deebeb8bf78ae1f5dddf697ef0d72ad7814da724d3e09bc0be792b85f101f6111405c57d3db17f3ce24700db911efcb637d83aa62561030c708fcd8d7205636fd54c6e280385f555cedea4c74dfb33fd91b43c077aabd8971c0428cb899781f239f63651a42ec739b268ee2597ccd561901ffd01c368d501fb7c82e51b2b92e8ffc1f38ee30f8509b13b855dd42365a9747682e1cdfc2d9072aabf4f72e6dc233f31dec2fa53d3af167531f9ed9afa8f6bf3f23777d360b7cf3d5b57d1f8e02c74c6cef1d9dfeae3987ce3525a8628c35e66e487f5495956be11803d0d6fae67bc2131a3170faebe98c9fd33aa338dbc6378a633d8a78cd797df93b3206fb57786efaba8e94b6a58efcf3e7dea50ffde1bd8ec9f0e13c5cea331cdbeeefb42340f77f711dd9f83360ac366c84f699131adb8db886fbf3633c58bca9f2c1357df45d2a8c57aef61e3b82f7e8021605e2dd143c595f835d6e03f682d330ed3eb2016a5d0c32f595bda68a561fdb08e51af4d45e56c59f466c945e1d59c6a0d5cb7d59b1d417a372cd55c929d86c102fc4a054bc4609084d063e3c393a258dd05a05dd33312ce24216badc31a8e94fc0bf99fb2f9c57f1a55ef063cbbbfff9013d20ee51191fa77f9ee8e8b086de41719a8ec67277cc1fed5977f283ddd0bff7fdb1dd53c90cc46febec45f4da39edf8191fec6ff5e8dfd151dc28ceeb9f334accc7acd8374bfaf75d6bbb17eb9b43393f80cce058785e59561983a6afbea65ec4f29622fa75d6bc9f1a47650cb2dceda1817faedce6f8296d9cd6da45982c02ab8c973be9965dc73d456b7c910dcd90c9902e69ddb132de6a1c8bed32c6c371c2e06cb78c3d856d8493b175dbeba989dab727d5c2e6ccb9e29cabe2ebe93da87fdf583971466484123d6bd3b26513cc96e779f54979da1ec709ab62a6c5a0c766a27f725df78bf2dd237f60c767817d4a76bf28f33dfd84f56b2bad8cdf344372e8cffa75507b3e39b51fdad5415cd4caf3ef8f9983e26e7c29584ee38b324a0e49124415258722ce44c9195edf8d88ef3737b7c490a099af46c9a186a3be2839e4eded97a2e414cd3d1125e7b63748ce9020a9329ccdf52d7dcd10d4a92039438224caac5547fb83e49cca7a0992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992730992f3b707c9699c80fcedd17100b94169808a87dbd772d6d39415adc952798b5c06680963f2542007266c6ed0ab9d4a31a9c04f4914cd267a821b7a8aa820ca93a351d25653a670539c5f44c6998eaaef7232d2e461a651b78e164d7746711256ddfe00a780e664bab3f8db2d17b1ae4a652ea0b8e09415a1488232ad4224b423d744f589a7a42c0293689f7672e2b44d2fcadaa9fdf4ea4692f1ab134f5168df8c715427be9d11de39bea7c1ad7c13c99bc30d5b221319d462072811fb283a4db7fee28650c1e9dcd4e5f8e836287c53fabb08b7e4d153a2ba45d089a7f854ba7dab069ce696b71811986663b879c405cfbeeead5f482ecab2e1b694b25e407d754e15d1cd5bd309ebd537e31df309df8453c913a0f4e6353f99f256a2eaa6169f0c4c7a01efdeab5bbcd0293446274aabddba90154705d908a5bc7df28ff3892c61e4ecde0c1902d19e633d4bd6429079812feb58e31b739bb726926b832ce4b6bc19715a468a821beea2661d3e3ae1071a800cb76ea19ca4334e06148ae33c37905cdc32a84ec3218d7302867382aca0a106b7bdecd5a8538f92419e0e9d8a9be30a1e05d0feaadd886e4a8b57d3fa249ba86ee86ab67705e3522afa7d8ca628fb8b91131fe983f2b6aee6b76b2d37287289f89197b7789572b1286f7e29e5261578e2246d5b9e3a4e0048ccac85ec71fc467fd1c9fd1468d091f5e6ade6c5d869d0e03c0da1cc84f99b4ed93f7bb6de73a27e3da43f71a04e307743f28e1e7da7a9db9b1b6ac8305fbd76e696fe2b0ed4516bbf749e4ea03cc5c937438d86343d226efacfd3098269dc4f83fbd97f9e7e2aebe53cfd729e7e394fff3f719e7e394fbf9ca75fced32fe7e997f3f4cb79fae53cfd729e7e394fbf9ca75fced32fe7e997f3f4cb79fae53cfd729e7e394fbf9ca75fced3ff5f384fffffe9143d79b7e511a12a8ef32cde7bf8f4fa5d784b67dd13eceabe1685647eece18e11b8c7c471e63e3e8d452750adbb47985719bfe3eeabd357f42d9cb24f48a68ec79aeccc09baa3c6d128e6b5bef3e5fedca9ef0d9c5c9d3a712d4ec9ca362e76864c42cc2b38e102bf6b28b33ab52d4fa72b2480d83c49251c1bda9c130ec483d7b9fbed3a44f7e8509a5cfaae4e039392722b947c6152b769cafba9c0adb6f8649979558ab8bb531ee295ec9daaff0a9cce16ef715b6edaa77227eb476d44a7a71ef251c7e94b281bdf1770efebe2de99f243145b661e21bac389bfaf2b24c465adf801f150503bc46995f663dfa6d1e9d3c7761b01c981e2ca613feaf2a4b62aa3e1636dd3190dc806811b3da9f23430906ca0bc2d1acd0314cf8179edf007fb24ffb064d2d39429f3fa9276500e5027d07a59c41976fee7c353cee244ccee3f12eb7a93d65f56efcf9d63d56757853ac3aea0f888b27d76d574fd2c72777461716c52abcaff0cedf9c7ff070000ffff03004bba585f9a180100`)))
@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+	"github.com/gocomply/metaschema/metaschema/protobuf"
+)
+
+// GenerateProto writes <package>.proto file of the metaschema, its lock file
+// and go conversions between the models and the protocol buffers messages.
+// The conversions are written into <package>/<package>pbconv, as they refer
+// to <package>/<package>pb generated by protoc-gen-go afterwards.
+func GenerateProto(metaschema *parser.Metaschema, baseDir string) error {
+	pkgDir, err := ensurePkgDir(metaschema, baseDir)
+	if err != nil {
+		return err
+	}
+	convDir := filepath.Join(pkgDir, metaschema.GoPackageName()+"pbconv")
+	if err := os.MkdirAll(convDir, os.FileMode(0722)); err != nil {
+		return err
+	}
+	lockPath := filepath.Join(baseDir, protobuf.LockFileName(metaschema))
+	lock, err := protobuf.ReadLock(lockPath)
+	if err != nil {
+		return err
+	}
+	schema, err := protobuf.Generate(metaschema, lock, baseDir)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := executeProtoTemplate(&buf, "proto", schema); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, protobuf.FileName(metaschema)), buf.Bytes(), os.FileMode(0644)); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	if err := executeProtoTemplate(&buf, "generated_proto", schema); err != nil {
+		return err
+	}
+	p, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.New(err.Error() + " in following file:\n" + buf.String())
+	}
+	if err := os.WriteFile(filepath.Join(convDir, "generated_proto.go"), p, os.FileMode(0644)); err != nil {
+		return err
+	}
+	return lock.Write(lockPath)
+}
+
+func executeProtoTemplate(buf *bytes.Buffer, templateName string, schema *protobuf.Schema) error {
+	text, err := readTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	t, err := template.New(templateName + ".tmpl").Parse(text)
+	if err != nil {
+		return err
+	}
+	return t.Execute(buf, schema)
+}
@@ -9,62 +9,124 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/gocomply/metaschema/metaschema/parser"
 	"github.com/markbates/pkger"
 )
 
+// GenerateModules generates go code of the modules concurrently. Nothing is
+// written unless code of all the modules is rendered, errors of all the
+// modules are returned joined.
+func GenerateModules(metaschemas []*parser.Metaschema, baseDir string) error {
+	files := make([][]generatedFile, len(metaschemas))
+	errs := make([]error, len(metaschemas))
+	var wg sync.WaitGroup
+	for i, meta := range metaschemas {
+		wg.Add(1)
+		go func(i int, meta *parser.Metaschema) {
+			defer wg.Done()
+			var err error
+			files[i], err = renderModule(meta, baseDir)
+			var d *parser.Diagnostic
+			if errors.As(err, &d) {
+				// diagnostics point at the metaschema source already
+				errs[i] = d
+			} else if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", meta.GoPackageName(), err)
+			}
+		}(i, meta)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := writeFiles(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GenerateAll(metaschema *parser.Metaschema, baseDir string) error {
-	pkgDir, err := ensurePkgDir(metaschema, baseDir)
+	files, err := renderModule(metaschema, baseDir)
 	if err != nil {
 		return err
 	}
+	return writeFiles(files)
+}
+
+// generatedFile is formatted go source to be written to path
+type generatedFile struct {
+	path   string
+	source []byte
+}
+
+func renderModule(metaschema *parser.Metaschema, baseDir string) ([]generatedFile, error) {
+	pkgDir := filepath.Join(baseDir, filepath.Clean(metaschema.GoPackageName()))
 	templates := []string{"generated_models"}
 	if len(metaschema.Multiplexers) > 0 {
 		templates = append(templates, "generated_multiplexers")
 	}
 
+	var files []generatedFile
 	for _, templateName := range templates {
 		t, err := newTemplate(baseDir, templateName)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		err = executeTemplate(t, metaschema, fmt.Sprintf("%s/%s.go", pkgDir, templateName))
+		source, err := executeTemplate(t, metaschema)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files = append(files, generatedFile{path: fmt.Sprintf("%s/%s.go", pkgDir, templateName), source: source})
 	}
-	return nil
+	return files, nil
 }
 
-func executeTemplate(t *template.Template, metaschema *parser.Metaschema, filename string) error {
-	f, err := os.Create(filename) // #nosec G304
-	if err != nil {
-		return err
+func writeFiles(files []generatedFile) error {
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), os.FileMode(0722)); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, f.source, os.FileMode(0644)); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+func executeTemplate(t *template.Template, metaschema *parser.Metaschema) ([]byte, error) {
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, metaschema); err != nil {
-		return err
+		return nil, err
 	}
 
 	p, err := format.Source(buf.Bytes())
 	if err != nil {
-		return errors.New(err.Error() + " in following file:\n" + buf.String())
+		return nil, errors.New(err.Error() + " in following file:\n" + buf.String())
 	}
-
-	_, err = f.Write(p)
-	return err
+	return p, nil
 }
 
 func newTemplate(baseDir, templateName string) (*template.Template, error) {
 	getImports := func(metaschema parser.Metaschema) string {
 		var imports strings.Builder
 		imports.WriteString("import (\n")
-		if metaschema.ContainsRootElement() {
+		if metaschema.UsesXml() {
 			imports.WriteString("\t\"encoding/xml\"\n")
 		}
+		if metaschema.UsesDatatype() {
+			imports.WriteString("\n\t\"github.com/gocomply/metaschema/metaschema/datatype\"\n")
+		}
+		if metaschema.UsesMarkup() {
+			imports.WriteString("\n\t\"github.com/gocomply/metaschema/metaschema/markup\"\n")
+		}
+		if metaschema.GeneratesValidation() {
+			imports.WriteString("\n\t\"github.com/gocomply/metaschema/metaschema/validation\"\n")
+		}
 
 		for _, im := range metaschema.ImportedDependencies() {
 			imports.WriteString(fmt.Sprintf("\n\t\"%s/%s/%s\"\n", metaschema.GoMod, baseDir, im.GoPackageName()))
@@ -101,4 +163,8 @@ func noop() { //nolint:golint,unused
 	// Hint pkger tool to bundle these files
 	pkger.Include("/metaschema/templates/generated_models.tmpl")       // nolint:staticcheck
 	pkger.Include("/metaschema/templates/generated_multiplexers.tmpl") // nolint:staticcheck
+	pkger.Include("/metaschema/templates/docs_markdown.tmpl")          // nolint:staticcheck
+	pkger.Include("/metaschema/templates/docs_html.tmpl")              // nolint:staticcheck
+	pkger.Include("/metaschema/templates/proto.tmpl")                  // nolint:staticcheck
+	pkger.Include("/metaschema/templates/generated_proto.tmpl")        // nolint:staticcheck
 }
@@ -0,0 +1,40 @@
+package library
+
+import "testing"
+
+func TestChoice(t *testing.T) {
+	tests := []struct {
+		name string
+		book Book
+		err  string
+	}{
+		{"isbn", Book{Isbn: "978-3-16-148410-0"}, ""},
+		{"issn", Book{Issn: "2049-3630"}, ""},
+		{"both", Book{Isbn: "978-3-16-148410-0", Issn: "2049-3630"}, "/book: exactly one of isbn, issn must be set, found 2"},
+		{"none", Book{}, "/book: exactly one of isbn, issn must be set, found 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.book.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("valid book rejected: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+// TestChoiceOfMember checks that choices of the books are validated along
+// with the library and reported at the path of the book
+func TestChoiceOfMember(t *testing.T) {
+	library := Library{Id: "main", Shelves: ShelfMultiplexer{"a"}, Books: BookMultiplexer{{Isbn: "978-3-16-148410-0"}, {}}}
+	want := "/library/book[2]: exactly one of isbn, issn must be set, found 0"
+	if err := library.Validate(); err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
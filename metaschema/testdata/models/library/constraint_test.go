@@ -0,0 +1,35 @@
+package library
+
+import "testing"
+
+// TestConstraints validates books against rules of the book definition, some
+// of them targeting its flags, and of the definitions of its fields
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		book Book
+		err  string
+	}{
+		{"valid", Book{Id: "b1", Format: BookFormatPrint, Isbn: "978-3-16"}, ""},
+		{"id not matching", Book{Id: "x1", Isbn: "978-3-16"}, "/book/@id: value 'x1' does not match pattern 'b[0-9]+'"},
+		{"id matching in part", Book{Id: "b1x", Isbn: "978-3-16"}, "/book/@id: value 'b1x' does not match pattern 'b[0-9]+'"},
+		{"format not allowed", Book{Format: "audio", Isbn: "978-3-16"}, "/book/@format: value 'audio' is not one of allowed values: print, ebook"},
+		{"isbn not matching", Book{Isbn: "ISBN 978"}, "/book/isbn: value 'ISBN 978' does not match pattern '[0-9]{3}-[0-9-]+'"},
+		// rules of lower level than error are left to the validator
+		{"issn warning", Book{Issn: "x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.book.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("valid book rejected: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
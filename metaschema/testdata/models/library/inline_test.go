@@ -0,0 +1,48 @@
+package library
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+// TestInlineDefinitions decodes members defined inline, their types are named
+// after the definitions containing them
+func TestInlineDefinitions(t *testing.T) {
+	want := BookMultiplexer{{
+		Isbn: "978-3-16-148410-0",
+		Reviews: BookReviewMultiplexer{{
+			Reviewer: "ann",
+			Verdicts: BookReviewVerdictMultiplexer{{Lang: "en", Value: "Good"}, {Value: "Gut"}},
+		}},
+	}}
+	tests := []struct {
+		name      string
+		document  string
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"xml", `<library xmlns="http://example.com/ns/library">
+  <book>
+    <isbn>978-3-16-148410-0</isbn>
+    <review reviewer="ann">
+      <verdict lang="en">Good</verdict>
+      <verdict>Gut</verdict>
+    </review>
+  </book>
+</library>`, xml.Unmarshal},
+		{"json", `{"books": [{"isbn": "978-3-16-148410-0", "reviews": [{"reviewer": "ann",
+  "verdicts": [{"lang": "en", "value": "Good"}, {"value": "Gut"}]}]}]}`, json.Unmarshal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var library Library
+			if err := tt.unmarshal([]byte(tt.document), &library); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(library.Books, want) {
+				t.Errorf("got %+v, want %+v", library.Books, want)
+			}
+		})
+	}
+}
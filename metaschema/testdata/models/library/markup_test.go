@@ -0,0 +1,75 @@
+package library
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const markupLibrary = `<library xmlns="http://example.com/ns/library" id="main">
+  <title>The <em>city</em> &amp; <code>county</code> library</title>
+  <shelf>a</shelf>
+  <book id="b1">
+    <isbn>978-3-16</isbn>
+    <review reviewer="ann">
+      <verdict>Good</verdict>
+      <p>Worth <strong>reading</strong>, see <a href="https://example.com/b1">notes</a>.</p>
+      <ul><li>plot</li><li>style</li></ul>
+    </review>
+  </book>
+  <note lang="en"><p>Open <q>daily</q> with <insert type="param" id-ref="hours"/>.</p></note>
+</library>`
+
+// TestMarkup decodes markup kept as XHTML from XML, writes it as Markdown to
+// JSON and back to XML
+func TestMarkup(t *testing.T) {
+	var library Library
+	if err := xml.Unmarshal([]byte(markupLibrary), &library); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := library.Title.XHTML(), "The <em>city</em> &amp; <code>county</code> library"; got != want {
+		t.Errorf("got title %s, want %s", got, want)
+	}
+	if got, want := library.Title.Text(), "The city & county library"; got != want {
+		t.Errorf("got text of title %s, want %s", got, want)
+	}
+	if got := library.Books[0].Reviews[0].Summary; got == nil || !strings.Contains(got.XHTML(), "<li>style</li>") {
+		t.Errorf("got summary %v", got)
+	}
+	if got := library.Notes[0]; got.Lang != "en" || !strings.Contains(got.Value.XHTML(), `<insert type="param" id-ref="hours"/>`) {
+		t.Errorf("got note %+v", got)
+	}
+
+	js, err := json.Marshal(&library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"title":"The *city* \u0026 ` + "`county`" + ` library"`,
+		`"summary":"Worth **reading**, see [notes](https://example.com/b1).\n\n- plot\n- style"`,
+		`"notes":[{"lang":"en","value":"Open \"daily\" with {{ insert: param, hours }}."}]`,
+	} {
+		if !strings.Contains(string(js), want) {
+			t.Errorf("JSON is missing %s:\n%s", want, js)
+		}
+	}
+
+	var decoded Library
+	if err := json.Unmarshal(js, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<title>The <em>city</em> &amp; <code>county</code> library</title>`,
+		`<verdict>Good</verdict><p>Worth <strong>reading</strong>, see <a href="https://example.com/b1">notes</a>.</p><ul>`,
+		`<note lang="en"><p>Open <q>daily</q> with <insert type="param" id-ref="hours"/>.</p></note>`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("XML is missing %s:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package library
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	if XMLNamespace != "http://example.com/ns/library" || JSONBaseURI != "http://example.com/ns/library" {
+		t.Errorf("got namespace %s and base URI %s", XMLNamespace, JSONBaseURI)
+	}
+
+	// the field of the imported module keeps namespace of that module
+	out, err := xml.Marshal(&Library{Contact: "desk@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<library xmlns="http://example.com/ns/library"><contact xmlns="http://example.com/ns/shared">desk@example.com</contact></library>`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		contact  Contact
+		err      string
+	}{
+		{"own namespaces", want, "desk@example.com", ""},
+		{"prefixed", `<l:library xmlns:l="http://example.com/ns/library" xmlns:s="http://example.com/ns/shared"><s:contact>desk@example.com</s:contact></l:library>`, "desk@example.com", ""},
+		// elements of other namespace are not members of the model
+		{"contact of library namespace", `<library xmlns="http://example.com/ns/library"><contact>desk@example.com</contact></library>`, "", ""},
+		{"library of other namespace", `<library xmlns="http://example.com/ns/other"/>`, "", "expected element <library> in name space http://example.com/ns/library"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var library Library
+			err := xml.Unmarshal([]byte(tt.document), &library)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if library.Contact != tt.contact {
+				t.Errorf("got contact %q, want %q", library.Contact, tt.contact)
+			}
+		})
+	}
+}
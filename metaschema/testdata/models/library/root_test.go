@@ -0,0 +1,29 @@
+package library
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestRootElement(t *testing.T) {
+	out, err := xml.Marshal(&Library{Books: BookMultiplexer{{Issn: "2049-3630"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<library xmlns="http://example.com/ns/library"><book><issn>2049-3630</issn></book></library>`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	var library Library
+	if err := xml.Unmarshal(out, &library); err != nil {
+		t.Fatal(err)
+	}
+	if library.XMLName.Local != "library" || len(library.Books) != 1 {
+		t.Errorf("decoded %+v", library)
+	}
+	if err := xml.Unmarshal([]byte(`<catalog xmlns="http://example.com/ns/library"/>`), &library); err == nil || !strings.Contains(err.Error(), "expected element type <library>") {
+		t.Errorf("got error %v, want rejection of other root element", err)
+	}
+}
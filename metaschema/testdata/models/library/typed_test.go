@@ -0,0 +1,103 @@
+package library
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestTypedValues decodes flags and fields of numeric, date and boolean types
+// into datatype wrappers of their values
+func TestTypedValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  string
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"xml", `<library xmlns="http://example.com/ns/library" id="main">
+  <shelf>a</shelf>
+  <book id="b1" pages="320">
+    <isbn>978-3-16</isbn>
+    <published>2020-05-17</published>
+    <available copies="3">true</available>
+  </book>
+</library>`, xml.Unmarshal},
+		{"json", `{"id": "main", "shelves": ["a"], "books": [{"id": "b1", "pages": 320, "isbn": "978-3-16",
+  "published": "2020-05-17", "available": {"copies": 3, "value": true}}]}`, json.Unmarshal},
+		{"yaml", `id: main
+shelves: [a]
+books:
+  - id: b1
+    pages: 320
+    isbn: 978-3-16
+    published: "2020-05-17"
+    available: {copies: 3, value: true}
+`, yaml.Unmarshal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var library Library
+			if err := tt.unmarshal([]byte(tt.document), &library); err != nil {
+				t.Fatal(err)
+			}
+			book := library.Books[0]
+			if book.Pages == nil || *book.Pages != 320 {
+				t.Errorf("got pages %v", book.Pages)
+			}
+			if book.Published == nil || book.Published.Year() != 2020 || book.Published.Month() != 5 || book.Published.Day() != 17 || book.Published.String() != "2020-05-17" {
+				t.Errorf("got published %v", book.Published)
+			}
+			if book.Available == nil || !bool(book.Available.Value) || book.Available.Copies == nil || *book.Available.Copies != 3 {
+				t.Errorf("got available %+v", book.Available)
+			}
+			if err := library.Validate(); err != nil {
+				t.Error(err)
+			}
+
+			js, err := json.Marshal(&book)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{`"pages":320`, `"published":"2020-05-17"`, `"available":{"copies":3,"value":true}`} {
+				if !strings.Contains(string(js), want) {
+					t.Errorf("JSON is missing %s:\n%s", want, js)
+				}
+			}
+			out, err := xml.Marshal(&library)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{`<book id="b1" pages="320">`, `<published>2020-05-17</published>`, `<available copies="3">true</available>`} {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("XML is missing %s:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidTypedValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  string
+		unmarshal func([]byte, interface{}) error
+		err       string
+	}{
+		{"xml pages", `<library xmlns="http://example.com/ns/library"><book pages="0"/></library>`, xml.Unmarshal, "0 is out of range"},
+		{"xml date", `<library xmlns="http://example.com/ns/library"><book><published>17.5.2020</published></book></library>`, xml.Unmarshal, "'17.5.2020' is not a valid date"},
+		{"xml boolean", `<library xmlns="http://example.com/ns/library"><book><available>yes</available></book></library>`, xml.Unmarshal, "'yes' is not a valid boolean"},
+		{"json pages as string", `{"books": [{"pages": "320"}]}`, json.Unmarshal, `'"320"' is not a valid integer`},
+		{"json copies", `{"books": [{"available": {"copies": -1, "value": true}}]}`, json.Unmarshal, "-1 is out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var library Library
+			if err := tt.unmarshal([]byte(tt.document), &library); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
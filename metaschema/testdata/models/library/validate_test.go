@@ -0,0 +1,50 @@
+package library
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestValidate checks that all the violations found within the library are
+// collected, each at the path of the offending node
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		library Library
+		want    []string
+	}{
+		{"valid", Library{Id: "main", Shelves: ShelfMultiplexer{"a", "b"}, Books: BookMultiplexer{
+			{Id: "b1", Isbn: "978-3-16", Reviews: BookReviewMultiplexer{{Reviewer: "ann"}}},
+		}}, nil},
+		{"missing flag and field", Library{}, []string{
+			"/library/@id: required flag is missing",
+			"/library: required <shelf> is missing",
+		}},
+		{"too many", Library{Id: "main", Shelves: ShelfMultiplexer{"a", "b", "c"}}, []string{
+			"/library: expected at most 2 <shelf>, found 3",
+		}},
+		{"invalid datatype", Library{Id: "1st", Shelves: ShelfMultiplexer{"a"}}, []string{
+			"/library/@id: '1st' is not a valid NCName",
+		}},
+		{"nested", Library{Id: "main", Shelves: ShelfMultiplexer{"a"}, Books: BookMultiplexer{
+			{Id: "b1", Isbn: "978-3-16"},
+			{Id: "b 2", Issn: "2049-3630", Reviews: BookReviewMultiplexer{{Reviewer: "ann"}, {}}},
+		}}, []string{
+			"/library/book[2]/@id: 'b 2' is not a valid NCName",
+			"/library/book[2]/@id: value 'b 2' does not match pattern 'b[0-9]+'",
+			"/library/book[2]/review[2]/@reviewer: required flag is missing",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			if err := tt.library.Validate(); err != nil {
+				got = strings.Split(err.Error(), "\n")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package library
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const yamlLibrary = `id: main
+title: The *city* library
+shelves:
+  - a
+books:
+  - id: b1
+    pages: 320
+    isbn: 978-3-16
+    published: "2020-05-17"
+    available:
+      copies: 3
+      value: true
+    reviews:
+      reviewer: ann
+      verdicts:
+        - lang: en
+          value: Good
+      summary: Worth **reading**.
+  - id: b2
+    issn: 2049-3630
+    reviews:
+      - reviewer: bob
+      - reviewer: eve
+notes:
+  - lang: en
+    value: Open daily.
+`
+
+// TestYAML decodes the document written in YAML the same way as in JSON, the
+// single review is accepted in place of the array of reviews
+func TestYAML(t *testing.T) {
+	var fromYAML Library
+	if err := yaml.Unmarshal([]byte(yamlLibrary), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if len(fromYAML.Books) != 2 || len(fromYAML.Books[0].Reviews) != 1 || len(fromYAML.Books[1].Reviews) != 2 {
+		t.Fatalf("got books %+v", fromYAML.Books)
+	}
+	if got := fromYAML.Books[0].Reviews[0].Summary; got == nil || got.XHTML() != "<p>Worth <strong>reading</strong>.</p>" {
+		t.Errorf("got summary %v", got)
+	}
+	if err := fromYAML.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	js, err := json.Marshal(&fromYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON Library
+	if err := json.Unmarshal(js, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("JSON decoded as %+v, YAML as %+v", fromJSON, fromYAML)
+	}
+
+	out, err := yaml.Marshal(&fromYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reviews are written as array, even the single one
+	for _, want := range []string{
+		`\btitle: The \*city\* library\n`,
+		`\bpages: 320\n`,
+		`\bpublished: "2020-05-17"\n`,
+		`\breviews:\n +- reviewer: ann\n`,
+		`\bsummary: Worth \*\*reading\*\*\.\n`,
+	} {
+		if !regexp.MustCompile(want).Match(out) {
+			t.Errorf("YAML is missing %s:\n%s", want, out)
+		}
+	}
+	var roundTrip Library
+	if err := yaml.Unmarshal(out, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, fromYAML) {
+		t.Errorf("got %+v after round trip, want %+v", roundTrip, fromYAML)
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEnumJsonKey(t *testing.T) {
+	documents := []codec{
+		{"json", `{"params":{"size":{"unit":"cm","value":"5"}},"tags":{"owner":"alice","team":"blue"}}`, json.Unmarshal, json.Marshal},
+		{"yaml", "params:\n  size:\n    unit: cm\n    value: \"5\"\ntags:\n  owner: alice\n  team: blue\n", yaml.Unmarshal, yaml.Marshal},
+	}
+	want := Catalog{
+		Params: ParamMultiplexer{{Name: ParamNameSize, Unit: "cm", Value: "5"}},
+		Tags:   TagMultiplexer{{Name: TagNameOwner, Value: "alice"}, {Name: "team", Value: "blue"}},
+	}
+	for _, c := range documents {
+		t.Run(c.name, func(t *testing.T) {
+			var catalog Catalog
+			if err := c.unmarshal([]byte(c.document), &catalog); err != nil {
+				t.Fatal(err)
+			}
+			sortTags(catalog.Tags)
+			if !reflect.DeepEqual(catalog, want) {
+				t.Fatalf("got %+v, want %+v", catalog, want)
+			}
+			data, err := c.marshal(&catalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again Catalog
+			if err := c.unmarshal(data, &again); err != nil {
+				t.Fatal(err)
+			}
+			sortTags(again.Tags)
+			if !reflect.DeepEqual(again, want) {
+				t.Errorf("round trip: got %+v, want %+v", again, want)
+			}
+		})
+	}
+}
+
+func TestEnumJsonKeyRejected(t *testing.T) {
+	documents := []codec{
+		{"json", `{"params":{"weight":{"value":"5"}}}`, json.Unmarshal, json.Marshal},
+		{"yaml", "params:\n  weight:\n    value: \"5\"\n", yaml.Unmarshal, yaml.Marshal},
+	}
+	for _, c := range documents {
+		t.Run(c.name, func(t *testing.T) {
+			var catalog Catalog
+			err := c.unmarshal([]byte(c.document), &catalog)
+			if err == nil || !strings.Contains(err.Error(), "'weight' is not one of allowed values of ParamName") {
+				t.Fatalf("got error %v, want rejection of 'weight'", err)
+			}
+			catalog = Catalog{Params: ParamMultiplexer{{Name: "weight", Value: "5"}}}
+			if _, err := c.marshal(&catalog); err == nil {
+				t.Errorf("key 'weight' encoded")
+			}
+		})
+	}
+}
+
+// sortTags orders tags decoded from JSON object, whose keys come in random
+// order
+func sortTags(tags TagMultiplexer) {
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+}
+
+func TestDatatypeJsonKey(t *testing.T) {
+	documents := []codec{
+		{"json", `{"steps":{"1":{"actor":"user","value":"open"},"2":{"value":"close"}}}`, json.Unmarshal, json.Marshal},
+		{"yaml", "steps:\n  1:\n    actor: user\n    value: open\n  2:\n    value: close\n", yaml.Unmarshal, yaml.Marshal},
+	}
+	for _, c := range documents {
+		t.Run(c.name, func(t *testing.T) {
+			var catalog Catalog
+			if err := c.unmarshal([]byte(c.document), &catalog); err != nil {
+				t.Fatal(err)
+			}
+			data, err := c.marshal(&catalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again Catalog
+			if err := c.unmarshal(data, &again); err != nil {
+				t.Fatal(err)
+			}
+			for _, catalog := range []Catalog{catalog, again} {
+				steps := map[uint64]Step{}
+				for _, step := range catalog.Steps {
+					if step.Position == nil {
+						t.Fatalf("step %+v has no position", step)
+					}
+					steps[uint64(*step.Position)] = step
+				}
+				if len(steps) != 2 || steps[1].Actor != "user" || steps[1].Value != "open" || steps[2].Value != "close" {
+					t.Errorf("got steps %+v of %s", catalog.Steps, data)
+				}
+			}
+		})
+	}
+
+	var catalog Catalog
+	if err := json.Unmarshal([]byte(`{"steps":{"0":{"value":"open"}}}`), &catalog); err == nil {
+		t.Errorf("position 0 accepted as key")
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// codec decodes and encodes a document in one of the supported formats
+type codec struct {
+	name      string
+	document  string
+	unmarshal func([]byte, interface{}) error
+	marshal   func(interface{}) ([]byte, error)
+}
+
+func catalogCodecs(attrs map[string]string) []codec {
+	var xmlAttrs, jsonProps, yamlProps []string
+	for _, name := range []string{"kind", "status"} {
+		if value, ok := attrs[name]; ok {
+			xmlAttrs = append(xmlAttrs, " "+name+`="`+value+`"`)
+			jsonProps = append(jsonProps, `"`+name+`":"`+value+`"`)
+			yamlProps = append(yamlProps, name+": "+value+"\n")
+		}
+	}
+	return []codec{
+		{"xml", `<catalog xmlns="http://example.com/ns/models"` + strings.Join(xmlAttrs, "") + `></catalog>`, xml.Unmarshal, xml.Marshal},
+		{"json", "{" + strings.Join(jsonProps, ",") + "}", json.Unmarshal, json.Marshal},
+		{"yaml", strings.Join(yamlProps, ""), yaml.Unmarshal, yaml.Marshal},
+	}
+}
+
+func TestClosedEnum(t *testing.T) {
+	tests := []struct {
+		value string
+		want  CatalogKind
+		err   bool
+	}{
+		{"basic", CatalogKindBasic, false},
+		{"extended", CatalogKindExtended, false},
+		{"other", "", true},
+	}
+	for _, tt := range tests {
+		for _, c := range catalogCodecs(map[string]string{"kind": tt.value}) {
+			t.Run(c.name+"/"+tt.value, func(t *testing.T) {
+				var catalog Catalog
+				err := c.unmarshal([]byte(c.document), &catalog)
+				if tt.err {
+					if err == nil || !strings.Contains(err.Error(), "'other' is not one of allowed values of CatalogKind") {
+						t.Fatalf("got error %v, want rejection of 'other'", err)
+					}
+					if _, err := c.marshal(&Catalog{Kind: CatalogKind(tt.value)}); err == nil {
+						t.Errorf("value 'other' encoded")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if catalog.Kind != tt.want {
+					t.Fatalf("got %q, want %q", catalog.Kind, tt.want)
+				}
+				data, err := c.marshal(&catalog)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var again Catalog
+				if err := c.unmarshal(data, &again); err != nil {
+					t.Fatal(err)
+				}
+				if again.Kind != tt.want {
+					t.Errorf("round trip: got %q, want %q", again.Kind, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenEnum(t *testing.T) {
+	for _, value := range []string{"draft", "retired"} {
+		for _, c := range catalogCodecs(map[string]string{"status": value}) {
+			t.Run(c.name+"/"+value, func(t *testing.T) {
+				var catalog Catalog
+				if err := c.unmarshal([]byte(c.document), &catalog); err != nil {
+					t.Fatal(err)
+				}
+				if catalog.Status != CatalogStatus(value) {
+					t.Fatalf("got %q, want %q", catalog.Status, value)
+				}
+				data, err := c.marshal(&catalog)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var again Catalog
+				if err := c.unmarshal(data, &again); err != nil {
+					t.Fatal(err)
+				}
+				if again.Status != CatalogStatus(value) {
+					t.Errorf("round trip: got %q, want %q", again.Status, value)
+				}
+			})
+		}
+	}
+}
+
+func TestSharedEnum(t *testing.T) {
+	param := Param{Name: ParamNameSize, Level: LevelHigh, Priority: Priority("urgent")}
+	// flags referring to the same definition share its type
+	step := Step{Level: param.Level, Priority: param.Priority}
+	if step.Level != LevelHigh || step.Priority != "urgent" {
+		t.Fatalf("got %+v", step)
+	}
+
+	var catalog Catalog
+	err := json.Unmarshal([]byte(`{"steps":{"1":{"level":"low","priority":"later"}}}`), &catalog)
+	if err == nil || !strings.Contains(err.Error(), "'later' is not one of allowed values of Priority") {
+		t.Fatalf("got error %v, want rejection of 'later'", err)
+	}
+	err = json.Unmarshal([]byte(`{"params":{"size":{"level":"medium"}}}`), &catalog)
+	if err == nil || !strings.Contains(err.Error(), "'medium' is not one of allowed values of Level") {
+		t.Fatalf("got error %v, want rejection of 'medium'", err)
+	}
+}
@@ -0,0 +1,76 @@
+package metaschema
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gocomply/metaschema/metaschema/document"
+	"github.com/gocomply/metaschema/metaschema/parser"
+	"github.com/gocomply/metaschema/metaschema/validator"
+)
+
+// Load decodes all the metaschemas found in the directory
+func Load(metaschemaDir string) ([]*parser.Metaschema, error) {
+	return NewLoader("", io.Discard).LoadDir(metaschemaDir)
+}
+
+// LoadSchema decodes the metaschemas found in the directory into a schema of
+// instance documents
+func LoadSchema(metaschemaDir string) (*document.Schema, error) {
+	metaschemas, err := Load(metaschemaDir)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := document.NewSchema(metaschemas...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", metaschemaDir, err)
+	}
+	return schema, nil
+}
+
+// Validate checks the documents against the metaschemas found in the
+// directory. Format of each document is detected unless given explicitly.
+// Documents that cannot be read are reported by a syntax finding.
+func Validate(metaschemaDir string, documents []string, format string) ([]validator.Report, error) {
+	schema, err := LoadSchema(metaschemaDir)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]validator.Report, 0, len(documents))
+	for _, path := range documents {
+		report, err := validateDocument(schema, path, format)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func validateDocument(schema *document.Schema, path, format string) (validator.Report, error) {
+	report := validator.Report{Document: path}
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return report, err
+	}
+	f := document.DetectFormat(path, data)
+	if format != "" {
+		if f, err = document.ParseFormat(format); err != nil {
+			return report, err
+		}
+	}
+	report.Format = f.String()
+	doc, err := schema.Decode(data, f)
+	if err != nil {
+		report.Findings = []validator.Finding{{
+			Level:   parser.ConstraintLevelCritical,
+			Rule:    validator.RuleSyntax,
+			Path:    "/",
+			Message: err.Error(),
+		}}
+		return report, nil
+	}
+	report.Findings = validator.Validate(doc)
+	return report, nil
+}
@@ -0,0 +1,168 @@
+// Package validation holds helpers used by Validate methods of the generated
+// models.
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gocomply/metaschema/metaschema/datatype"
+)
+
+// Validator is implemented by every generated model type that carries
+// metaschema constraints
+type Validator interface {
+	// ValidatePath collects violations within the node located at given path
+	ValidatePath(path string) Violations
+}
+
+// Violation describes single node that does not conform to the metaschema
+type Violation struct {
+	// Path locates the offending node, for example /catalog/group[2]/@id
+	Path    string
+	Message string
+}
+
+func (v Violation) Error() string {
+	return v.Path + ": " + v.Message
+}
+
+// Violations is a list of all the violations found within a document. It
+// implements error interface.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, violation := range v {
+		msgs = append(msgs, violation.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Err returns nil when there are no violations, the violations otherwise
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Add records new violation at given path
+func (v *Violations) Add(path, format string, args ...interface{}) {
+	*v = append(*v, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Append records violations found within a child node
+func (v *Violations) Append(other Violations) {
+	*v = append(*v, other...)
+}
+
+// RequireFlag records violation when required flag is not set
+func (v *Violations) RequireFlag(path, name string, set bool) {
+	if !set {
+		v.Add(Flag(path, name), "required flag is missing")
+	}
+}
+
+// Cardinality records violation when number of occurrences of a model item is
+// out of bounds. Negative max stands for unbounded.
+func (v *Violations) Cardinality(path, name string, count, min, max int) {
+	if count < min {
+		if min == 1 && count == 0 {
+			v.Add(path, "required <%s> is missing", name)
+		} else {
+			v.Add(path, "expected at least %d <%s>, found %d", min, name, count)
+		}
+	}
+	if max >= 0 && count > max {
+		v.Add(path, "expected at most %d <%s>, found %d", max, name, count)
+	}
+}
+
+// Choice records violation when not exactly one member of a choice is set
+func (v *Violations) Choice(path, names string, set int) {
+	if set != 1 {
+		v.Add(path, "exactly one of %s must be set, found %d", names, set)
+	}
+}
+
+// AllowedValues records violation when the value is not one of the allowed
+func (v *Violations) AllowedValues(path, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Add(path, "value '%s' is not one of allowed values: %s", value, strings.Join(allowed, ", "))
+}
+
+// Enum returns error when non-empty value of the named enum type is not one of
+// the allowed values. Generated enum types of closed allowed-values lists call
+// it when marshalled or unmarshalled.
+func Enum(typeName, value string, allowed ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("value '%s' is not one of allowed values of %s: %s", value, typeName, strings.Join(allowed, ", "))
+}
+
+// Datatype records violation when the value is not a valid lexical
+// representation of the data type
+func (v *Violations) Datatype(path, name, value string) {
+	if err := datatype.Check(name, value); err != nil {
+		v.Add(path, "%s", err)
+	}
+}
+
+// Matches records violation when the value does not match the regular
+// expression. Like in XML Schema the expression has to match the whole value.
+func (v *Violations) Matches(path, value, pattern string) {
+	re, err := compile(pattern)
+	if err != nil {
+		v.Add(path, "cannot check pattern '%s': %s", pattern, err)
+		return
+	}
+	if !re.MatchString(value) {
+		v.Add(path, "value '%s' does not match pattern '%s'", value, pattern)
+	}
+}
+
+// Count returns 1 when the item is set, 0 otherwise
+func Count(set bool) int {
+	if set {
+		return 1
+	}
+	return 0
+}
+
+// Flag returns path of the named flag of the node at given path
+func Flag(path, name string) string {
+	return path + "/@" + name
+}
+
+// Index returns path of i-th (zero based) occurrence of the named child
+func Index(path, name string, i int) string {
+	return path + "/" + name + "[" + strconv.Itoa(i+1) + "]"
+}
+
+var patterns sync.Map
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
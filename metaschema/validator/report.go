@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Report holds findings of a single document
+type Report struct {
+	Document string    `json:"document"`
+	Format   string    `json:"format,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+var levelRank = map[parser.ConstraintLevel]int{
+	parser.ConstraintLevelDebug:         0,
+	parser.ConstraintLevelInformational: 1,
+	parser.ConstraintLevelWarning:       2,
+	parser.ConstraintLevelError:         3,
+	parser.ConstraintLevelCritical:      4,
+}
+
+// AtLeast returns true when the level is as severe as the threshold or more
+func AtLeast(level, threshold parser.ConstraintLevel) bool {
+	return levelRank[level] >= levelRank[threshold]
+}
+
+// ParseLevel returns the constraint level of given name
+func ParseLevel(name string) (parser.ConstraintLevel, error) {
+	level := parser.ConstraintLevel(name)
+	if _, ok := levelRank[level]; !ok {
+		return "", fmt.Errorf("unknown level '%s', expected one of CRITICAL, ERROR, WARNING, INFORMATIONAL, DEBUG", name)
+	}
+	return level, nil
+}
+
+// Fails returns true when any of the findings is at the threshold level or
+// more severe
+func (r *Report) Fails(threshold parser.ConstraintLevel) bool {
+	for _, f := range r.Findings {
+		if AtLeast(f.Level, threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the findings in compiler-like human readable form
+func WriteText(w io.Writer, reports []Report) error {
+	counts := map[parser.ConstraintLevel]int{}
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			counts[f.Level]++
+			location := r.Document
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d:%d", location, f.Line, f.Column)
+			}
+			rule := f.Rule
+			if f.ConstraintId != "" {
+				rule += " " + f.ConstraintId
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s [%s] %s: %s\n", location, f.Level, rule, f.Path, f.Message); err != nil {
+				return err
+			}
+		}
+	}
+	if len(counts) == 0 {
+		_, err := fmt.Fprintf(w, "%d document(s) valid\n", len(reports))
+		return err
+	}
+	levels := make([]parser.ConstraintLevel, 0, len(counts))
+	for l := range counts {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levelRank[levels[i]] > levelRank[levels[j]] })
+	summary := ""
+	for _, l := range levels {
+		if summary != "" {
+			summary += ", "
+		}
+		summary += fmt.Sprintf("%d %s", counts[l], l)
+	}
+	_, err := fmt.Fprintln(w, summary)
+	return err
+}
+
+// WriteJSON writes the reports as JSON array
+func WriteJSON(w io.Writer, reports []Report) error {
+	for i := range reports {
+		if reports[i].Findings == nil {
+			reports[i].Findings = []Finding{}
+		}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(reports)
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// SARIF 2.1.0 log, only the properties needed to report findings
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes the findings as SARIF log, suitable for code scanning
+// services
+func WriteSARIF(w io.Writer, reports []Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gocomply_metaschema",
+			InformationUri: "https://github.com/GoComply/metaschema",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	rules := map[string]bool{}
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			id := f.Rule
+			if f.ConstraintId != "" {
+				id = f.ConstraintId
+			}
+			rules[id] = true
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: r.Document}},
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Path}},
+			}
+			if f.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleId:    id,
+				Level:     sarifLevel(f.Level),
+				Message:   sarifMessage{Text: f.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	for id := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{Id: id})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool { return run.Tool.Driver.Rules[i].Id < run.Tool.Driver.Rules[j].Id })
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(level parser.ConstraintLevel) string {
+	switch level {
+	case parser.ConstraintLevelCritical, parser.ConstraintLevelError:
+		return "error"
+	case parser.ConstraintLevelWarning:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,427 @@
+// Package validator checks instance documents read by package document
+// against their metaschema: structure, cardinality, data types and the
+// constraints declared by the definitions.
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/datatype"
+	"github.com/gocomply/metaschema/metaschema/document"
+	"github.com/gocomply/metaschema/metaschema/metapath"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// Finding describes single place where the document does not conform to the
+// metaschema
+type Finding struct {
+	Level parser.ConstraintLevel `json:"level"`
+	// Rule is the kind of the check, such as cardinality or allowed-values
+	Rule string `json:"rule"`
+	// ConstraintId is the id of the violated constraint, when declared
+	ConstraintId string `json:"constraintId,omitempty"`
+	Path         string `json:"path"`
+	Line         int    `json:"line,omitempty"`
+	Column       int    `json:"column,omitempty"`
+	Message      string `json:"message"`
+}
+
+// Rule kinds of findings not caused by constraints
+const (
+	RuleSyntax      = "syntax"
+	RuleStructure   = "structure"
+	RuleCardinality = "cardinality"
+	RuleChoice      = "choice"
+	RuleDatatype    = "datatype"
+	RuleMetapath    = "metapath"
+)
+
+// Validate checks the document and returns all the findings ordered by their
+// position in the source
+func Validate(doc *document.Node) []Finding {
+	v := &validator{
+		indexes:     map[string]map[string]bool{},
+		expressions: map[string]*metapath.Expression{},
+	}
+	nodes := walk(doc, nil)
+	for _, n := range nodes {
+		v.structure(n)
+	}
+	// indexes have to be complete before index-has-key rules are checked
+	for _, n := range nodes {
+		for _, rule := range rules(n) {
+			if index, ok := rule.(*parser.Index); ok {
+				v.index(n, index)
+			}
+		}
+	}
+	for _, n := range nodes {
+		for _, rule := range rules(n) {
+			v.constraint(n, rule)
+		}
+	}
+	sort.SliceStable(v.findings, func(i, j int) bool {
+		a, b := v.findings[i], v.findings[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return v.findings
+}
+
+type validator struct {
+	findings    []Finding
+	indexes     map[string]map[string]bool
+	expressions map[string]*metapath.Expression
+}
+
+func walk(n *document.Node, out []*document.Node) []*document.Node {
+	out = append(out, n)
+	out = append(out, n.FlagNodes()...)
+	for _, c := range n.ChildNodes() {
+		out = walk(c, out)
+	}
+	return out
+}
+
+// rules returns constraint rules declared by the definition of the node
+func rules(n *document.Node) []parser.Rule {
+	switch {
+	case n.AssemblyDefinition() != nil:
+		return n.AssemblyDefinition().Constraint.Rules()
+	case n.FieldDefinition() != nil:
+		return n.FieldDefinition().Constraint.Rules()
+	case n.FlagDefinition() != nil:
+		return n.FlagDefinition().Rules()
+	}
+	return nil
+}
+
+func (v *validator) report(n *document.Node, level parser.ConstraintLevel, rule, format string, args ...interface{}) {
+	line, column := n.Position()
+	v.findings = append(v.findings, Finding{
+		Level:   level,
+		Rule:    rule,
+		Path:    n.Path(),
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) structure(n *document.Node) {
+	if n.Kind() == metapath.DocumentNode {
+		return
+	}
+	if !n.Known() {
+		if n.Kind() == metapath.FlagNode {
+			v.report(n, parser.ConstraintLevelError, RuleStructure, "flag '%s' is not declared by the metaschema", n.Name())
+		} else {
+			v.report(n, parser.ConstraintLevelError, RuleStructure, "'%s' is not declared by the metaschema", n.Name())
+		}
+		return
+	}
+	if n.Problem() != "" {
+		v.report(n, parser.ConstraintLevelError, RuleStructure, "%s", n.Problem())
+	}
+	switch {
+	case n.AssemblyDefinition() != nil:
+		def := n.AssemblyDefinition()
+		v.requiredFlags(n, def.Flags)
+		if def.Model != nil {
+			v.cardinality(n, def.Model)
+		}
+	case n.FieldDefinition() != nil:
+		def := n.FieldDefinition()
+		v.requiredFlags(n, def.Flags)
+		v.datatype(n, n.AsType())
+	case n.FlagDefinition() != nil:
+		v.datatype(n, n.AsType())
+	}
+}
+
+func (v *validator) requiredFlags(n *document.Node, flags []parser.Flag) {
+	for i := range flags {
+		if flags[i].IsRequired() && n.Flag(flags[i].XmlName()) == nil {
+			v.report(n, parser.ConstraintLevelError, RuleStructure, "required flag '%s' is missing", flags[i].XmlName())
+		}
+	}
+}
+
+func (v *validator) cardinality(n *document.Node, model *parser.Model) {
+	counts := map[parser.GoStructItem]int{}
+	for _, c := range n.ChildNodes() {
+		if c.ModelItem() != nil {
+			counts[c.ModelItem()]++
+		}
+	}
+	for _, item := range model.GoStructItems() {
+		count, min, max := counts[item], item.Min(), item.Max()
+		if item.InChoice() {
+			min = 0
+		}
+		if count < min {
+			if count == 0 {
+				v.report(n, parser.ConstraintLevelError, RuleCardinality, "required '%s' is missing", item.XmlName())
+			} else {
+				v.report(n, parser.ConstraintLevelError, RuleCardinality, "expected at least %d '%s', found %d", min, item.XmlName(), count)
+			}
+		}
+		if max >= 0 && count > max {
+			v.report(n, parser.ConstraintLevelError, RuleCardinality, "expected at most %d '%s', found %d", max, item.XmlName(), count)
+		}
+	}
+	for i := range model.Choice {
+		set := 0
+		var names []string
+		for _, item := range model.Choice[i].GoStructItems() {
+			names = append(names, item.XmlName())
+			if counts[item] > 0 {
+				set++
+			}
+		}
+		if set != 1 {
+			v.report(n, parser.ConstraintLevelError, RuleChoice, "exactly one of %s must be set, found %d", strings.Join(names, ", "), set)
+		}
+	}
+}
+
+func (v *validator) datatype(n *document.Node, asType parser.AsType) {
+	if asType == "" {
+		asType = parser.AsTypeString
+	}
+	if n.Kind() == metapath.FieldNode && asType == parser.AsTypeString && n.Value() == "" && len(n.FlagNodes()) > 0 {
+		// fields are often used only to carry their flags
+		return
+	}
+	if err := datatype.Check(string(asType), n.Value()); err != nil {
+		v.report(n, parser.ConstraintLevelError, RuleDatatype, "invalid %s: %s", asType, err)
+	}
+}
+
+func (v *validator) compile(expression string) (*metapath.Expression, error) {
+	if e, ok := v.expressions[expression]; ok {
+		return e, nil
+	}
+	e, err := metapath.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	v.expressions[expression] = e
+	return e, nil
+}
+
+// targets evaluates target of the rule in the context of the node
+func (v *validator) targets(n *document.Node, rule parser.Rule) ([]*document.Node, bool) {
+	target := rule.Base().EffectiveTarget()
+	if target == "." {
+		return []*document.Node{n}, true
+	}
+	e, err := v.compile(target)
+	if err == nil {
+		var nodes []metapath.Node
+		nodes, err = e.EvaluateNodes(n)
+		if err == nil {
+			result := make([]*document.Node, 0, len(nodes))
+			for _, node := range nodes {
+				if dn, ok := node.(*document.Node); ok {
+					result = append(result, dn)
+				}
+			}
+			return result, true
+		}
+	}
+	v.violation(n, rule, RuleMetapath, "cannot evaluate target '%s' of %s constraint: %s", target, rule.Kind(), err)
+	return nil, false
+}
+
+// violation records finding caused by the constraint rule
+func (v *validator) violation(n *document.Node, rule parser.Rule, kind, format string, args ...interface{}) {
+	v.report(n, rule.Base().EffectiveLevel(), kind, format, args...)
+	v.findings[len(v.findings)-1].ConstraintId = rule.Base().Id
+}
+
+// message returns the message declared by the rule, or the default one
+func (v *validator) message(n *document.Node, rule parser.Rule, format string, args ...interface{}) string {
+	msg := strings.TrimSpace(rule.Base().Message)
+	if msg == "" {
+		return fmt.Sprintf(format, args...)
+	}
+	return messageExpr.ReplaceAllStringFunc(msg, func(m string) string {
+		e, err := v.compile(strings.TrimSpace(m[1 : len(m)-1]))
+		if err != nil {
+			return m
+		}
+		s, err := e.EvaluateString(n)
+		if err != nil {
+			return m
+		}
+		return s
+	})
+}
+
+var messageExpr = regexp.MustCompile(`\{[^{}]+\}`)
+
+func (v *validator) constraint(n *document.Node, rule parser.Rule) {
+	switch r := rule.(type) {
+	case *parser.AllowedValues:
+		if r.AllowsOther() {
+			return
+		}
+		targets, _ := v.targets(n, r)
+		allowed := r.Values()
+		for _, t := range targets {
+			if !contains(allowed, t.Value()) {
+				v.violation(t, r, r.Kind(), "%s", v.message(t, r, "value '%s' is not one of allowed values: %s", t.Value(), strings.Join(allowed, ", ")))
+			}
+		}
+	case *parser.Matches:
+		targets, _ := v.targets(n, r)
+		for _, t := range targets {
+			v.matches(t, r)
+		}
+	case *parser.IsUnique:
+		targets, _ := v.targets(n, r)
+		seen := map[string]bool{}
+		for _, t := range targets {
+			key, ok := v.key(t, r, r.KeyField)
+			if !ok {
+				continue
+			}
+			if seen[key] {
+				v.violation(t, r, r.Kind(), "%s", v.message(t, r, "key '%s' is not unique", printableKey(key)))
+			}
+			seen[key] = true
+		}
+	case *parser.HasCardinality:
+		targets, ok := v.targets(n, r)
+		if !ok {
+			return
+		}
+		count := len(targets)
+		if count < r.Min() || (r.Max() >= 0 && count > r.Max()) {
+			v.violation(n, r, r.Kind(), "%s", v.message(n, r, "found %d occurrences of '%s', expected %s", count, r.EffectiveTarget(), bounds(r.Min(), r.Max())))
+		}
+	case *parser.Expect:
+		targets, _ := v.targets(n, r)
+		e, err := v.compile(r.Test)
+		if err != nil {
+			v.violation(n, r, RuleMetapath, "cannot parse test '%s' of expect constraint: %s", r.Test, err)
+			return
+		}
+		for _, t := range targets {
+			ok, err := e.EvaluateBoolean(t)
+			if err != nil {
+				v.violation(t, r, RuleMetapath, "cannot evaluate test '%s' of expect constraint: %s", r.Test, err)
+			} else if !ok {
+				v.violation(t, r, r.Kind(), "%s", v.message(t, r, "expectation '%s' is not met", r.Test))
+			}
+		}
+	case *parser.IndexHasKey:
+		targets, _ := v.targets(n, r)
+		for _, t := range targets {
+			key, ok := v.key(t, r, r.KeyField)
+			if ok && !v.indexes[r.Name][key] {
+				v.violation(t, r, r.Kind(), "%s", v.message(t, r, "key '%s' is not found in index '%s'", printableKey(key), r.Name))
+			}
+		}
+	}
+}
+
+func (v *validator) matches(t *document.Node, r *parser.Matches) {
+	if r.Datatype != "" {
+		if err := datatype.Check(string(r.Datatype), t.Value()); err != nil {
+			v.violation(t, r, r.Kind(), "%s", v.message(t, r, "invalid %s: %s", r.Datatype, err))
+		}
+	}
+	if r.Regex == "" {
+		return
+	}
+	re, err := regexp.Compile("^(?:" + r.Regex + ")$")
+	if err != nil {
+		v.violation(t, r, r.Kind(), "cannot compile pattern '%s': %s", r.Regex, err)
+		return
+	}
+	if !re.MatchString(t.Value()) {
+		v.violation(t, r, r.Kind(), "%s", v.message(t, r, "value '%s' does not match pattern '%s'", t.Value(), r.Regex))
+	}
+}
+
+func (v *validator) index(n *document.Node, r *parser.Index) {
+	if v.indexes[r.Name] == nil {
+		v.indexes[r.Name] = map[string]bool{}
+	}
+	targets, _ := v.targets(n, r)
+	for _, t := range targets {
+		key, ok := v.key(t, r, r.KeyField)
+		if !ok {
+			continue
+		}
+		if v.indexes[r.Name][key] {
+			v.violation(t, r, r.Kind(), "%s", v.message(t, r, "duplicate key '%s' in index '%s'", printableKey(key), r.Name))
+		}
+		v.indexes[r.Name][key] = true
+	}
+}
+
+// key computes the key of the target node, returns false when it cannot be
+// computed
+func (v *validator) key(t *document.Node, rule parser.Rule, fields []parser.KeyField) (string, bool) {
+	parts := make([]string, 0, len(fields))
+	for _, kf := range fields {
+		target := strings.TrimSpace(kf.Target)
+		if target == "" {
+			target = "."
+		}
+		e, err := v.compile(target)
+		if err != nil {
+			v.violation(t, rule, RuleMetapath, "cannot parse key-field target '%s': %s", target, err)
+			return "", false
+		}
+		s, err := e.EvaluateString(t)
+		if err != nil {
+			v.violation(t, rule, RuleMetapath, "cannot evaluate key-field target '%s': %s", target, err)
+			return "", false
+		}
+		if kf.Pattern != "" {
+			re, err := regexp.Compile(kf.Pattern)
+			if err != nil {
+				v.violation(t, rule, RuleMetapath, "cannot compile key-field pattern '%s': %s", kf.Pattern, err)
+				return "", false
+			}
+			if m := re.FindStringSubmatch(s); len(m) > 1 {
+				s = m[1]
+			} else if len(m) == 1 {
+				s = m[0]
+			}
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, keySeparator), true
+}
+
+const keySeparator = "\x00"
+
+func printableKey(key string) string {
+	return strings.ReplaceAll(key, keySeparator, "|")
+}
+
+func bounds(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("at least %d", min)
+	case min == max:
+		return fmt.Sprintf("exactly %d", min)
+	}
+	return fmt.Sprintf("between %d and %d", min, max)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/document"
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const catalogMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>catalog</short-name>
+  <namespace>http://example.com/ns/catalog</namespace>
+  <define-flag name="id" as-type="NCName"><description>Identifier</description></define-flag>
+  <define-assembly name="catalog">
+    <description>A catalog</description>
+    <root-name>catalog</root-name>
+    <flag ref="id" required="yes"/>
+    <constraint>
+      <is-unique id="unique-control" target="control"><key-field target="@id"/></is-unique>
+      <expect target="." test="count(control) &gt; 1" level="WARNING"><message>catalog should have controls</message></expect>
+    </constraint>
+    <model>
+      <field ref="title" required="yes"/>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls" in-json="ARRAY"/></assembly>
+    </model>
+  </define-assembly>
+  <define-field name="title"><description>A title</description></define-field>
+  <define-assembly name="control">
+    <description>A control</description>
+    <flag ref="id" required="yes"/>
+    <flag name="class" as-type="NCName"><description>class</description>
+      <constraint>
+        <allowed-values allow-other="no"><enum value="basic">Basic</enum></allowed-values>
+      </constraint>
+    </flag>
+    <model>
+      <field ref="title" required="yes"/>
+    </model>
+  </define-assembly>
+</METASCHEMA>`
+
+// invalidCatalog has allowed-values, is-unique and structure findings
+const invalidCatalog = `<catalog xmlns="http://example.com/ns/catalog" id="c1">
+  <title>Catalog</title>
+  <control id="a" class="other"><title>A</title></control>
+  <control id="a"><title>B</title><extra/></control>
+  <control><title>C</title></control>
+</catalog>`
+
+func loadSchema(t *testing.T) *document.Schema {
+	t.Helper()
+	meta := &parser.Metaschema{URI: "file:///catalog.xml"}
+	if err := xml.Unmarshal([]byte(catalogMetaschema), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := document.NewSchema(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func validate(t *testing.T, src string, format document.Format) []Finding {
+	t.Helper()
+	doc, err := loadSchema(t).Decode([]byte(src), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Validate(doc)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		format document.Format
+		src    string
+		want   []string
+	}{
+		{"valid xml", document.XML, `<catalog xmlns="http://example.com/ns/catalog" id="c1">
+  <title>Catalog</title>
+  <control id="a" class="basic"><title>A</title></control>
+  <control id="b"><title>B</title></control>
+</catalog>`, nil},
+		{"valid json", document.JSON, `{"catalog": {"id": "c1", "title": "Catalog",
+  "controls": [{"id": "a", "title": "A"}, {"id": "b", "title": "B"}]}}`, nil},
+		{"valid yaml", document.YAML, "catalog:\n  id: c1\n  title: Catalog\n  controls:\n  - id: a\n    title: A\n  - id: b\n    title: B\n", nil},
+		{"invalid xml", document.XML, invalidCatalog, []string{
+			"ERROR allowed-values /catalog/control[1]/@class",
+			"ERROR unique-control /catalog/control[2]",
+			"ERROR structure /catalog/control[2]/extra",
+			"ERROR structure /catalog/control[3]",
+		}},
+		{"missing title in json", document.JSON, `{"catalog": {"id": "c1", "controls": [{"id": "a", "title": "A"}]}}`, []string{
+			"ERROR cardinality /catalog",
+			"WARNING expect /catalog",
+		}},
+		{"datatype in yaml", document.YAML, "catalog:\n  id: not valid\n  title: Catalog\n  controls:\n  - id: a\n    title: A\n  - id: b\n    title: B\n", []string{
+			"ERROR datatype /catalog/@id",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range validate(t, tt.src, tt.format) {
+				rule := f.Rule
+				if f.ConstraintId != "" {
+					rule = f.ConstraintId
+				}
+				got = append(got, string(f.Level)+" "+rule+" "+f.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got findings\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWriteReports(t *testing.T) {
+	reports := []Report{
+		{Document: "valid.xml", Format: "xml"},
+		{Document: "invalid.xml", Format: "xml", Findings: validate(t, invalidCatalog, document.XML)},
+	}
+	tests := []struct {
+		name  string
+		write func(w *bytes.Buffer) error
+		want  []string
+	}{
+		{"text", func(w *bytes.Buffer) error { return WriteText(w, reports) }, []string{
+			"invalid.xml:3:3: ERROR [allowed-values] /catalog/control[1]/@class: value 'other' is not one of allowed values: basic\n",
+			"invalid.xml:4:3: ERROR [is-unique unique-control] /catalog/control[2]: ",
+			"\n4 ERROR\n",
+		}},
+		{"json", func(w *bytes.Buffer) error { return WriteJSON(w, reports) }, []string{
+			`"document": "valid.xml",` + "\n    \"format\": \"xml\",\n    \"findings\": []",
+			`"constraintId": "unique-control"`,
+			`"line": 4`,
+		}},
+		{"sarif", func(w *bytes.Buffer) error { return WriteSARIF(w, reports) }, []string{
+			`"version": "2.1.0"`,
+			`"ruleId": "unique-control"`,
+			`"uri": "invalid.xml"`,
+			`"fullyQualifiedName": "/catalog/control[2]/extra"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output is missing %q:\n%s", want, buf.String())
+				}
+			}
+			if tt.name != "text" && !json.Valid(buf.Bytes()) {
+				t.Errorf("output is not valid JSON:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteTextValid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, []Report{{Document: "a.xml"}, {Document: "b.json"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "2 document(s) valid\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+// TestFails checks the decision of the exit status of the validate command
+func TestFails(t *testing.T) {
+	warning := Report{Findings: []Finding{{Level: parser.ConstraintLevelWarning}}}
+	invalid := Report{Findings: validate(t, invalidCatalog, document.XML)}
+	tests := []struct {
+		report    Report
+		threshold string
+		fails     bool
+	}{
+		{Report{}, "DEBUG", false},
+		{warning, "ERROR", false},
+		{warning, "WARNING", true},
+		{warning, "INFORMATIONAL", true},
+		{invalid, "CRITICAL", false},
+		{invalid, "ERROR", true},
+	}
+	for _, tt := range tests {
+		threshold, err := ParseLevel(tt.threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tt.report.Fails(threshold); got != tt.fails {
+			t.Errorf("%v at %s: got %v, want %v", tt.report.Findings, tt.threshold, got, tt.fails)
+		}
+	}
+	if _, err := ParseLevel("FATAL"); err == nil {
+		t.Error("unknown level parsed")
+	}
+}
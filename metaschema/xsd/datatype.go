@@ -0,0 +1,42 @@
+package xsd
+
+import "github.com/gocomply/metaschema/metaschema/parser"
+
+// restriction is a simple type restricting a built-in XML Schema type
+type restriction struct {
+	base    string
+	pattern string
+}
+
+// datatypes maps data types to simple types. Patterns follow the lexical
+// rules of the datatype package written as XML Schema regular expressions,
+// which match the whole value.
+var datatypes = map[parser.AsType]restriction{
+	parser.AsTypeString:             {"xs:string", `[\s\S]*\S[\s\S]*`},
+	parser.AsTypeToken:              {"xs:string", `\S( ?\S)*`},
+	parser.AsTypeNCName:             {"xs:NCName", ""},
+	parser.AsTypeID:                 {"xs:NCName", ""},
+	parser.AsTypeIDRef:              {"xs:NCName", ""},
+	parser.AsTypeNMToken:            {"xs:NMTOKEN", ""},
+	parser.AsTypeUUID:               {"xs:string", `[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[45][0-9A-Fa-f]{3}-[89ABab][0-9A-Fa-f]{3}-[0-9A-Fa-f]{12}`},
+	parser.AsTypeEmail:              {"xs:string", `[^\s@]+@[^\s@]+`},
+	parser.AsTypeHostname:           {"xs:string", `[\p{L}\p{N}]([\p{L}\p{N}\-]*[\p{L}\p{N}])?(\.[\p{L}\p{N}]([\p{L}\p{N}\-]*[\p{L}\p{N}])?)*\.?`},
+	parser.AsTypeBoolean:            {"xs:boolean", ""},
+	parser.AsTypeInteger:            {"xs:integer", ""},
+	parser.AsTypeNonNegativeInteger: {"xs:nonNegativeInteger", ""},
+	parser.AsTypePositiveInteger:    {"xs:positiveInteger", ""},
+	parser.AsTypeDecimal:            {"xs:decimal", ""},
+	parser.AsTypeDate:               {"xs:date", ""},
+	parser.AsTypeDateTZ:             {"xs:date", `.*(Z|[+\-][0-9]{2}:[0-9]{2})`},
+	parser.AsTypeDateTime:           {"xs:dateTime", ""},
+	parser.AsTypeDateTimeTZ:         {"xs:dateTime", `.*(Z|[+\-][0-9]{2}:[0-9]{2})`},
+	parser.AsTypeDayTimeDuration:    {"xs:duration", `-?P([0-9]+D)?(T([0-9]+H)?([0-9]+M)?([0-9]+(\.[0-9]+)?S)?)?`},
+	parser.AsTypeYearMonthDuration:  {"xs:duration", `-?P([0-9]+Y)?([0-9]+M)?`},
+	parser.AsTypeBase64:             {"xs:base64Binary", ""},
+	parser.AsTypeURI:                {"xs:anyURI", `[A-Za-z][A-Za-z0-9+\-.]*:.*`},
+	parser.AsTypeAnyURI:             {"xs:anyURI", ""},
+	parser.AsTypeURIRef:             {"xs:anyURI", ""},
+	parser.AsTypeIPv4Address:        {"xs:string", `((25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\.){3}(25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])`},
+	// IPv6 addresses are only checked for the allowed characters
+	parser.AsTypeIPv6Address: {"xs:string", `[0-9A-Fa-f:.]*:[0-9A-Fa-f:.]*`},
+}
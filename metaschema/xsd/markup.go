@@ -0,0 +1,126 @@
+package xsd
+
+// markupTypes declare the XHTML subset of markup-line and markup-multiline
+// content as converted by the markup package. Markup elements are in the
+// namespace of the field that holds them, so the declarations are repeated by
+// each schema that uses markup.
+const markupTypes = `
+  <xs:group name="inline-markup">
+    <xs:choice>
+      <xs:element name="a">
+        <xs:complexType mixed="true">
+          <xs:group ref="inline-markup" minOccurs="0" maxOccurs="unbounded"/>
+          <xs:attribute name="href" type="xs:anyURI"/>
+          <xs:attribute name="title" type="xs:string"/>
+        </xs:complexType>
+      </xs:element>
+      <xs:element name="em" type="inline-markup"/>
+      <xs:element name="i" type="inline-markup"/>
+      <xs:element name="strong" type="inline-markup"/>
+      <xs:element name="b" type="inline-markup"/>
+      <xs:element name="code">
+        <xs:complexType mixed="true">
+          <xs:group ref="inline-markup" minOccurs="0" maxOccurs="unbounded"/>
+          <xs:attribute name="class" type="xs:string"/>
+        </xs:complexType>
+      </xs:element>
+      <xs:element name="q" type="inline-markup"/>
+      <xs:element name="sub" type="inline-markup"/>
+      <xs:element name="sup" type="inline-markup"/>
+      <xs:element name="img">
+        <xs:complexType>
+          <xs:attribute name="src" type="xs:anyURI" use="required"/>
+          <xs:attribute name="alt" type="xs:string"/>
+          <xs:attribute name="title" type="xs:string"/>
+        </xs:complexType>
+      </xs:element>
+      <xs:element name="br">
+        <xs:complexType/>
+      </xs:element>
+      <xs:element name="insert">
+        <xs:complexType>
+          <xs:attribute name="type" type="xs:NCName" use="required"/>
+          <xs:attribute name="id-ref" type="xs:NCName" use="required"/>
+        </xs:complexType>
+      </xs:element>
+    </xs:choice>
+  </xs:group>
+
+  <xs:complexType name="inline-markup" mixed="true">
+    <xs:group ref="inline-markup" minOccurs="0" maxOccurs="unbounded"/>
+  </xs:complexType>
+
+  <xs:group name="block-markup">
+    <xs:choice>
+      <xs:element name="p" type="inline-markup"/>
+      <xs:element name="h1" type="inline-markup"/>
+      <xs:element name="h2" type="inline-markup"/>
+      <xs:element name="h3" type="inline-markup"/>
+      <xs:element name="h4" type="inline-markup"/>
+      <xs:element name="h5" type="inline-markup"/>
+      <xs:element name="h6" type="inline-markup"/>
+      <xs:element name="ul" type="list-markup"/>
+      <xs:element name="ol" type="list-markup"/>
+      <xs:element name="pre" type="inline-markup"/>
+      <xs:element name="hr">
+        <xs:complexType/>
+      </xs:element>
+      <xs:element name="blockquote" type="block-markup"/>
+      <xs:element name="table" type="table-markup"/>
+    </xs:choice>
+  </xs:group>
+
+  <xs:complexType name="block-markup">
+    <xs:group ref="block-markup" minOccurs="0" maxOccurs="unbounded"/>
+  </xs:complexType>
+
+  <xs:complexType name="list-markup">
+    <xs:sequence>
+      <xs:element name="li" maxOccurs="unbounded">
+        <xs:complexType mixed="true">
+          <xs:choice minOccurs="0" maxOccurs="unbounded">
+            <xs:group ref="inline-markup"/>
+            <xs:element name="p" type="inline-markup"/>
+            <xs:element name="ul" type="list-markup"/>
+            <xs:element name="ol" type="list-markup"/>
+          </xs:choice>
+        </xs:complexType>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+
+  <xs:complexType name="table-markup">
+    <xs:choice maxOccurs="unbounded">
+      <xs:element name="thead" type="table-rows-markup"/>
+      <xs:element name="tbody" type="table-rows-markup"/>
+      <xs:element name="tfoot" type="table-rows-markup"/>
+      <xs:element name="tr" type="table-row-markup"/>
+    </xs:choice>
+  </xs:complexType>
+
+  <xs:complexType name="table-rows-markup">
+    <xs:sequence>
+      <xs:element name="tr" type="table-row-markup" maxOccurs="unbounded"/>
+    </xs:sequence>
+  </xs:complexType>
+
+  <xs:complexType name="table-row-markup">
+    <xs:choice maxOccurs="unbounded">
+      <xs:element name="th" type="table-cell-markup"/>
+      <xs:element name="td" type="table-cell-markup"/>
+    </xs:choice>
+  </xs:complexType>
+
+  <xs:complexType name="table-cell-markup" mixed="true">
+    <xs:group ref="inline-markup" minOccurs="0" maxOccurs="unbounded"/>
+    <xs:attribute name="align">
+      <xs:simpleType>
+        <xs:restriction base="xs:token">
+          <xs:enumeration value="left"/>
+          <xs:enumeration value="center"/>
+          <xs:enumeration value="right"/>
+        </xs:restriction>
+      </xs:simpleType>
+    </xs:attribute>
+  </xs:complexType>
+`
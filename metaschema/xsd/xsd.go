@@ -0,0 +1,424 @@
+// Package xsd generates XML Schema of the XML representation of compiled
+// metaschemas, that is the XML read and written by the generated go types and
+// by the document package.
+//
+// Each metaschema becomes a schema of its own namespace, imported
+// metaschemas are referred to by xs:import. Top-level definitions become
+// global elements of named complex types, local definitions get named types
+// derived from the name of their parent. Flags are attributes, group-as
+// in-xml="GROUPED" adds the wrapper element and unwrapped markup-multiline
+// fields place their blocks directly within the parent element.
+package xsd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+// GenerateAll writes the schema of the metaschema to FileName file within
+// baseDir
+func GenerateAll(metaschema *parser.Metaschema, baseDir string) error {
+	var buf bytes.Buffer
+	if err := Generate(metaschema, &buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(baseDir, os.FileMode(0755)); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(baseDir, FileName(metaschema)), buf.Bytes(), os.FileMode(0644))
+}
+
+// FileName returns name of the schema file of the metaschema. Schemas of
+// imported metaschemas are expected next to the importing one.
+func FileName(metaschema *parser.Metaschema) string {
+	return metaschema.GoPackageName() + "_schema.xsd"
+}
+
+// Generate writes XML Schema of the definitions of the metaschema
+func Generate(metaschema *parser.Metaschema, w io.Writer) error {
+	g := generator{
+		metaschema: metaschema,
+		imports:    map[string]*parser.Metaschema{},
+		datatypes:  map[parser.AsType]bool{},
+	}
+	for i := range metaschema.DefineAssembly {
+		da := &metaschema.DefineAssembly[i]
+		g.element(da.Name, da.Name+"-ASSEMBLY")
+		if da.RootXmlName() != da.Name {
+			g.element(da.RootXmlName(), da.Name+"-ASSEMBLY")
+		}
+		g.queue(func() { g.assemblyType(da, da.Name) })
+	}
+	for i := range metaschema.DefineField {
+		df := &metaschema.DefineField[i]
+		g.element(df.Name, df.Name+"-FIELD")
+		g.queue(func() { g.fieldType(df, df.Name) })
+	}
+	for len(g.pending) > 0 {
+		next := g.pending[0]
+		g.pending = g.pending[1:]
+		next()
+	}
+	if g.err != nil {
+		return g.err
+	}
+	_, err := io.WriteString(w, g.document())
+	return err
+}
+
+// generator writes declarations of a schema, collecting imports, data types
+// and markup they depend on
+type generator struct {
+	metaschema *parser.Metaschema
+	body       strings.Builder
+	// pending writes types of local definitions after their parent
+	pending   []func()
+	imports   map[string]*parser.Metaschema
+	datatypes map[parser.AsType]bool
+	markup    bool
+	err       error
+}
+
+func (g *generator) document() string {
+	var doc strings.Builder
+	ns := g.metaschema.XmlNamespace()
+	doc.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	doc.WriteString("<!-- Code generated by https://github.com/GoComply/metaschema; DO NOT EDIT. -->\n")
+	doc.WriteString(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"`)
+	if ns != "" {
+		doc.WriteString(` targetNamespace="` + escape(ns) + `" xmlns="` + escape(ns) + `"`)
+	}
+	namespaces := make([]string, 0, len(g.imports))
+	for importNS := range g.imports {
+		namespaces = append(namespaces, importNS)
+	}
+	sort.Strings(namespaces)
+	for _, importNS := range namespaces {
+		if importNS != ns {
+			doc.WriteString("\n  xmlns:" + g.imports[importNS].GoPackageName() + `="` + escape(importNS) + `"`)
+		}
+	}
+	doc.WriteString(` elementFormDefault="qualified">` + "\n")
+	for _, importNS := range namespaces {
+		location := escape(FileName(g.imports[importNS]))
+		switch {
+		case importNS == ns:
+			doc.WriteString(`  <xs:include schemaLocation="` + location + `"/>` + "\n")
+		default:
+			doc.WriteString(`  <xs:import namespace="` + escape(importNS) + `" schemaLocation="` + location + `"/>` + "\n")
+		}
+	}
+	doc.WriteString(g.body.String())
+	asTypes := make([]string, 0, len(g.datatypes))
+	for asType := range g.datatypes {
+		asTypes = append(asTypes, string(asType))
+	}
+	sort.Strings(asTypes)
+	for _, asType := range asTypes {
+		dt := datatypes[parser.AsType(asType)]
+		doc.WriteString("\n" + `  <xs:simpleType name="` + parser.AsType(asType).DatatypeName() + `">` + "\n")
+		if dt.pattern == "" {
+			doc.WriteString(`    <xs:restriction base="` + dt.base + `"/>` + "\n")
+		} else {
+			doc.WriteString(`    <xs:restriction base="` + dt.base + `">` + "\n")
+			doc.WriteString(`      <xs:pattern value="` + escape(dt.pattern) + `"/>` + "\n")
+			doc.WriteString("    </xs:restriction>\n")
+		}
+		doc.WriteString("  </xs:simpleType>\n")
+	}
+	if g.markup {
+		doc.WriteString(markupTypes)
+	}
+	doc.WriteString("</xs:schema>\n")
+	return doc.String()
+}
+
+func (g *generator) queue(write func()) {
+	g.pending = append(g.pending, write)
+}
+
+func (g *generator) line(depth int, format string, args ...interface{}) {
+	g.body.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteString("\n")
+}
+
+func (g *generator) fail(err error) {
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+func (g *generator) element(name, typeName string) {
+	g.line(1, `<xs:element name="%s" type="%s"/>`, name, typeName)
+}
+
+func (g *generator) documentation(depth int, description string) {
+	if text := strings.Join(strings.Fields(description), " "); text != "" {
+		g.line(depth, `<xs:annotation><xs:documentation>%s</xs:documentation></xs:annotation>`, escape(text))
+	}
+}
+
+// assemblyType writes complex type of the assembly definition, base is the
+// name used to derive names of the types
+func (g *generator) assemblyType(da *parser.DefineAssembly, base string) {
+	g.line(0, "")
+	g.line(1, `<xs:complexType name="%s-ASSEMBLY">`, base)
+	g.documentation(2, da.Description)
+	if da.Model != nil && len(da.Model.GoStructItems()) > 0 {
+		g.line(2, "<xs:sequence>")
+		written := map[*parser.Choice]bool{}
+		for _, item := range da.Model.GoStructItems() {
+			choice := choiceOf(da.Model, item)
+			switch {
+			case choice == nil:
+				g.item(3, item, base, item.Min())
+			case !written[choice]:
+				written[choice] = true
+				g.line(3, "<xs:choice>")
+				for _, member := range choice.GoStructItems() {
+					min := member.Min()
+					if min < 1 {
+						min = 1
+					}
+					g.item(4, member, base, min)
+				}
+				g.line(3, "</xs:choice>")
+			}
+		}
+		g.line(2, "</xs:sequence>")
+	}
+	g.attributes(2, da.Flags)
+	g.line(1, "</xs:complexType>")
+}
+
+func choiceOf(model *parser.Model, item parser.GoStructItem) *parser.Choice {
+	for i := range model.Choice {
+		for _, member := range model.Choice[i].GoStructItems() {
+			if member == item {
+				return &model.Choice[i]
+			}
+		}
+	}
+	return nil
+}
+
+// item writes particle of the model item within type of the parent
+func (g *generator) item(depth int, item parser.GoStructItem, parentBase string, min int) {
+	var groupAs *parser.GroupAs
+	var element string
+	switch v := item.(type) {
+	case *parser.Assembly:
+		groupAs = v.GroupAs
+		if v.IsInline() {
+			base := parentBase + "-" + v.Def.Name
+			element = fmt.Sprintf(`name="%s" type="%s-ASSEMBLY"`, v.Def.Name, base)
+			g.queue(func() { g.assemblyType(v.Def, base) })
+		} else {
+			element = `ref="` + g.qname(v.Def.Metaschema, v.Def.Name) + `"`
+		}
+	case *parser.Field:
+		groupAs = v.GroupAs
+		if v.InXml == "UNWRAPPED" {
+			g.markup = true
+			g.line(depth, `<xs:group ref="block-markup" minOccurs="0" maxOccurs="unbounded"/>`)
+			return
+		}
+		if v.IsInline() {
+			base := parentBase + "-" + v.Def.Name
+			element = fmt.Sprintf(`name="%s" type="%s-FIELD"`, v.Def.Name, base)
+			g.queue(func() { g.fieldType(v.Def, base) })
+		} else {
+			element = `ref="` + g.qname(v.Def.Metaschema, v.Def.Name) + `"`
+		}
+	}
+	if groupAs == nil || groupAs.InXml != "GROUPED" {
+		g.line(depth, `<xs:element %s%s/>`, element, occurs(min, item.Max()))
+		return
+	}
+	wrapperMin := 1
+	if min == 0 {
+		wrapperMin, min = 0, 1
+	}
+	g.line(depth, `<xs:element name="%s"%s>`, groupAs.Name, occurs(wrapperMin, 1))
+	g.line(depth+1, "<xs:complexType>")
+	g.line(depth+2, "<xs:sequence>")
+	g.line(depth+3, `<xs:element %s%s/>`, element, occurs(min, item.Max()))
+	g.line(depth+2, "</xs:sequence>")
+	g.line(depth+1, "</xs:complexType>")
+	g.line(depth, "</xs:element>")
+}
+
+func occurs(min, max int) string {
+	var result string
+	if min != 1 {
+		result += fmt.Sprintf(` minOccurs="%d"`, min)
+	}
+	switch {
+	case max < 0:
+		result += ` maxOccurs="unbounded"`
+	case max > 1:
+		result += fmt.Sprintf(` maxOccurs="%d"`, max)
+	}
+	return result
+}
+
+// qname returns qualified name of a global element of the metaschema,
+// registering the import of other metaschemas
+func (g *generator) qname(metaschema *parser.Metaschema, name string) string {
+	if metaschema == nil || metaschema.GoPackageName() == g.metaschema.GoPackageName() {
+		return name
+	}
+	ns := metaschema.XmlNamespace()
+	g.imports[ns] = metaschema
+	if ns == g.metaschema.XmlNamespace() {
+		return name
+	}
+	return metaschema.GoPackageName() + ":" + name
+}
+
+// fieldType writes type of the field definition
+func (g *generator) fieldType(df *parser.DefineField, base string) {
+	name := base + "-FIELD"
+	g.line(0, "")
+	switch {
+	case df.IsMarkup():
+		g.markup = true
+		group, mixed := "block-markup", ""
+		if df.AsType == parser.AsTypeMarkupLine {
+			group, mixed = "inline-markup", ` mixed="true"`
+		}
+		g.line(1, `<xs:complexType name="%s"%s>`, name, mixed)
+		g.documentation(2, df.Description)
+		g.line(2, `<xs:group ref="%s" minOccurs="0" maxOccurs="unbounded"/>`, group)
+		g.attributes(2, df.Flags)
+		g.line(1, "</xs:complexType>")
+	case df.AsType == parser.AsTypeMixed:
+		g.line(1, `<xs:complexType name="%s" mixed="true">`, name)
+		g.documentation(2, df.Description)
+		g.line(2, "<xs:sequence>")
+		g.line(3, `<xs:any processContents="lax" minOccurs="0" maxOccurs="unbounded"/>`)
+		g.line(2, "</xs:sequence>")
+		g.attributes(2, df.Flags)
+		g.line(1, "</xs:complexType>")
+	case df.Empty():
+		g.line(1, `<xs:complexType name="%s">`, name)
+		g.documentation(2, df.Description)
+		g.attributes(2, df.Flags)
+		g.line(1, "</xs:complexType>")
+	case len(df.Flags) == 0:
+		g.line(1, `<xs:simpleType name="%s">`, name)
+		g.documentation(2, df.Description)
+		g.restriction(2, df.AsType, allowed(df.ClosedAllowedValues()))
+		g.line(1, "</xs:simpleType>")
+	default:
+		valueType := g.datatype(df.AsType)
+		if values := allowed(df.ClosedAllowedValues()); len(values) > 0 {
+			valueType = name + "-VALUE"
+			g.line(1, `<xs:simpleType name="%s">`, valueType)
+			g.restriction(2, df.AsType, values)
+			g.line(1, "</xs:simpleType>")
+		}
+		g.line(1, `<xs:complexType name="%s">`, name)
+		g.documentation(2, df.Description)
+		g.line(2, "<xs:simpleContent>")
+		g.line(3, `<xs:extension base="%s">`, valueType)
+		g.attributes(4, df.Flags)
+		g.line(3, "</xs:extension>")
+		g.line(2, "</xs:simpleContent>")
+		g.line(1, "</xs:complexType>")
+	}
+}
+
+func (g *generator) attributes(depth int, flags []parser.Flag) {
+	for i := range flags {
+		f := &flags[i]
+		asType, description := f.AsType, f.Description
+		if f.Def != nil {
+			if asType == "" {
+				asType = f.Def.AsType
+			}
+			if description == "" {
+				description = f.Def.Description
+			}
+		}
+		use := ""
+		if f.IsRequired() {
+			use = ` use="required"`
+		}
+		values := allowed(f.ClosedAllowedValues())
+		if len(values) == 0 {
+			g.line(depth, `<xs:attribute name="%s" type="%s"%s>`, f.XmlName(), g.datatype(asType), use)
+			g.documentation(depth+1, description)
+			g.line(depth, "</xs:attribute>")
+			continue
+		}
+		g.line(depth, `<xs:attribute name="%s"%s>`, f.XmlName(), use)
+		g.documentation(depth+1, description)
+		g.line(depth+1, "<xs:simpleType>")
+		g.restriction(depth+2, asType, values)
+		g.line(depth+1, "</xs:simpleType>")
+		g.line(depth, "</xs:attribute>")
+	}
+}
+
+// restriction writes restriction of the data type to the enumerated values
+func (g *generator) restriction(depth int, asType parser.AsType, values []string) {
+	if len(values) == 0 {
+		g.line(depth, `<xs:restriction base="%s"/>`, g.datatype(asType))
+		return
+	}
+	g.line(depth, `<xs:restriction base="%s">`, g.datatype(asType))
+	for _, v := range values {
+		g.line(depth+1, `<xs:enumeration value="%s"/>`, escape(v))
+	}
+	g.line(depth, "</xs:restriction>")
+}
+
+// datatype returns name of the simple type of the data type
+func (g *generator) datatype(asType parser.AsType) string {
+	if asType == "" {
+		asType = parser.AsTypeString
+	}
+	asType = asType.Canonical()
+	if _, ok := datatypes[asType]; !ok {
+		g.fail(fmt.Errorf("Unknown as-type='%s' found in metaschema '%s'", asType, g.metaschema.GoPackageName()))
+		return "xs:string"
+	}
+	g.datatypes[asType] = true
+	return asType.DatatypeName()
+}
+
+// allowed returns values permitted by all the allowed-values rules
+func allowed(rules []*parser.AllowedValues) []string {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := rules[0].Values()
+	for _, av := range rules[1:] {
+		var both []string
+		for _, v := range result {
+			for _, other := range av.Values() {
+				if v == other {
+					both = append(both, v)
+					break
+				}
+			}
+		}
+		result = both
+	}
+	return result
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
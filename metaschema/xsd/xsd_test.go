@@ -0,0 +1,132 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/metaschema/metaschema/parser"
+)
+
+const commonMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>common</short-name>
+  <namespace>http://example.com/ns/common</namespace>
+  <define-flag name="id" as-type="NCName"><description>Identifier</description></define-flag>
+  <define-field name="title" as-type="markup-line"><description>A title</description></define-field>
+  <define-field name="link">
+    <description>A link</description>
+    <flag name="href" as-type="uri-reference" required="yes"><description>href</description></flag>
+  </define-field>
+</METASCHEMA>`
+
+const catalogMetaschema = `<METASCHEMA xmlns="http://csrc.nist.gov/ns/oscal/metaschema/1.0">
+  <short-name>catalog</short-name>
+  <namespace>http://example.com/ns/catalog</namespace>
+  <import href="common.xml"/>
+  <define-assembly name="catalog">
+    <description>A catalog &amp; its controls</description>
+    <root-name>catalog</root-name>
+    <flag ref="id" required="yes"/>
+    <model>
+      <field ref="title" required="yes"/>
+      <assembly ref="control" max-occurs="unbounded"><group-as name="controls" in-xml="GROUPED"/></assembly>
+      <choice>
+        <field ref="link" max-occurs="unbounded"><group-as name="links"/></field>
+        <field ref="note"/>
+      </choice>
+    </model>
+  </define-assembly>
+  <define-assembly name="control">
+    <description>A control</description>
+    <flag ref="id" required="yes"/>
+    <flag name="class" as-type="NCName"><description>class</description>
+      <constraint>
+        <allowed-values allow-other="no"><enum value="basic">Basic</enum><enum value="enhanced">Enhanced</enum></allowed-values>
+      </constraint>
+    </flag>
+    <model>
+      <field ref="title"/>
+      <field ref="prose" in-xml="UNWRAPPED"/>
+    </model>
+  </define-assembly>
+  <define-field name="note"><description>A note</description></define-field>
+  <define-field name="prose" as-type="markup-multiline"><description>Prose</description></define-field>
+</METASCHEMA>`
+
+func compile(t *testing.T, name, src string, imports ...*parser.Metaschema) *parser.Metaschema {
+	t.Helper()
+	meta := &parser.Metaschema{URI: "file:///" + name, ImportedMetaschema: imports}
+	if err := xml.Unmarshal([]byte(src), meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return meta
+}
+
+// generate returns the schema of the metaschema after checking that it is
+// well-formed XML
+func generate(t *testing.T, meta *parser.Metaschema) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Generate(meta, &buf); err != nil {
+		t.Fatal(err)
+	}
+	d := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("schema of %s is not well-formed: %v\n%s", meta.GoPackageName(), err, buf.String())
+		}
+	}
+	return buf.String()
+}
+
+func TestGenerate(t *testing.T) {
+	common := compile(t, "common.xml", commonMetaschema)
+	catalog := compile(t, "catalog.xml", catalogMetaschema, common)
+	tests := []struct {
+		meta *parser.Metaschema
+		want []string
+	}{
+		{common, []string{
+			`targetNamespace="http://example.com/ns/common" xmlns="http://example.com/ns/common" elementFormDefault="qualified">`,
+			`<xs:element name="title" type="title-FIELD"/>`,
+			`<xs:attribute name="href" type="UriReferenceDatatype" use="required">`,
+		}},
+		{catalog, []string{
+			`xmlns:common="http://example.com/ns/common"`,
+			`<xs:import namespace="http://example.com/ns/common" schemaLocation="common_schema.xsd"/>`,
+			`<xs:documentation>A catalog &amp; its controls</xs:documentation>`,
+			`<xs:element ref="common:title"/>`,
+			`<xs:attribute name="id" type="NCNameDatatype" use="required">`,
+			// GROUPED adds the wrapper element
+			`<xs:element name="controls" minOccurs="0">`,
+			`<xs:element ref="control" maxOccurs="unbounded"/>`,
+			`<xs:choice>
+        <xs:element ref="common:link" maxOccurs="unbounded"/>
+        <xs:element ref="note"/>
+      </xs:choice>`,
+			`<xs:enumeration value="enhanced"/>`,
+			// blocks of unwrapped markup are placed within the parent
+			`<xs:element ref="common:title" minOccurs="0"/>
+      <xs:group ref="block-markup" minOccurs="0" maxOccurs="unbounded"/>`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.meta.GoPackageName(), func(t *testing.T) {
+			schema := generate(t, tt.meta)
+			for _, want := range tt.want {
+				if !strings.Contains(schema, want) {
+					t.Errorf("schema is missing %s:\n%s", want, schema)
+				}
+			}
+		})
+	}
+}